@@ -0,0 +1,78 @@
+package main
+
+import (
+	"github.com/panoptescloud/orca/internal/controller"
+	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/spf13/cobra"
+)
+
+func handleDataSnapshot(cmd *cobra.Command, args []string) error {
+	ctrl := svcContainer.GetController()
+
+	ws, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+	project, err := cmd.Flags().GetString("project")
+	cobra.CheckErr(err)
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	return ctrl.DataSnapshot(cmd.Context(), controller.DataSnapshotDTO{
+		Workspace: ws,
+		Project:   project,
+		Name:      name,
+		Force:     force,
+	})
+}
+
+func handleDataRestore(cmd *cobra.Command, args []string) error {
+	ctrl := svcContainer.GetController()
+
+	ws, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+	project, err := cmd.Flags().GetString("project")
+	cobra.CheckErr(err)
+	autoConfirm, err := cmd.Flags().GetBool("yes")
+	cobra.CheckErr(err)
+
+	return ctrl.DataRestore(cmd.Context(), controller.DataRestoreDTO{
+		Workspace:        ws,
+		Project:          project,
+		Name:             args[0],
+		SkipConfirmation: autoConfirm,
+		Interactive:      hostsys.IsInteractive(),
+	})
+}
+
+func handleDataReset(cmd *cobra.Command, args []string) error {
+	ctrl := svcContainer.GetController()
+
+	ws, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+	project, err := cmd.Flags().GetString("project")
+	cobra.CheckErr(err)
+	autoConfirm, err := cmd.Flags().GetBool("yes")
+	cobra.CheckErr(err)
+
+	return ctrl.DataReset(cmd.Context(), controller.DataResetDTO{
+		Workspace:        ws,
+		Project:          project,
+		SkipConfirmation: autoConfirm,
+		Interactive:      hostsys.IsInteractive(),
+	})
+}
+
+func handleDataLs(cmd *cobra.Command, args []string) error {
+	ctrl := svcContainer.GetController()
+
+	ws, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+
+	return ctrl.DataLs(controller.DataLsDTO{
+		Workspace: ws,
+	})
+}
@@ -13,7 +13,7 @@ func handleDebugShowComposeConfig(cmd *cobra.Command, args []string) error {
 	project, err := cmd.Flags().GetString("project")
 	cobra.CheckErr(err)
 
-	return ctrl.ShowComposeConfig(controller.ShowComposeConfigDTO{
+	return ctrl.ShowComposeConfig(cmd.Context(), controller.ShowComposeConfigDTO{
 		Workspace: ws,
 		Project:   project,
 	})
@@ -32,3 +32,33 @@ func handleDebugShowComposeCommand(cmd *cobra.Command, args []string) error {
 		Project:   project,
 	})
 }
+
+func handleDebugShowConfig(cmd *cobra.Command, args []string) error {
+	ctrl := svcContainer.GetController()
+
+	ws, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+	project, err := cmd.Flags().GetString("project")
+	cobra.CheckErr(err)
+
+	return ctrl.ShowConfig(controller.ShowConfigDTO{
+		Workspace: ws,
+		Project:   project,
+	})
+}
+
+func handleDebugContext(cmd *cobra.Command, args []string) error {
+	ctrl := svcContainer.GetController()
+
+	ws, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+	project, err := cmd.Flags().GetString("project")
+	cobra.CheckErr(err)
+
+	return ctrl.DebugContext(controller.DebugContextDTO{
+		Workspace:        ws,
+		Project:          project,
+		WorkspaceFromEnv: isFromEnv(cmd, "workspace"),
+		ProjectFromEnv:   isFromEnv(cmd, "project"),
+	})
+}
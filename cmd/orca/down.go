@@ -12,9 +12,15 @@ func handleDown(cmd *cobra.Command, args []string) error {
 	cobra.CheckErr(err)
 	project, err := cmd.Flags().GetString("project")
 	cobra.CheckErr(err)
+	profile, err := cmd.Flags().GetString("profile")
+	cobra.CheckErr(err)
+	withDependants, err := cmd.Flags().GetBool("with-dependants")
+	cobra.CheckErr(err)
 
-	return ctrl.Down(controller.DownDTO{
-		Workspace: ws,
-		Project:   project,
+	return ctrl.Down(cmd.Context(), controller.DownDTO{
+		Workspace:      ws,
+		Project:        project,
+		Profile:        profile,
+		WithDependants: withDependants,
 	})
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/panoptescloud/orca/internal/controller"
+	"github.com/panoptescloud/orca/internal/hostsys"
 	"github.com/spf13/cobra"
 )
 
@@ -14,11 +15,27 @@ func handleExec(cmd *cobra.Command, args []string) error {
 	cobra.CheckErr(err)
 	service, err := cmd.Flags().GetString("service")
 	cobra.CheckErr(err)
+	user, err := cmd.Flags().GetString("user")
+	cobra.CheckErr(err)
+	workdir, err := cmd.Flags().GetString("workdir")
+	cobra.CheckErr(err)
+	env, err := cmd.Flags().GetStringArray("env")
+	cobra.CheckErr(err)
+	index, err := cmd.Flags().GetInt("index")
+	cobra.CheckErr(err)
+	noRunFallback, err := cmd.Flags().GetBool("no-run-fallback")
+	cobra.CheckErr(err)
 
-	return ctrl.ExecOrRun(controller.ExecDTO{
-		Workspace: ws,
-		Project:   project,
-		Service:   service,
-		Args:      args,
+	return ctrl.ExecOrRun(cmd.Context(), controller.ExecDTO{
+		Workspace:     ws,
+		Project:       project,
+		Service:       service,
+		Args:          args,
+		User:          user,
+		Workdir:       workdir,
+		Env:           env,
+		Index:         index,
+		TTY:           hostsys.IsInteractive(),
+		NoRunFallback: noRunFallback,
 	})
 }
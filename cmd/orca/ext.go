@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/panoptescloud/orca/internal/controller"
+	"github.com/panoptescloud/orca/internal/hostsys"
 	"github.com/spf13/cobra"
 )
 
@@ -17,10 +18,11 @@ func handleExt(cmd *cobra.Command, args []string) error {
 	extensionName := args[0]
 	extensionArgs := args[1:]
 
-	return ctrl.ExecuteExtension(controller.ExecuteExtensionDTO{
+	return ctrl.ExecuteExtension(cmd.Context(), controller.ExecuteExtensionDTO{
 		Workspace: ws,
 		Project:   project,
 		Name:      extensionName,
 		Args:      extensionArgs,
+		TTY:       hostsys.IsInteractive(),
 	})
 }
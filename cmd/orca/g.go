@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/panoptescloud/orca/internal/git"
+	"github.com/panoptescloud/orca/internal/workspaces"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +15,23 @@ func handleGCo(cmd *cobra.Command, args []string) error {
 		searchTerm = args[0]
 	}
 
-	branch, err := g.Checkout(git.CheckoutDTO{
+	shouldPull, err := cmd.Flags().GetBool("pull")
+	cobra.CheckErr(err)
+	all, err := cmd.Flags().GetBool("all")
+	cobra.CheckErr(err)
+
+	if all {
+		ws, err := cmd.Flags().GetString("workspace")
+		cobra.CheckErr(err)
+
+		return svcContainer.GetWorkspaceManager().CheckoutAll(cmd.Context(), workspaces.GitCheckoutAllDTO{
+			WorkspaceName: ws,
+			Branch:        searchTerm,
+			Pull:          shouldPull,
+		})
+	}
+
+	branch, err := g.Checkout(cmd.Context(), git.CheckoutDTO{
 		Name: searchTerm,
 	})
 
@@ -22,11 +39,8 @@ func handleGCo(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	shouldPull, err := cmd.Flags().GetBool("pull")
-	cobra.CheckErr(err)
-
 	if shouldPull {
-		return g.PullBranch(git.PullBranchDTO{
+		return g.PullBranch(cmd.Context(), git.PullBranchDTO{
 			Name: branch,
 		})
 	}
@@ -43,7 +57,7 @@ func handleGBranches(cmd *cobra.Command, args []string) error {
 		searchTerm = args[0]
 	}
 
-	return g.ShowBranches(git.SearchBranchesDTO{
+	return g.ShowBranches(cmd.Context(), git.SearchBranchesDTO{
 		Search: searchTerm,
 	})
 }
@@ -54,7 +68,7 @@ func handleGRebaseInteractively(cmd *cobra.Command, args []string) error {
 	amount, err := cmd.Flags().GetInt("number")
 	cobra.CheckErr(err)
 
-	return g.RebaseInteractively(git.RebaseInteractivelyDTO{
+	return g.RebaseInteractively(cmd.Context(), git.RebaseInteractivelyDTO{
 		Amount: amount,
 	})
 }
@@ -65,7 +79,7 @@ func handleGPush(cmd *cobra.Command, args []string) error {
 	force, err := cmd.Flags().GetBool("force")
 	cobra.CheckErr(err)
 
-	return g.Push(git.PushDTO{
+	return g.Push(cmd.Context(), git.PushDTO{
 		Force: force,
 	})
 }
@@ -79,7 +93,7 @@ func handleGUndo(cmd *cobra.Command, args []string) error {
 	amount, err := cmd.Flags().GetInt("number")
 	cobra.CheckErr(err)
 
-	return g.UndoLastXCommits(git.UndoLastXCommitsDTO{
+	return g.UndoLastXCommits(cmd.Context(), git.UndoLastXCommitsDTO{
 		Amount:           amount,
 		SkipConfirmation: autoConfirm,
 	})
@@ -91,13 +105,41 @@ func handleGLogl(cmd *cobra.Command, args []string) error {
 	amount, err := cmd.Flags().GetInt("number")
 	cobra.CheckErr(err)
 
-	return g.Logl(git.LoglDTO{
+	return g.Logl(cmd.Context(), git.LoglDTO{
 		Amount: amount,
 	})
 }
 
 func handleGPull(cmd *cobra.Command, args []string) error {
+	all, err := cmd.Flags().GetBool("all")
+	cobra.CheckErr(err)
+
+	if all {
+		ws, err := cmd.Flags().GetString("workspace")
+		cobra.CheckErr(err)
+
+		return svcContainer.GetWorkspaceManager().PullAll(cmd.Context(), workspaces.GitAllDTO{
+			WorkspaceName: ws,
+		})
+	}
+
 	g := svcContainer.GetGit()
 
-	return g.PullBranch(git.PullBranchDTO{})
+	return g.PullBranch(cmd.Context(), git.PullBranchDTO{})
+}
+
+func handleGStatus(cmd *cobra.Command, args []string) error {
+	all, err := cmd.Flags().GetBool("all")
+	cobra.CheckErr(err)
+
+	if all {
+		ws, err := cmd.Flags().GetString("workspace")
+		cobra.CheckErr(err)
+
+		return svcContainer.GetWorkspaceManager().StatusAll(cmd.Context(), workspaces.GitAllDTO{
+			WorkspaceName: ws,
+		})
+	}
+
+	return svcContainer.GetGit().Status(cmd.Context())
 }
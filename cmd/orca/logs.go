@@ -1,7 +1,11 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/panoptescloud/orca/internal/controller"
+	"github.com/panoptescloud/orca/internal/hostsys"
 	"github.com/spf13/cobra"
 )
 
@@ -12,12 +16,86 @@ func handleLogs(cmd *cobra.Command, args []string) error {
 	cobra.CheckErr(err)
 	project, err := cmd.Flags().GetString("project")
 	cobra.CheckErr(err)
+	profile, err := cmd.Flags().GetString("profile")
+	cobra.CheckErr(err)
 	service, err := cmd.Flags().GetString("service")
 	cobra.CheckErr(err)
+	since, err := cmd.Flags().GetString("since")
+	cobra.CheckErr(err)
+	grep, err := cmd.Flags().GetString("grep")
+	cobra.CheckErr(err)
+	noFollow, err := cmd.Flags().GetBool("no-follow")
+	cobra.CheckErr(err)
+	run, err := cmd.Flags().GetString("run")
+	cobra.CheckErr(err)
 
-	return ctrl.Logs(controller.LogsDTO{
+	return ctrl.Logs(cmd.Context(), controller.LogsDTO{
 		Workspace: ws,
 		Project:   project,
+		Profile:   profile,
 		Service:   service,
+		Since:     since,
+		Grep:      grep,
+		NoFollow:  noFollow,
+		Run:       run,
+	})
+}
+
+func handleLogsRecord(cmd *cobra.Command, args []string) error {
+	ctrl := svcContainer.GetController()
+
+	ws, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+	project, err := cmd.Flags().GetString("project")
+	cobra.CheckErr(err)
+	profile, err := cmd.Flags().GetString("profile")
+	cobra.CheckErr(err)
+	run, err := cmd.Flags().GetString("run")
+	cobra.CheckErr(err)
+
+	return ctrl.RecordLogs(cmd.Context(), controller.RecordLogsDTO{
+		Workspace: ws,
+		Project:   project,
+		Profile:   profile,
+		Run:       run,
 	})
 }
+
+// startLogRecorder runs 'logs record' in the background, for the same
+// workspace, project and profile as the command. Its own output goes to
+// recorder.log in the logs dir, in case it fails.
+func startLogRecorder(cmd *cobra.Command) (string, error) {
+	run := svcContainer.GetLogStore().NewRunID()
+	args := []string{"logs", "record", "--run", run}
+
+	for _, name := range []string{"workspace", "project", "profile"} {
+		value, err := cmd.Flags().GetString(name)
+		cobra.CheckErr(err)
+
+		if value != "" {
+			args = append(args, "--"+name, value)
+		}
+	}
+
+	self, err := os.Executable()
+
+	if err != nil {
+		return "", err
+	}
+
+	fs := svcContainer.GetFs()
+
+	if err := fs.MkdirAll(getLogsDir(), 0755); err != nil {
+		return "", err
+	}
+
+	out, err := fs.OpenFile(filepath.Join(getLogsDir(), "recorder.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer out.Close()
+
+	return run, svcContainer.GetExecutor().StartDetached(self, args, hostsys.WithOutputTo(out))
+}
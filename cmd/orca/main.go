@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path"
 	"strings"
+	"syscall"
 
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/controller"
 	"github.com/panoptescloud/orca/internal/hostsys"
 	"github.com/panoptescloud/orca/internal/logging"
 	"github.com/panoptescloud/orca/internal/workspaces"
@@ -35,7 +40,8 @@ var rootCmd = &cobra.Command{
 	Long: `This can be used to manage multiple projects in different repositories
 so that they can be started/stopped as a singular unit, and provides common
 utilities to interact with services form anywhere on the host.`,
-	RunE: handleGroup,
+	RunE:              handleGroup,
+	PersistentPreRunE: applyEnvOptions,
 }
 
 var versionCmd = &cobra.Command{
@@ -54,7 +60,10 @@ var gCoCmd = &cobra.Command{
 	Use:   "co",
 	Short: "Checkout a branch for a git repository.",
 	Long: `Searches for a branch with the name provided as an argument. If a single branch is
-found, it will be checked out. If multiple are found will provide a list to select from.`,
+found, it will be checked out. If multiple are found will provide a list to select from.
+
+With --all, the exact branch is checked out in every cloned project of the workspace
+that has it, either locally or on origin.`,
 	Run: errorHandlerWrapper(handleGCo, 1),
 }
 
@@ -69,10 +78,18 @@ If no search term is given, will list all branches.`,
 var gPullCmd = &cobra.Command{
 	Use:   "pull",
 	Short: "Pulls a branch from origin.",
-	Long:  `Will pull the currently checked out branch`,
+	Long:  `Will pull the currently checked out branch. With --all, fast-forwards the current branch of every cloned project in the workspace.`,
 	Run:   errorHandlerWrapper(handleGPull, 1),
 }
 
+var gStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the status of the git repository.",
+	Long: `With --all, shows the branch, commits ahead/behind the upstream, and whether there
+are uncommitted changes for every cloned project in the workspace.`,
+	Run: errorHandlerWrapper(handleGStatus, 1),
+}
+
 var gRbiCmd = &cobra.Command{
 	Use:   "rbi",
 	Short: "Run an interactive rebase.",
@@ -113,6 +130,12 @@ var utilGenDocsCmd = &cobra.Command{
 	Run:   errorHandlerWrapper(handleUtilGenDocs, 1),
 }
 
+var utilGenSchemasCmd = &cobra.Command{
+	Use:   "gen-schemas",
+	Short: "Generates JSON schemas for the workspace and project config files.",
+	Run:   errorHandlerWrapper(handleUtilGenSchemas, 1),
+}
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Commands for managing configuration",
@@ -138,24 +161,41 @@ var wsCmd = &cobra.Command{
 }
 
 var wsSwitchCmd = &cobra.Command{
-	Use:   "switch",
+	Use:   "switch [workspace]",
 	Short: "Switch to another workspace",
+	Long:  `Switch to another workspace. If no workspace is given, you'll be asked to choose one.`,
+	Args:  cobra.MaximumNArgs(1),
 	Run:   errorHandlerWrapper(handleWsSwitch, 1),
 }
 
 var wsInitCmd = &cobra.Command{
-	Use:   "init",
+	Use:   "init [git url]",
 	Short: "Initialises a workspace.",
 	Long: `Initialises a new workspace with orca. Either a local directory or a git repository can be supplied.
-When using a local directory, will locate an orca workspace config and clone the relevant projects.
-When using a git url, will clone the given repository, and then clone any other required repositories based on an orca workspace file within the repo.`,
-	Run: errorHandlerWrapper(handleWsInit, 1),
+When using a local directory, will locate an orca workspace config and register it.
+When using a git url, will clone the given repository into the target, and then locate the orca workspace config within the repo.
+
+Use --clone to also clone all of the projects in the workspace, and --switch to make it the current workspace.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  errorHandlerWrapper(handleWsInit, 1),
 }
 
 var wsLsCmd = &cobra.Command{
 	Use:   "ls",
 	Short: "Lists all available workspaces.",
-	Run:   errorHandlerWrapper(handleWsLs, 1),
+	Long: `Lists all available workspaces, with the current one marked by '*'. For each workspace the
+number of cloned projects is shown against the number in its config, along with any problem
+finding the config.`,
+	Run: errorHandlerWrapper(handleWsLs, 1),
+}
+
+var wsShowCmd = &cobra.Command{
+	Use:   "show [workspace]",
+	Short: "Shows the details of a workspace.",
+	Long: `Shows the projects in the workspace, with their paths, repositories and requirements, along
+with the overlay settings and profiles. Defaults to the current workspace.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  errorHandlerWrapper(handleWsShow, 1),
 }
 
 var wsCloneCmd = &cobra.Command{
@@ -163,10 +203,78 @@ var wsCloneCmd = &cobra.Command{
 	Short: "Clones all the projects required for this workspace.",
 	Long: `Based on the workspace config will clone each repository required by the workspace.
 This can be run at any time to clone any projects that have not already been cloned.
-The project option allows you to clone only a specific project.`,
+The project option allows you to clone only a specific project.
+
+Projects are cloned in parallel, with a summary of what was cloned, skipped or failed
+at the end.`,
 	Run: errorHandlerWrapper(handleWsClone, 1),
 }
 
+var wsValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validates the workspace config, and the config of each cloned project.",
+	Long: `Checks for unknown or mistyped fields, projects that are required but not defined,
+duplicate project names, dependency cycles and other problems that would otherwise
+only be found at runtime (if at all). This validation also runs whenever a workspace
+is loaded, this command just reports every problem at once.`,
+	Run: errorHandlerWrapper(handleWsValidate, 1),
+}
+
+var wsDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks everything the workspace needs to run, and suggests fixes for any problems.",
+	Long: `Checks that the required tools are installed, the config is valid, every project is cloned
+on the expected branch with valid compose and env files, and that the overlay network, TLS
+certificates and host entries the workspace needs are in place.`,
+	Run: errorHandlerWrapper(handleWsDoctor, 1),
+}
+
+var wsRmCmd = &cobra.Command{
+	Use:   "rm <workspace>",
+	Short: "Removes a workspace from orca.",
+	Long: `Removes the workspace, and the paths of its projects, from the orca config. The projects
+themselves are left on disk.
+
+Use --down to stop the workspace first, and --clean to remove the overlays and any certificates
+that are not used by another workspace.`,
+	Args: cobra.ExactArgs(1),
+	Run:  errorHandlerWrapper(handleWsRm, 1),
+}
+
+var wsRenameCmd = &cobra.Command{
+	Use:   "rename <workspace> <new name>",
+	Short: "Changes the name a workspace is registered under.",
+	Long: `Changes the name a workspace is registered under, the workspace config is not changed.
+As the name is used for the compose projects, the workspace must be stopped first. The volumes
+compose created are also named after it, so are left behind, orca lists any that are.`,
+	Args: cobra.ExactArgs(2),
+	Run:  errorHandlerWrapper(handleWsRename, 1),
+}
+
+var wsRelocateCmd = &cobra.Command{
+	Use:   "relocate <path>",
+	Short: "Updates where the workspace config is found.",
+	Long: `Updates where the workspace config is found, e.g. after the repository containing it has
+been moved. The path can either be the workspace config, or the directory containing it.`,
+	Args: cobra.ExactArgs(1),
+	Run:  errorHandlerWrapper(handleWsRelocate, 1),
+}
+
+var wsProjectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Commands related to managing the projects in a workspace.",
+	RunE:  handleGroup,
+}
+
+var wsProjectSetPathCmd = &cobra.Command{
+	Use:   "set-path <project> <path>",
+	Short: "Updates where a project is found.",
+	Long: `Updates where a project is found, e.g. after it has been moved, or if it was cloned
+without orca. The path must contain the project config.`,
+	Args: cobra.ExactArgs(2),
+	Run:  errorHandlerWrapper(handleWsProjectSetPath, 1),
+}
+
 var sysCmd = &cobra.Command{
 	Use:   "sys",
 	Short: "Commands for handling the installation of this tool.",
@@ -222,8 +330,17 @@ var restartCmd = &cobra.Command{
 var execCmd = &cobra.Command{
 	Use:   "exec",
 	Short: "Runs a command inside one of the containers in the environment",
-	Long:  `...TBD...`,
-	Run:   errorHandlerWrapper(handleExec, 1),
+	Long: `Runs the command in the service's container if it's running, otherwise in a new one 
+(like 'compose run --rm'). Put the command after '--' if it has flags of its own, e.g.
+
+  orca exec -s php -- php artisan migrate --force
+
+A TTY is only allocated when orca is run from a terminal, so input can be piped in:
+
+  orca exec -s db -- psql < dump.sql
+
+The exit code of the command becomes orca's exit code.`,
+	Run: errorHandlerWrapper(handleExec, 1),
 }
 
 var tlsCmd = &cobra.Command{
@@ -239,6 +356,18 @@ If no root certificate has been generated, one will be generated.`,
 	Run: errorHandlerWrapper(handleTLSGen, 1),
 }
 
+var tlsAcmeServerCmd = &cobra.Command{
+	Use:   "acme-server",
+	Short: "Runs a local ACME server that issues certificates from the orca root certificate.",
+	Long: `Starts a minimal ACME v2 server over HTTPS, for tools that can request their own
+certificates (e.g. Traefik or Caddy). Any name that is listed in the workspace hosts or
+TLS certificates is approved automatically; other names are rejected.
+
+Clients must trust the orca root certificate. Containers can reach the server via
+'host.docker.internal' when listening on an address they can access, e.g. 0.0.0.0:14000.`,
+	Run: errorHandlerWrapper(handleTLSAcmeServer, 1),
+}
+
 var debugCmd = &cobra.Command{
 	Use:   "debug",
 	Short: "Commands to aid in debugging or understanding whats happening.",
@@ -261,11 +390,98 @@ var debugShowComposeCommandCmd = &cobra.Command{
 	Run:   errorHandlerWrapper(handleDebugShowComposeCommand, 1),
 }
 
+var debugShowConfigCmd = &cobra.Command{
+	Use:   "show-config",
+	Short: `Shows the effective workspace or project config.`,
+	Long: `Shows the config after all includes and local overrides (e.g. 
+orca.workspace.local.yaml) are merged together, and variables are resolved. Each 
+value is commented with the file and line it came from.`,
+	Run: errorHandlerWrapper(handleDebugShowConfig, 1),
+}
+
+var debugContextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Shows which workspace and project a command would use, and why.",
+	Long: `Commands use the workspace and project given as options first. Otherwise, the 
+most specific project (or workspace config) directory containing the working 
+directory is used, falling back to the current workspace.`,
+	Args: cobra.NoArgs,
+	Run:  errorHandlerWrapper(handleDebugContext, 1),
+}
+
 var logsCmd = &cobra.Command{
 	Use:   "logs",
-	Short: `Tails logs from docker compose project.`,
-	Long:  `Basically a 'docker compose logs -f', you may supply a service to tail specifically.`,
-	Run:   errorHandlerWrapper(handleLogs, 1),
+	Short: `Tails logs from a project, profile or the whole workspace.`,
+	Long: `Basically a 'docker compose logs -f', but across every project at once. Each line is 
+prefixed with the project and service it came from. Outside of a project, all of the 
+workspace's cloned projects are included.
+
+Logs recorded with 'orca up --record-logs' can be replayed with --run, giving either the 
+id of the run or 'latest'.`,
+	Run: errorHandlerWrapper(handleLogs, 1),
+}
+
+// logsRecordCmd is started in the background by 'up --record-logs', it's not
+// meant to be run directly.
+var logsRecordCmd = &cobra.Command{
+	Use:    "record",
+	Short:  "Records logs to disk until the containers stop.",
+	Args:   cobra.NoArgs,
+	Hidden: true,
+	Run:    errorHandlerWrapper(handleLogsRecord, 1),
+}
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Prints the workspace and project for the current directory, for use in a shell prompt.",
+	Long: `Prints the workspace, and project, that commands would run against from the current directory.
+ORCA_WORKSPACE and ORCA_PROJECT are used first, as they are for other commands. Only the orca config is read, so it's quick enough to use in a shell prompt, e.g. for bash:
+
+  PS1='$(orca prompt --format "[{{.Workspace}}] ") '"$PS1"
+
+Nothing is printed when there's no workspace.`,
+	Run: errorHandlerWrapper(handlePrompt, 1),
+}
+
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Commands to snapshot, restore and reset the data in a project's volumes.",
+	Long: `Manages the named volumes in the compose files of a project, or of every cloned project in
+the workspace. The projects must be stopped first, e.g. with 'orca down'.`,
+	RunE: handleGroup,
+}
+
+var dataSnapshotCmd = &cobra.Command{
+	Use:   "snapshot [name]",
+	Short: "Saves the contents of the volumes, so they can be restored later.",
+	Long: `Saves the contents of the named volumes in ~/.orca/snapshots/<workspace>/<name>, the name
+defaults to the current date and time. Volumes that haven't been created yet are skipped, and
+removed again when the snapshot is restored.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  errorHandlerWrapper(handleDataSnapshot, 1),
+}
+
+var dataRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Replaces the contents of the volumes with those in a snapshot.",
+	Long: `Replaces the contents of the named volumes with those saved in the snapshot. Projects that
+aren't in the snapshot are left alone.`,
+	Args: cobra.ExactArgs(1),
+	Run:  errorHandlerWrapper(handleDataRestore, 1),
+}
+
+var dataResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Removes the volumes, so the projects start from scratch.",
+	Args:  cobra.NoArgs,
+	Run:   errorHandlerWrapper(handleDataReset, 1),
+}
+
+var dataLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "Lists the snapshots of the workspace.",
+	Args:  cobra.NoArgs,
+	Run:   errorHandlerWrapper(handleDataLs, 1),
 }
 
 var hostsCmd = &cobra.Command{
@@ -288,11 +504,21 @@ func errorHandlerWrapper(f runEHandlerFunc, errorExitCode int) runHandlerFunc {
 			// Set as a debug level here as it should already be logged earlier
 			// in the stack
 			slog.Debug("unhandled error", "err", err)
-			os.Exit(errorExitCode)
+			os.Exit(exitCodeFor(err, errorExitCode))
 		}
 	}
 }
 
+// exitCodeFor lets scripts tell why a command failed, see common.ExitCode.
+// The fallback is used for anything without a more specific exit code.
+func exitCodeFor(err error, fallback int) int {
+	if code := common.ExitCode(err); code != common.ExitCodeFailure {
+		return code
+	}
+
+	return fallback
+}
+
 func handleGroup(cmd *cobra.Command, _ []string) error {
 	return cmd.Help()
 }
@@ -326,6 +552,20 @@ func getTLSDir() string {
 	return dir
 }
 
+func getLogsDir() string {
+	homeDir, err := os.UserHomeDir()
+	cobra.CheckErr(err)
+
+	return fmt.Sprintf("%s/.orca/logs", homeDir)
+}
+
+func getSnapshotsDir() string {
+	homeDir, err := os.UserHomeDir()
+	cobra.CheckErr(err)
+
+	return fmt.Sprintf("%s/.orca/snapshots", homeDir)
+}
+
 func getConfigFilePath() string {
 	homeDir, err := os.UserHomeDir()
 	cobra.CheckErr(err)
@@ -370,6 +610,7 @@ func init() {
 	// Persistent flags
 	rootCmd.PersistentFlags().String("log-level", cfg.GetLoggingLevel(), "Log level to use, one of: debug, info, warn, error, none. Defaults to none, as most errors are already surfaced anyway.")
 	rootCmd.PersistentFlags().String("log-format", cfg.GetLoggingFormat(), "log format to use")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Print the commands that would be run, and the files that would be changed, without doing either.")
 
 	cobra.CheckErr(viper.BindPFlag("logging.level", rootCmd.PersistentFlags().Lookup("log-level")))
 	cobra.CheckErr(viper.BindPFlag("logging.format", rootCmd.PersistentFlags().Lookup("log-format")))
@@ -388,6 +629,7 @@ func init() {
 
 	// Git
 	gCoCmd.Flags().BoolP("pull", "p", false, "Pulls the branch from origin after checking it out.")
+	addAllProjectsOption(gCoCmd)
 	gCmd.AddCommand(gCoCmd)
 
 	gCmd.AddCommand(gBranchesCmd)
@@ -405,12 +647,17 @@ func init() {
 	gLoglCmd.Flags().IntP("number", "n", 10, "The number of commits to remove from the branch.")
 	gCmd.AddCommand(gLoglCmd)
 
+	addAllProjectsOption(gPullCmd)
 	gCmd.AddCommand(gPullCmd)
 
+	addAllProjectsOption(gStatusCmd)
+	gCmd.AddCommand(gStatusCmd)
+
 	rootCmd.AddCommand(gCmd)
 
 	// Utils
 	utilCmd.AddCommand(utilGenDocsCmd)
+	utilCmd.AddCommand(utilGenSchemasCmd)
 
 	rootCmd.AddCommand(utilCmd)
 
@@ -423,43 +670,81 @@ func init() {
 	wsCmd.AddCommand(wsSwitchCmd)
 
 	wsInitCmd.Flags().StringP("source", "s", getWorkingDir(), "The directory of the workspace configuration.")
-	wsInitCmd.Flags().StringP("target", "t", getWorkingDirParent(), "The directory to store the workspace projects.")
+	wsInitCmd.Flags().StringP("target", "t", getWorkingDirParent(), "The directory to store the workspace projects. When a git url is given, defaults to the current directory.")
 	wsInitCmd.Flags().StringP("config", "c", workspaces.DefaultWorkspaceFileName, "The name of the workspace config file within the source.")
+	wsInitCmd.Flags().Bool("clone", false, "Clone all projects in the workspace after registering it.")
+	wsInitCmd.Flags().Bool("switch", false, "Switch to the workspace after registering it.")
 	wsCmd.AddCommand(wsInitCmd)
 
+	wsLsCmd.Flags().Bool("status", false, "Also show how many projects in each workspace are running.")
 	wsCmd.AddCommand(wsLsCmd)
 
+	wsCmd.AddCommand(wsShowCmd)
+
 	wsCloneCmd.Flags().StringP("target", "t", "", `The directory in which to clone the project(s). 
 If multiple projects are being cloned, then it will place them in {target}/{repo name}.
 If a single project is being clone then it will be cloned into {target}.`)
 	addWorkspaceOption(wsCloneCmd, false)
 	addProjectOption(wsCloneCmd)
+	wsCloneCmd.Flags().Int("depth", 0, "Create shallow clones with this many commits of history.")
+	wsCloneCmd.Flags().String("filter", "", "Create partial clones using this filter, e.g. 'blob:none'.")
+	wsCloneCmd.Flags().Int("retry-failed", 0, "The number of times to retry projects that failed to clone (2 if given without a value).")
+	wsCloneCmd.Flags().Lookup("retry-failed").NoOptDefVal = "2"
 	wsCloneCmd.MarkFlagRequired("workspace")
 	wsCmd.AddCommand(wsCloneCmd)
 
+	addWorkspaceOption(wsValidateCmd, false)
+	wsCmd.AddCommand(wsValidateCmd)
+
+	addWorkspaceOption(wsDoctorCmd, false)
+	wsCmd.AddCommand(wsDoctorCmd)
+
+	wsRmCmd.Flags().Bool("down", false, "Stop all projects in the workspace before removing it.")
+	wsRmCmd.Flags().Bool("clean", false, "Remove the overlays, and any certificates not used by another workspace.")
+	wsCmd.AddCommand(wsRmCmd)
+
+	wsCmd.AddCommand(wsRenameCmd)
+
+	addWorkspaceOption(wsRelocateCmd, false)
+	wsCmd.AddCommand(wsRelocateCmd)
+
+	addWorkspaceOption(wsProjectSetPathCmd, false)
+	wsProjectCmd.AddCommand(wsProjectSetPathCmd)
+	wsCmd.AddCommand(wsProjectCmd)
+
 	rootCmd.AddCommand(wsCmd)
 
 	// up
 	addWorkspaceOption(upCmd, false)
 	addProjectOption(upCmd)
+	addProfileOption(upCmd)
+	upCmd.Flags().Bool("with-deps", false, "Also start every project the project requires, skipping any that are already running.")
+	upCmd.Flags().Bool("record-logs", false, "Record the logs of every service to disk in the background, so they can be replayed with 'orca logs --run'.")
 
 	rootCmd.AddCommand(upCmd)
 
 	// down
 	addWorkspaceOption(downCmd, false)
 	addProjectOption(downCmd)
+	addProfileOption(downCmd)
+	downCmd.Flags().Bool("with-dependants", false, "Also stop every project that requires the project, before stopping it.")
 
 	rootCmd.AddCommand(downCmd)
 
 	// restart
 	addWorkspaceOption(restartCmd, false)
 	addProjectOption(restartCmd)
+	addProfileOption(restartCmd)
 
 	rootCmd.AddCommand(restartCmd)
 
 	// tls
 	addWorkspaceOption(tlsGenCmd, false)
 	tlsCmd.AddCommand(tlsGenCmd)
+
+	addWorkspaceOption(tlsAcmeServerCmd, false)
+	tlsAcmeServerCmd.Flags().StringP("address", "a", "127.0.0.1:14000", "The address to listen on.")
+	tlsCmd.AddCommand(tlsAcmeServerCmd)
 	rootCmd.AddCommand(tlsCmd)
 
 	// debug
@@ -470,32 +755,144 @@ If a single project is being clone then it will be cloned into {target}.`)
 	addWorkspaceOption(debugShowComposeCommandCmd, false)
 	addProjectOption(debugShowComposeCommandCmd)
 	debugCmd.AddCommand(debugShowComposeCommandCmd)
+
+	addWorkspaceOption(debugShowConfigCmd, false)
+	addProjectOption(debugShowConfigCmd)
+	debugCmd.AddCommand(debugShowConfigCmd)
+
+	addWorkspaceOption(debugContextCmd, false)
+	addProjectOption(debugContextCmd)
+	debugCmd.AddCommand(debugContextCmd)
 	rootCmd.AddCommand(debugCmd)
 
 	// exec
 	addWorkspaceOption(execCmd, false)
 	addProjectOption(execCmd)
 	addServiceOption(execCmd, true)
+	execCmd.Flags().StringP("user", "u", "", "The user to run the command as.")
+	execCmd.Flags().String("workdir", "", "The directory to run the command in, within the container.")
+	execCmd.Flags().StringArrayP("env", "e", []string{}, "Set an environment variable (KEY=VAL), or pass it through from the host (KEY). Can be given multiple times.")
+	execCmd.Flags().Int("index", 0, "Which container to use, for a service with multiple replicas. The service must be running.")
+	execCmd.Flags().Bool("no-run-fallback", false, "Fail if the service isn't running, rather than running the command in a new container.")
 	rootCmd.AddCommand(execCmd)
 
 	// logs
 	addWorkspaceOption(logsCmd, false)
 	addProjectOption(logsCmd)
+	addProfileOption(logsCmd)
 	addServiceOption(logsCmd, false)
+	logsCmd.Flags().String("since", "", "Only show logs since a timestamp (e.g. 2024-01-02T13:04:05) or relative to now (e.g. 10m).")
+	logsCmd.Flags().String("grep", "", "Only show lines matching the regular expression.")
+	logsCmd.Flags().Bool("no-follow", false, "Show the logs so far, rather than continuing to stream them.")
+	logsCmd.Flags().String("run", "", "Replay the logs recorded during a run, by its id or 'latest'.")
+
+	addWorkspaceOption(logsRecordCmd, false)
+	addProjectOption(logsRecordCmd)
+	addProfileOption(logsRecordCmd)
+	logsRecordCmd.Flags().String("run", "", "The id to record the logs under.")
+	logsRecordCmd.MarkFlagRequired("run")
+	logsCmd.AddCommand(logsRecordCmd)
+
 	rootCmd.AddCommand(logsCmd)
 
+	// data
+	addWorkspaceOption(dataSnapshotCmd, false)
+	addProjectOption(dataSnapshotCmd)
+	dataSnapshotCmd.Flags().Bool("force", false, "Replace an existing snapshot with the same name.")
+	dataCmd.AddCommand(dataSnapshotCmd)
+
+	addWorkspaceOption(dataRestoreCmd, false)
+	addProjectOption(dataRestoreCmd)
+	dataRestoreCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt.")
+	dataCmd.AddCommand(dataRestoreCmd)
+
+	addWorkspaceOption(dataResetCmd, false)
+	addProjectOption(dataResetCmd)
+	dataResetCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt.")
+	dataCmd.AddCommand(dataResetCmd)
+
+	addWorkspaceOption(dataLsCmd, false)
+	dataCmd.AddCommand(dataLsCmd)
+
+	rootCmd.AddCommand(dataCmd)
+
 	// hosts
 	addWorkspaceOption(hostsCmd, true)
 	rootCmd.AddCommand(hostsCmd)
 
+	// prompt
+	promptCmd.Flags().String("format", controller.DefaultPromptFormat, "A go template for the output, given the .Workspace and .Project.")
+	rootCmd.AddCommand(promptCmd)
+
 	// ext
 	addWorkspaceOption(extCmd, false)
 	addProjectOption(extCmd)
 	rootCmd.AddCommand(extCmd)
 }
 
+// envOptions are the options that can also be set from the environment, e.g.
+// by direnv or in CI. They're ordered from the broadest to the narrowest.
+var envOptions = []struct {
+	name string
+	env  string
+}{
+	{name: "workspace", env: controller.WorkspaceEnv},
+	{name: "project", env: controller.ProjectEnv},
+	{name: "service", env: controller.ServiceEnv},
+}
+
+// fromEnvAnnotation marks the options that were set from the environment.
+const fromEnvAnnotation = "orca_from_env"
+
+// applyEnvOptions sets any options that weren't given from the environment.
+// This happens before the required options are checked, so the environment
+// can satisfy them too.
+func applyEnvOptions(cmd *cobra.Command, args []string) error {
+	opts := []controller.EnvOption{}
+
+	for _, opt := range envOptions {
+		f := cmd.Flags().Lookup(opt.name)
+
+		if f == nil {
+			continue
+		}
+
+		opts = append(opts, controller.EnvOption{
+			Name:  opt.name,
+			Env:   opt.env,
+			Given: f.Changed,
+		})
+	}
+
+	for name, value := range controller.ResolveEnvOptions(opts, os.Getenv) {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return err
+		}
+
+		if err := cmd.Flags().SetAnnotation(name, fromEnvAnnotation, []string{"true"}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isFromEnv reports whether the option was set by applyEnvOptions, rather than
+// given on the command line.
+func isFromEnv(cmd *cobra.Command, name string) bool {
+	f := cmd.Flags().Lookup(name)
+
+	if f == nil {
+		return false
+	}
+
+	_, ok := f.Annotations[fromEnvAnnotation]
+
+	return ok
+}
+
 func addServiceOption(cmd *cobra.Command, required bool) {
-	cmd.Flags().StringP("service", "s", "", "The service within the project to exec into")
+	cmd.Flags().StringP("service", "s", "", "The name of the service to run this command for, can also be set with ORCA_SERVICE.")
 
 	if required {
 		cmd.MarkFlagRequired("service")
@@ -503,7 +900,7 @@ func addServiceOption(cmd *cobra.Command, required bool) {
 }
 
 func addWorkspaceOption(cmd *cobra.Command, required bool) {
-	cmd.Flags().StringP("workspace", "w", "", "The name of the workspace to run this command for.")
+	cmd.Flags().StringP("workspace", "w", "", "The name of the workspace to run this command for, can also be set with ORCA_WORKSPACE.")
 
 	if required {
 		cmd.MarkFlagRequired("workspace")
@@ -511,10 +908,32 @@ func addWorkspaceOption(cmd *cobra.Command, required bool) {
 }
 
 func addProjectOption(cmd *cobra.Command) {
-	cmd.Flags().StringP("project", "p", "", "The name of the project within the workspace to run this command for.")
+	cmd.Flags().StringP("project", "p", "", "The name of the project within the workspace to run this command for, can also be set with ORCA_PROJECT.")
+}
+
+// addAllProjectsOption is for git commands that can be run across every
+// project in the workspace at once.
+func addAllProjectsOption(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, "Run for every cloned project in the workspace, in parallel. Repositories with uncommitted changes are skipped.")
+	addWorkspaceOption(cmd, false)
+}
+
+func addProfileOption(cmd *cobra.Command) {
+	cmd.Flags().String("profile", "", "The name of a profile within the workspace, the projects in it are used along with everything they require.")
 }
 
 func bootstrap() {
+	dryRun, err := rootCmd.PersistentFlags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	if dryRun {
+		svcContainer.enableDryRun()
+
+		// The config was loaded before the flags were parsed, so it needs to
+		// be loaded again through the dry run filesystem.
+		cobra.CheckErr(svcContainer.GetConfig().LoadOrCreate())
+	}
+
 	cfg := svcContainer.GetConfig()
 
 	// Tell viper to replace . in nested path with underscores
@@ -524,7 +943,10 @@ func bootstrap() {
 	viper.SetEnvPrefix("orca")
 	viper.AutomaticEnv()
 
-	err := viper.Unmarshal(cfg.GetRuntimeConfig())
+	// Not bound to a flag, so viper needs to be told about it
+	cobra.CheckErr(viper.BindEnv("compose.provider"))
+
+	err = viper.Unmarshal(cfg.GetRuntimeConfig())
 	cobra.CheckErr(err)
 
 	h, err := logging.NewSlogHandler(cfg)
@@ -535,7 +957,23 @@ func bootstrap() {
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	// The first signal cancels the context, giving anything running the
+	// chance to stop cleanly. The signal is kept as the cause, so it's known
+	// whether the terminal has interrupted them already. After that the
+	// default behaviour is restored, so a second one exits immediately.
+	go func() {
+		sig := <-signals
+		signal.Stop(signals)
+		cancel(common.ErrReceivedSignal{Signal: sig})
+	}()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/panoptescloud/orca/internal/controller"
+	"github.com/spf13/cobra"
+)
+
+func handlePrompt(cmd *cobra.Command, args []string) error {
+	ctrl := svcContainer.GetController()
+
+	format, err := cmd.Flags().GetString("format")
+	cobra.CheckErr(err)
+
+	return ctrl.Prompt(controller.PromptDTO{
+		Format: format,
+	})
+}
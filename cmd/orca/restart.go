@@ -12,18 +12,22 @@ func handleRestart(cmd *cobra.Command, args []string) error {
 	cobra.CheckErr(err)
 	project, err := cmd.Flags().GetString("project")
 	cobra.CheckErr(err)
+	profile, err := cmd.Flags().GetString("profile")
+	cobra.CheckErr(err)
 
-	err = ctrl.Down(controller.DownDTO{
+	err = ctrl.Down(cmd.Context(), controller.DownDTO{
 		Workspace: ws,
 		Project:   project,
+		Profile:   profile,
 	})
 
 	if err != nil {
 		return err
 	}
 
-	return ctrl.Up(controller.UpDTO{
+	return ctrl.Up(cmd.Context(), controller.UpDTO{
 		Workspace: ws,
 		Project:   project,
+		Profile:   profile,
 	})
 }
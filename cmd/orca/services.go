@@ -1,22 +1,35 @@
 package main
 
 import (
+	"context"
 	"os"
 
 	"github.com/panoptescloud/orca/internal/config"
 	"github.com/panoptescloud/orca/internal/controller"
 	"github.com/panoptescloud/orca/internal/docker"
+	"github.com/panoptescloud/orca/internal/doctor"
 	"github.com/panoptescloud/orca/internal/git"
 	"github.com/panoptescloud/orca/internal/github"
 	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/panoptescloud/orca/internal/logstore"
 	"github.com/panoptescloud/orca/internal/repository"
+	"github.com/panoptescloud/orca/internal/snapshots"
 	"github.com/panoptescloud/orca/internal/tls"
 	"github.com/panoptescloud/orca/internal/tui"
 	"github.com/panoptescloud/orca/internal/workspaces"
+	"github.com/panoptescloud/orca/pkg/logmux"
 	"github.com/spf13/afero"
 )
 
+// executor runs commands on the host, or with --dry-run, prints them instead.
+type executor interface {
+	Exec(ctx context.Context, cmdName string, args []string, opts ...hostsys.ExecOpt) error
+	StartDetached(cmdName string, args []string, opts ...hostsys.ExecOpt) error
+}
+
 type services struct {
+	dryRun bool
+
 	fs afero.Fs
 
 	config *config.Config
@@ -25,7 +38,7 @@ type services struct {
 
 	hostSystem *hostsys.HostSystem
 
-	executor *hostsys.Executor
+	executor executor
 
 	git *git.Git
 
@@ -35,13 +48,21 @@ type services struct {
 
 	controller *controller.Controller
 
+	logStore *logstore.Store
+
+	snapshotStore *snapshots.Store
+
 	workspaceRepo *repository.WorkspaceRepository
 
 	certificateManager *tls.CertificateManager
 
+	composeProviders        *docker.Providers
 	compose                 *docker.Compose
 	composeParser           *docker.ComposeParser
 	composeOverlayGenerator *docker.ComposeOverlayGenerator
+	volumes                 *docker.Volumes
+
+	doctor *doctor.Doctor
 }
 
 func (s *services) GetFs() afero.Fs {
@@ -51,9 +72,22 @@ func (s *services) GetFs() afero.Fs {
 
 	s.fs = afero.NewOsFs()
 
+	if s.dryRun {
+		s.fs = hostsys.NewDryRunFs(s.fs, os.Stdout)
+	}
+
 	return s.fs
 }
 
+// enableDryRun makes every service report the commands it would run, and the
+// files it would change, rather than actually doing so. It must be called
+// before any other services are used, so that they're all built with it.
+func (s *services) enableDryRun() {
+	*s = services{
+		dryRun: true,
+	}
+}
+
 func (s *services) GetConfig() *config.Config {
 	if s.config != nil {
 		return s.config
@@ -90,16 +124,23 @@ func (s *services) GetHostSystem() *hostsys.HostSystem {
 		s.GetFs(),
 		s.GetGithubClient(),
 		getToolsDir(),
+		s.GetConfig().GetComposeProvider(),
 	)
 
 	return s.hostSystem
 }
 
-func (s *services) GetExecutor() *hostsys.Executor {
+func (s *services) GetExecutor() executor {
 	if s.executor != nil {
 		return s.executor
 	}
 
+	if s.dryRun {
+		s.executor = hostsys.NewRecordingExecutor(os.Stdout)
+
+		return s.executor
+	}
+
 	s.executor = hostsys.NewExecutor()
 
 	return s.executor
@@ -139,6 +180,12 @@ func (s *services) GetWorkspaceManager() *workspaces.Manager {
 		s.GetConfig(),
 		s.GetGit(),
 		s.GetWorkspaceRepository(),
+		s.GetCompose(),
+		s.GetController(),
+		s.GetController(),
+		s.GetCertificateManager(),
+		s.GetComposeOverlayGenerator(),
+		s.GetVolumes(),
 	)
 
 	return s.workspaceManager
@@ -154,11 +201,42 @@ func (s *services) GetController() *controller.Controller {
 		s.GetWorkspaceRepository(),
 		s.GetCompose(),
 		s.GetTui(),
+		logmux.NewTerminal(os.Stdout),
+		s.GetLogStore(),
+		s.GetExecutor(),
+		s.GetVolumes(),
+		s.GetSnapshotStore(),
 	)
 
 	return s.controller
 }
 
+func (s *services) GetLogStore() *logstore.Store {
+	if s.logStore != nil {
+		return s.logStore
+	}
+
+	s.logStore = logstore.NewStore(
+		s.GetFs(),
+		getLogsDir(),
+	)
+
+	return s.logStore
+}
+
+func (s *services) GetSnapshotStore() *snapshots.Store {
+	if s.snapshotStore != nil {
+		return s.snapshotStore
+	}
+
+	s.snapshotStore = snapshots.NewStore(
+		s.GetFs(),
+		getSnapshotsDir(),
+	)
+
+	return s.snapshotStore
+}
+
 func (s *services) GetWorkspaceRepository() *repository.WorkspaceRepository {
 	if s.workspaceRepo != nil {
 		return s.workspaceRepo
@@ -196,11 +274,27 @@ func (s *services) GetCompose() *docker.Compose {
 		s.GetExecutor(),
 		s.GetTui(),
 		s.GetComposeOverlayGenerator(),
+		s.GetComposeProviders(),
 	)
 
 	return s.compose
 }
 
+func (s *services) GetComposeProviders() *docker.Providers {
+	if s.composeProviders != nil {
+		return s.composeProviders
+	}
+
+	s.composeProviders = docker.NewProviders(
+		s.GetExecutor(),
+		os.Getenv,
+		s.GetConfig().GetComposeProvider(),
+		hostsys.DetectComposeProvider,
+	)
+
+	return s.composeProviders
+}
+
 func (s *services) GetComposeParser() *docker.ComposeParser {
 	if s.composeParser != nil {
 		return s.composeParser
@@ -211,6 +305,20 @@ func (s *services) GetComposeParser() *docker.ComposeParser {
 	return s.composeParser
 }
 
+func (s *services) GetVolumes() *docker.Volumes {
+	if s.volumes != nil {
+		return s.volumes
+	}
+
+	s.volumes = docker.NewVolumes(
+		s.GetExecutor(),
+		s.GetComposeParser(),
+		s.GetComposeProviders(),
+	)
+
+	return s.volumes
+}
+
 func (s *services) GetComposeOverlayGenerator() *docker.ComposeOverlayGenerator {
 	if s.composeOverlayGenerator != nil {
 		return s.composeOverlayGenerator
@@ -227,3 +335,24 @@ func (s *services) GetComposeOverlayGenerator() *docker.ComposeOverlayGenerator
 
 	return s.composeOverlayGenerator
 }
+
+func (s *services) GetDoctor() *doctor.Doctor {
+	if s.doctor != nil {
+		return s.doctor
+	}
+
+	s.doctor = doctor.NewDoctor(
+		s.GetFs(),
+		s.GetTui(),
+		s.GetConfig(),
+		s.GetWorkspaceRepository(),
+		s.GetGit(),
+		s.GetComposeParser(),
+		s.GetCompose(),
+		s.GetCertificateManager(),
+		s.GetHostSystem(),
+		doctor.DefaultHostsFile,
+	)
+
+	return s.doctor
+}
@@ -25,7 +25,7 @@ func handleCheck(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
-func handleSysInstall(_ *cobra.Command, args []string) error {
+func handleSysInstall(cmd *cobra.Command, args []string) error {
 	tui := svcContainer.GetTui()
 	if len(args) < 1 {
 		return tui.RecordIfError("Must provide the name of the tool as the first argument", common.ErrArgumentRequired{
@@ -40,7 +40,7 @@ func handleSysInstall(_ *cobra.Command, args []string) error {
 
 	sys := svcContainer.GetHostSystem()
 
-	return sys.Install(hostsys.InstallDTO{
+	return sys.Install(cmd.Context(), hostsys.InstallDTO{
 		Tool: hostsys.AvailableTool(args[0]),
 	})
 }
@@ -56,7 +56,7 @@ func handleSysSelfUpdate(cmd *cobra.Command, _ []string) error {
 	if to != "" {
 		strategy = hostsys.VersioningStrategySpecific
 	}
-	return sys.UpdateSelf(hostsys.UpdateSelfDTO{
+	return sys.UpdateSelf(cmd.Context(), hostsys.UpdateSelfDTO{
 		Strategy:         strategy,
 		SpecifiedVersion: to,
 	})
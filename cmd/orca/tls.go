@@ -15,3 +15,17 @@ func handleTLSGen(cmd *cobra.Command, args []string) error {
 		WorkspaceName: ws,
 	})
 }
+
+func handleTLSAcmeServer(cmd *cobra.Command, args []string) error {
+	cm := svcContainer.GetCertificateManager()
+
+	ws, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+	address, err := cmd.Flags().GetString("address")
+	cobra.CheckErr(err)
+
+	return cm.ServeAcme(tls.ServeAcmeDTO{
+		WorkspaceName: ws,
+		Address:       address,
+	})
+}
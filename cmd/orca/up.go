@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/panoptescloud/orca/internal/controller"
 	"github.com/spf13/cobra"
 )
@@ -12,9 +14,31 @@ func handleUp(cmd *cobra.Command, args []string) error {
 	cobra.CheckErr(err)
 	project, err := cmd.Flags().GetString("project")
 	cobra.CheckErr(err)
+	profile, err := cmd.Flags().GetString("profile")
+	cobra.CheckErr(err)
+	withDeps, err := cmd.Flags().GetBool("with-deps")
+	cobra.CheckErr(err)
+	recordLogs, err := cmd.Flags().GetBool("record-logs")
+	cobra.CheckErr(err)
 
-	return ctrl.Up(controller.UpDTO{
+	err = ctrl.Up(cmd.Context(), controller.UpDTO{
 		Workspace: ws,
 		Project:   project,
+		Profile:   profile,
+		WithDeps:  withDeps,
 	})
+
+	if err != nil || !recordLogs {
+		return err
+	}
+
+	run, err := startLogRecorder(cmd)
+
+	if err != nil {
+		return svcContainer.GetTui().RecordIfError("Failed to start recording logs!", err)
+	}
+
+	svcContainer.GetTui().Info(fmt.Sprintf("Recording logs as run '%s', replay them with 'orca logs --run %s'.", run, run))
+
+	return nil
 }
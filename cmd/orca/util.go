@@ -1,12 +1,46 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/panoptescloud/orca/internal/repository"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/cobra/doc"
 )
 
+const schemasDir = "./docs/schemas"
+
 func handleUtilGenDocs(cmd *cobra.Command, _ []string) error {
 	err := doc.GenMarkdownTree(rootCmd, "./docs/CLI")
 
 	return err
 }
+
+func handleUtilGenSchemas(cmd *cobra.Command, _ []string) error {
+	fs := svcContainer.GetFs()
+
+	if err := fs.MkdirAll(schemasDir, 0755); err != nil {
+		return err
+	}
+
+	schemas := map[string]func() ([]byte, error){
+		"orca.workspace.schema.json": repository.WorkspaceConfigSchema,
+		"orca.project.schema.json":   repository.ProjectConfigSchema,
+	}
+
+	for name, build := range schemas {
+		contents, err := build()
+
+		if err != nil {
+			return err
+		}
+
+		if err := afero.WriteFile(fs, fmt.Sprintf("%s/%s", schemasDir, name), contents, os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,33 +1,23 @@
 package main
 
 import (
-	"fmt"
-
 	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/doctor"
 	"github.com/panoptescloud/orca/internal/workspaces"
 	"github.com/spf13/cobra"
 )
 
 func handleWsSwitch(cmd *cobra.Command, args []string) error {
-	tui := svcContainer.GetTui()
-
-	if len(args) < 1 {
-		tui.Error("Must supply a workspace!")
-
-		return common.ErrUnknownWorkspace{
-			Name: "",
-		}
-	}
-
-	manager := svcContainer.GetConfig()
+	manager := svcContainer.GetWorkspaceManager()
 
-	if err := manager.SwitchWorkspace(args[0]); err != nil {
-		return tui.RecordIfError("Failed to switch workspace!", err)
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
 	}
 
-	tui.Success(fmt.Sprintf("Switched to %s", args[0]))
-
-	return nil
+	return manager.Switch(cmd.Context(), workspaces.SwitchDTO{
+		WorkspaceName: name,
+	})
 }
 
 func handleWsInit(cmd *cobra.Command, args []string) error {
@@ -39,18 +29,55 @@ func handleWsInit(cmd *cobra.Command, args []string) error {
 	cobra.CheckErr(err)
 	configFile, err := cmd.Flags().GetString("config")
 	cobra.CheckErr(err)
+	cloneProjects, err := cmd.Flags().GetBool("clone")
+	cobra.CheckErr(err)
+	switchTo, err := cmd.Flags().GetBool("switch")
+	cobra.CheckErr(err)
+
+	gitUrl := ""
+
+	if len(args) > 0 {
+		gitUrl = args[0]
+
+		// The workspace repo is cloned into the target, so by default that
+		// should be where the command is run, rather than its parent
+		if !cmd.Flags().Changed("target") {
+			target = getWorkingDir()
+		}
+	}
 
-	return manager.Initialise(workspaces.InitialiseDTO{
+	return manager.Initialise(cmd.Context(), workspaces.InitialiseDTO{
+		SourceGitUrl:      gitUrl,
 		SourceDirectory:   source,
 		WorkspaceFileName: configFile,
 		Into:              target,
+		CloneProjects:     cloneProjects,
+		Switch:            switchTo,
 	})
 }
 
 func handleWsLs(cmd *cobra.Command, args []string) error {
 	manager := svcContainer.GetWorkspaceManager()
 
-	return manager.Ls(workspaces.LsDTO{})
+	status, err := cmd.Flags().GetBool("status")
+	cobra.CheckErr(err)
+
+	return manager.Ls(cmd.Context(), workspaces.LsDTO{
+		Status: status,
+	})
+}
+
+func handleWsShow(cmd *cobra.Command, args []string) error {
+	manager := svcContainer.GetWorkspaceManager()
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	return manager.Show(workspaces.ShowDTO{
+		WorkspaceName: name,
+	})
 }
 
 func handleWsClone(cmd *cobra.Command, args []string) error {
@@ -65,9 +92,94 @@ func handleWsClone(cmd *cobra.Command, args []string) error {
 	to, err := cmd.Flags().GetString("target")
 	cobra.CheckErr(err)
 
-	return manager.Clone(workspaces.CloneDTO{
+	depth, err := cmd.Flags().GetInt("depth")
+	cobra.CheckErr(err)
+
+	filter, err := cmd.Flags().GetString("filter")
+	cobra.CheckErr(err)
+
+	retries, err := cmd.Flags().GetInt("retry-failed")
+	cobra.CheckErr(err)
+
+	return manager.Clone(cmd.Context(), workspaces.CloneDTO{
 		WorkspaceName: name,
 		Project:       projectName,
 		To:            to,
+		Options: common.CloneOptions{
+			Depth:  depth,
+			Filter: filter,
+		},
+		RetryFailed: retries,
+	})
+}
+
+func handleWsValidate(cmd *cobra.Command, args []string) error {
+	manager := svcContainer.GetWorkspaceManager()
+
+	name, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+
+	return manager.Validate(workspaces.ValidateDTO{
+		WorkspaceName: name,
+	})
+}
+
+func handleWsRm(cmd *cobra.Command, args []string) error {
+	manager := svcContainer.GetWorkspaceManager()
+
+	down, err := cmd.Flags().GetBool("down")
+	cobra.CheckErr(err)
+	clean, err := cmd.Flags().GetBool("clean")
+	cobra.CheckErr(err)
+
+	return manager.Remove(cmd.Context(), workspaces.RemoveDTO{
+		WorkspaceName: args[0],
+		Down:          down,
+		Clean:         clean,
+	})
+}
+
+func handleWsRename(cmd *cobra.Command, args []string) error {
+	manager := svcContainer.GetWorkspaceManager()
+
+	return manager.Rename(cmd.Context(), workspaces.RenameDTO{
+		From: args[0],
+		To:   args[1],
+	})
+}
+
+func handleWsRelocate(cmd *cobra.Command, args []string) error {
+	manager := svcContainer.GetWorkspaceManager()
+
+	name, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+
+	return manager.Relocate(workspaces.RelocateDTO{
+		WorkspaceName: name,
+		Path:          args[0],
+	})
+}
+
+func handleWsProjectSetPath(cmd *cobra.Command, args []string) error {
+	manager := svcContainer.GetWorkspaceManager()
+
+	name, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+
+	return manager.SetProjectPath(workspaces.SetProjectPathDTO{
+		WorkspaceName: name,
+		Project:       args[0],
+		Path:          args[1],
+	})
+}
+
+func handleWsDoctor(cmd *cobra.Command, args []string) error {
+	d := svcContainer.GetDoctor()
+
+	name, err := cmd.Flags().GetString("workspace")
+	cobra.CheckErr(err)
+
+	return d.Diagnose(cmd.Context(), doctor.DiagnoseDTO{
+		WorkspaceName: name,
 	})
 }
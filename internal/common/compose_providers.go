@@ -0,0 +1,15 @@
+package common
+
+const (
+	ComposeProviderDocker  = "docker"
+	ComposeProviderPodman  = "podman"
+	ComposeProviderNerdctl = "nerdctl"
+)
+
+// ComposeProviders are the supported compose implementations, in the order
+// they're preferred when detecting which is installed.
+var ComposeProviders = []string{
+	ComposeProviderDocker,
+	ComposeProviderPodman,
+	ComposeProviderNerdctl,
+}
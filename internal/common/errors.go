@@ -2,7 +2,9 @@ package common
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"time"
 )
 
 type ErrToolsNotFoundOnSystem struct {
@@ -51,6 +53,16 @@ func (err ErrCommandExecutionFailed) Error() string {
 	return fmt.Sprintf("command execution failed: %s", err.Msg)
 }
 
+// ErrReceivedSignal is the cause of the context being cancelled, when orca is
+// signalled to stop.
+type ErrReceivedSignal struct {
+	Signal os.Signal
+}
+
+func (err ErrReceivedSignal) Error() string {
+	return fmt.Sprintf("received signal: %s", err.Signal)
+}
+
 type ErrUserAbortedExecution struct{}
 
 func (err ErrUserAbortedExecution) Error() string {
@@ -139,6 +151,14 @@ func (err ErrUnknownProject) Error() string {
 	return fmt.Sprintf("unknown project: %s", err.Name)
 }
 
+type ErrUnknownProfile struct {
+	Name string
+}
+
+func (err ErrUnknownProfile) Error() string {
+	return fmt.Sprintf("unknown profile: %s", err.Name)
+}
+
 type ErrUnknownTool struct {
 	Tool string
 }
@@ -270,3 +290,132 @@ type ErrUnknownExtension struct {
 func (err ErrUnknownExtension) Error() string {
 	return fmt.Sprintf("extension '%s' not found in project", err.Name)
 }
+
+// ConfigIssue describes a single problem found within a config file. Line and
+// Column are 1-indexed, and are 0 when the position is not known.
+type ConfigIssue struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (issue ConfigIssue) String() string {
+	switch {
+	case issue.Line > 0 && issue.Column > 0:
+		return fmt.Sprintf("%d:%d: %s", issue.Line, issue.Column, issue.Message)
+	case issue.Line > 0:
+		return fmt.Sprintf("%d: %s", issue.Line, issue.Message)
+	default:
+		return issue.Message
+	}
+}
+
+type ErrInvalidConfig struct {
+	Path   string
+	Issues []ConfigIssue
+}
+
+// Lines returns each issue prefixed with the path and position, in the same
+// format compilers use, so that editors/terminals can link to them.
+func (err ErrInvalidConfig) Lines() []string {
+	lines := make([]string, len(err.Issues))
+
+	for i, issue := range err.Issues {
+		if issue.Line == 0 {
+			lines[i] = fmt.Sprintf("%s: %s", err.Path, issue.Message)
+			continue
+		}
+
+		lines[i] = fmt.Sprintf("%s:%s", err.Path, issue.String())
+	}
+
+	return lines
+}
+
+func (err ErrInvalidConfig) Error() string {
+	return fmt.Sprintf("config at '%s' is invalid: %s", err.Path, strings.Join(err.Lines(), "; "))
+}
+
+type ErrContainerEngineUnavailable struct {
+	Socket string
+	Reason string
+}
+
+func (err ErrContainerEngineUnavailable) Error() string {
+	return fmt.Sprintf("could not connect to the container engine at '%s', is it running? %s", err.Socket, err.Reason)
+}
+
+type ErrUnknownComposeProvider struct {
+	Name string
+}
+
+func (err ErrUnknownComposeProvider) Error() string {
+	return fmt.Sprintf("unknown compose provider '%s', must be one of: %s", err.Name, strings.Join(ComposeProviders, ", "))
+}
+
+type ErrUnknownLogRun struct {
+	Workspace string
+	Run       string
+}
+
+func (err ErrUnknownLogRun) Error() string {
+	return fmt.Sprintf("no logs were recorded for run '%s' of workspace '%s'", err.Run, err.Workspace)
+}
+
+type ErrServiceNotRunning struct {
+	Project string
+	Service string
+}
+
+func (err ErrServiceNotRunning) Error() string {
+	return fmt.Sprintf("service '%s' in project '%s' is not running", err.Service, err.Project)
+}
+
+type ErrHookFailed struct {
+	Event HookEvent
+	Hook  string
+	Err   error
+}
+
+func (err ErrHookFailed) Error() string {
+	return fmt.Sprintf("%s hook '%s' failed: %s", err.Event, err.Hook, err.Err.Error())
+}
+
+// Unwrap allows the exit code of the hook's command to be used.
+func (err ErrHookFailed) Unwrap() error {
+	return err.Err
+}
+
+type ErrHookTimedOut struct {
+	Timeout time.Duration
+}
+
+func (err ErrHookTimedOut) Error() string {
+	return fmt.Sprintf("timed out after %s", err.Timeout)
+}
+
+type ErrProjectIsRunning struct {
+	Project string
+}
+
+func (err ErrProjectIsRunning) Error() string {
+	return fmt.Sprintf("project '%s' is running", err.Project)
+}
+
+type ErrUnknownSnapshot struct {
+	Workspace string
+	Name      string
+}
+
+func (err ErrUnknownSnapshot) Error() string {
+	return fmt.Sprintf("there is no snapshot '%s' of workspace '%s'", err.Name, err.Workspace)
+}
+
+type ErrSnapshotExists struct {
+	Workspace string
+	Name      string
+}
+
+func (err ErrSnapshotExists) Error() string {
+	return fmt.Sprintf("there is already a snapshot '%s' of workspace '%s'", err.Name, err.Workspace)
+}
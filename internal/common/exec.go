@@ -0,0 +1,20 @@
+package common
+
+// ExecOptions controls how a command is run in a service's container.
+type ExecOptions struct {
+	User    string
+	Workdir string
+
+	// Env is a list of KEY=VAL to set in the container, or just KEY to pass
+	// the value through from the host.
+	Env []string
+
+	// Index chooses the container of a scaled service, starting from 1. It's
+	// only used when exec-ing into a running container.
+	Index int
+
+	// TTY allocates a TTY in the container, it should only be set when orca
+	// itself is attached to one. Otherwise, e.g. when piping input in, the
+	// command fails.
+	TTY bool
+}
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// Exit codes used for orca's own failures. When a command orca runs fails,
+// e.g. in 'orca exec', orca exits with that command's exit code instead, so
+// these are chosen to be unlikely to clash with those.
+const (
+	// ExitCodeFailure is used for anything not covered below.
+	ExitCodeFailure = 1
+
+	// ExitCodeConfigError means the workspace, project or orca config is
+	// invalid or missing (EX_CONFIG in sysexits.h).
+	ExitCodeConfigError = 78
+
+	// ExitCodeUnknownWorkspace means the workspace, or a project or profile
+	// within it, doesn't exist.
+	ExitCodeUnknownWorkspace = 79
+
+	// ExitCodeToolMissing means a tool orca needs isn't installed, the same
+	// as a shell uses for a command that isn't found.
+	ExitCodeToolMissing = 127
+
+	// ExitCodeUserAborted means the user cancelled a prompt, or interrupted
+	// orca, the same as a shell uses for Ctrl-C.
+	ExitCodeUserAborted = 130
+)
+
+// ExitCode works out what orca should exit with for the error.
+func ExitCode(err error) int {
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) {
+		// -1 means it was killed by a signal, rather than exiting
+		if exitErr.ExitCode() > 0 {
+			return exitErr.ExitCode()
+		}
+
+		return ExitCodeFailure
+	}
+
+	switch {
+	case errors.As(err, &ErrUserAbortedExecution{}),
+		errors.Is(err, context.Canceled):
+		return ExitCodeUserAborted
+	case errors.As(err, &ErrToolsNotFoundOnSystem{}),
+		errors.As(err, &ErrToolNotFoundOnSystem{}),
+		errors.Is(err, exec.ErrNotFound):
+		return ExitCodeToolMissing
+	case errors.As(err, &ErrUnknownWorkspace{}),
+		errors.As(err, &ErrUnknownProject{}),
+		errors.As(err, &ErrUnknownProfile{}):
+		return ExitCodeUnknownWorkspace
+	case errors.As(err, &ErrInvalidConfig{}),
+		errors.As(err, &ErrConfigIsNotValidYAML{}),
+		errors.As(err, &ErrWorkspaceConfigNotFound{}),
+		errors.As(err, &ErrProjectConfigNotFound{}),
+		errors.As(err, &ErrInvalidValueForOverlayModifier{}),
+		errors.As(err, &ErrUnknownComposeProvider{}):
+		return ExitCodeConfigError
+	}
+
+	return ExitCodeFailure
+}
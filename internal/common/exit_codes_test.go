@@ -0,0 +1,89 @@
+package common_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExitCode(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+	require.NotNil(t, exitErr)
+
+	_, notFoundErr := exec.LookPath("orca-does-not-exist")
+	require.NotNil(t, notFoundErr)
+
+	tests := []struct {
+		name   string
+		err    error
+		expect int
+	}{
+		{
+			name:   "command exit code",
+			err:    exitErr,
+			expect: 3,
+		},
+		{
+			name:   "wrapped command exit code",
+			err:    fmt.Errorf("api: %w", exitErr),
+			expect: 3,
+		},
+		{
+			name:   "joined command exit code",
+			err:    errors.Join(errors.New("other"), exitErr),
+			expect: 3,
+		},
+		{
+			name:   "invalid config",
+			err:    common.ErrInvalidConfig{Path: "orca.workspace.yaml"},
+			expect: common.ExitCodeConfigError,
+		},
+		{
+			name:   "unknown workspace",
+			err:    common.ErrUnknownWorkspace{Name: "nope"},
+			expect: common.ExitCodeUnknownWorkspace,
+		},
+		{
+			name:   "unknown project",
+			err:    common.ErrUnknownProject{Name: "nope"},
+			expect: common.ExitCodeUnknownWorkspace,
+		},
+		{
+			name:   "tool missing",
+			err:    common.ErrToolsNotFoundOnSystem{},
+			expect: common.ExitCodeToolMissing,
+		},
+		{
+			name:   "executable not found",
+			err:    notFoundErr,
+			expect: common.ExitCodeToolMissing,
+		},
+		{
+			name:   "user aborted",
+			err:    common.ErrUserAbortedExecution{},
+			expect: common.ExitCodeUserAborted,
+		},
+		{
+			name:   "interrupted",
+			err:    fmt.Errorf("api: %w", context.Canceled),
+			expect: common.ExitCodeUserAborted,
+		},
+		{
+			name:   "anything else",
+			err:    errors.New("boom"),
+			expect: common.ExitCodeFailure,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert.Equal(tt, test.expect, common.ExitCode(test.err))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package common
+
+// RepoStatus is a summary of the state of a git repository.
+type RepoStatus struct {
+	Branch string
+
+	// HasUpstream is false when the branch isn't tracking a remote branch, in
+	// which case Ahead and Behind are always 0.
+	HasUpstream bool
+	Ahead       int
+	Behind      int
+
+	// Dirty is true when tracked files have uncommitted changes.
+	Dirty bool
+}
+
+// CloneOptions control how much of a repository's history is fetched when
+// cloning it.
+type CloneOptions struct {
+	// Depth creates a shallow clone with that many commits, 0 clones everything.
+	Depth int
+
+	// Filter creates a partial clone, e.g. 'blob:none'.
+	Filter string
+}
@@ -0,0 +1,103 @@
+package common
+
+import "time"
+
+// HookEvent is a point in a workspace's lifecycle that hooks can be run at.
+type HookEvent string
+
+const (
+	HookPreUp      HookEvent = "preUp"
+	HookPostUp     HookEvent = "postUp"
+	HookPreDown    HookEvent = "preDown"
+	HookPostDown   HookEvent = "postDown"
+	HookPostClone  HookEvent = "postClone"
+	HookPostSwitch HookEvent = "postSwitch"
+)
+
+// IsPre is whether the event happens before the action, in which case the
+// workspace's hooks run before those of its projects. Otherwise they run
+// after.
+func (e HookEvent) IsPre() bool {
+	return e == HookPreUp || e == HookPreDown
+}
+
+// HookFailurePolicy decides what happens when a hook fails, or times out.
+type HookFailurePolicy string
+
+const (
+	// HookAbort stops whatever triggered the hook, this is the default.
+	HookAbort HookFailurePolicy = "abort"
+
+	// HookWarn reports the failure, and carries on.
+	HookWarn HookFailurePolicy = "warn"
+)
+
+var HookFailurePolicies = []string{
+	string(HookAbort),
+	string(HookWarn),
+}
+
+// DefaultHookTimeout is how long a hook can run for when it doesn't have its
+// own timeout.
+const DefaultHookTimeout = 5 * time.Minute
+
+// Hook is a command run around a lifecycle event, either on the host or in
+// one of the project's services.
+type Hook struct {
+	Name    string
+	Command string
+
+	// Service runs the command in that service's container, rather than on
+	// the host.
+	Service string
+
+	// Project is the project the service belongs to, it's only used for the
+	// workspace's own hooks.
+	Project string
+
+	// Chdir is the directory to run the command in on the host, relative to
+	// the project (or workspace) directory.
+	Chdir string
+
+	Timeout   time.Duration
+	OnFailure HookFailurePolicy
+}
+
+// DisplayName is the name to show the user, the command is used if the hook
+// hasn't been named.
+func (h Hook) DisplayName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+
+	return h.Command
+}
+
+type Hooks struct {
+	PreUp      []Hook
+	PostUp     []Hook
+	PreDown    []Hook
+	PostDown   []Hook
+	PostClone  []Hook
+	PostSwitch []Hook
+}
+
+// For returns the hooks to run for the event, in the order they were given.
+func (h Hooks) For(event HookEvent) []Hook {
+	switch event {
+	case HookPreUp:
+		return h.PreUp
+	case HookPostUp:
+		return h.PostUp
+	case HookPreDown:
+		return h.PreDown
+	case HookPostDown:
+		return h.PostDown
+	case HookPostClone:
+		return h.PostClone
+	case HookPostSwitch:
+		return h.PostSwitch
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package common
+
+// LogsOptions controls which logs are read from compose.
+type LogsOptions struct {
+	// Since is a timestamp (e.g. 2024-01-02T13:04:05) or relative duration
+	// (e.g. 10m), as accepted by 'compose logs --since'.
+	Since string
+
+	// Follow keeps streaming new logs until the containers stop.
+	Follow bool
+}
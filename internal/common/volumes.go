@@ -0,0 +1,11 @@
+package common
+
+// Volume is a named volume from a project's compose file.
+type Volume struct {
+	// Key is what the volume is called in the compose file.
+	Key string
+
+	// Name is what the volume is called by the container engine, i.e. with
+	// the compose project name in front of it.
+	Name string
+}
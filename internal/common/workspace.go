@@ -52,11 +52,13 @@ type ProjectConfig struct {
 	Hosts           []string
 	TLSCertificates []string
 	Extensions      []Extension
+	Hooks           Hooks
 }
 
 type ProjectRepositoryConfig struct {
-	SSH  string
-	Self bool
+	SSH    string
+	Self   bool
+	Branch string
 }
 
 type Project struct {
@@ -121,6 +123,30 @@ type Workspace struct {
 	ConfigPath    string
 	Projects      []Project
 	OverlayConfig OverlayConfig `yaml:"overlays"`
+
+	// ComposeProvider is the compose implementation the workspace prefers, if
+	// any. The user's own config takes precedence over this.
+	ComposeProvider string
+
+	// Profiles are named subsets of the projects, which can be started without
+	// the rest of the workspace.
+	Profiles map[string][]string
+
+	// Hooks are run around lifecycle events for the whole workspace, on top
+	// of each project's own.
+	Hooks Hooks
+}
+
+func (ws *Workspace) GetProfile(name string) ([]string, error) {
+	projects, ok := ws.Profiles[name]
+
+	if !ok {
+		return nil, ErrUnknownProfile{
+			Name: name,
+		}
+	}
+
+	return projects, nil
 }
 
 func (ws *Workspace) GetProject(name string) (*Project, error) {
@@ -170,8 +196,24 @@ type UnconfiguredProject struct {
 	RepositoryConfig ProjectRepositoryConfig
 }
 
+func (p UnconfiguredProject) GetName() string {
+	return p.Name
+}
+
 type UnconfiguredWorkspace struct {
 	Name       string
 	ConfigPath string
 	Projects   []UnconfiguredProject
 }
+
+func (ws *UnconfiguredWorkspace) GetProject(name string) (*UnconfiguredProject, error) {
+	p := slices.GetNamedElement(ws.Projects, name)
+
+	if p == nil {
+		return nil, ErrUnknownProject{
+			Name: name,
+		}
+	}
+
+	return p, nil
+}
@@ -10,6 +10,12 @@ type configLogging struct {
 	Format string
 }
 
+type configCompose struct {
+	// Provider is the compose implementation to use, when empty it's detected
+	// from what is installed.
+	Provider string
+}
+
 type configProject struct {
 	Name string
 	Path string
@@ -31,6 +37,7 @@ func (self configWorkspace) GetName() string {
 
 type config struct {
 	Logging          configLogging
+	Compose          configCompose `yaml:"compose,omitempty"`
 	Workspaces       []configWorkspace
 	CurrentWorkspace string `yaml:"currentWorkspace" mapstructure:"current_workspace"`
 }
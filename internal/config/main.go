@@ -86,6 +86,78 @@ func (self *Config) AddWorkspace(path string, name string) error {
 	return self.save()
 }
 
+// RemoveWorkspace removes the workspace, and all of its project paths, from
+// the configuration on disk. If it was the current workspace, there will no
+// longer be a current workspace.
+func (self *Config) RemoveWorkspace(name string) error {
+	if !self.persisted.workspaceExists(name) {
+		return common.ErrUnknownWorkspace{
+			Name: name,
+		}
+	}
+
+	self.persisted.Workspaces = slices.RemoveNamedElement(self.persisted.Workspaces, name)
+
+	if self.persisted.CurrentWorkspace == name {
+		self.persisted.CurrentWorkspace = ""
+	}
+
+	self.runtimeConfig.Workspaces = self.persisted.Workspaces
+
+	if self.runtimeConfig.CurrentWorkspace == name {
+		self.runtimeConfig.CurrentWorkspace = ""
+	}
+
+	return self.save()
+}
+
+// RenameWorkspace changes the name the workspace is registered under, keeping
+// its path and projects. If it was the current workspace, it remains so.
+func (self *Config) RenameWorkspace(from string, to string) error {
+	ws, err := self.persisted.getWorkspace(from)
+
+	if err != nil {
+		return err
+	}
+
+	if self.persisted.workspaceExists(to) {
+		return common.ErrWorkspaceAlreadyExists{
+			Name: to,
+		}
+	}
+
+	ws.Name = to
+	self.persisted.Workspaces[slices.GetNamedElementIndex(self.persisted.Workspaces, from)] = ws
+
+	if self.persisted.CurrentWorkspace == from {
+		self.persisted.CurrentWorkspace = to
+	}
+
+	self.runtimeConfig.Workspaces = self.persisted.Workspaces
+
+	if self.runtimeConfig.CurrentWorkspace == from {
+		self.runtimeConfig.CurrentWorkspace = to
+	}
+
+	return self.save()
+}
+
+// SetWorkspacePath changes where the config for the workspace is found.
+func (self *Config) SetWorkspacePath(name string, path string) error {
+	ws, err := self.persisted.getWorkspace(name)
+
+	if err != nil {
+		return err
+	}
+
+	ws.Path = path
+
+	self.persisted.Workspaces = slices.UpsertNamedElement(self.persisted.Workspaces, ws)
+	self.runtimeConfig.Workspaces = self.persisted.Workspaces
+
+	return self.save()
+}
+
 func (self *Config) GetAllProjectMeta() []common.ProjectMeta {
 	projects := []common.ProjectMeta{}
 
@@ -195,6 +267,11 @@ func (self *Config) GetLoggingFormat() string {
 	return self.runtimeConfig.Logging.Format
 }
 
+// GetComposeProvider returns the compose provider the user has chosen, if any.
+func (self *Config) GetComposeProvider() string {
+	return self.runtimeConfig.Compose.Provider
+}
+
 func (self *Config) GetRuntimeConfig() *config {
 	return self.runtimeConfig
 }
@@ -242,6 +242,22 @@ func Test_LoggingGetters(t *testing.T) {
 	assert.Equal(t, "json", cfg.GetLoggingFormat())
 }
 
+func Test_GetComposeProvider(t *testing.T) {
+	_, cfg := useExistingConfig(t)
+	assert.Equal(t, "", cfg.GetComposeProvider())
+
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, configFilePath, []byte(`compose:
+    provider: podman
+`), 0755)
+	require.Nil(t, err)
+
+	cfg = NewDefaultConfig(fs, configFilePath)
+	require.Nil(t, cfg.LoadOrCreate())
+
+	assert.Equal(t, "podman", cfg.GetComposeProvider())
+}
+
 // Used to ensure that during loading or any mutations we do not end up referencing
 // the same struct. Should be called after any mutation functions throughout the
 // tests.
@@ -568,3 +584,112 @@ currentWorkspace: blah
 		})
 	}
 }
+
+func useConfigWithProjects(t *testing.T) (afero.Fs, *Config) {
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, configFilePath, []byte(existingWithMultipleWorkspacesAndProjects), 0755)
+	require.Nil(t, err)
+	cfg := NewDefaultConfig(
+		fs,
+		configFilePath,
+	)
+
+	require.Nil(t, cfg.LoadOrCreate())
+
+	return fs, cfg
+}
+
+func Test_RemoveWorkspace(t *testing.T) {
+	fs, cfg := useConfigWithProjects(t)
+
+	err := cfg.RemoveWorkspace("blah")
+	require.Nil(t, err)
+	assertInternalConfigsAreDifferent(t, cfg)
+
+	contents, err := afero.ReadFile(fs, configFilePath)
+	expectedContents := `logging:
+    level: debug
+    format: json
+workspaces:
+    - name: meh
+      path: /path/meh
+      projects:
+        - name: meh1
+          path: /projects/meh/1
+        - name: meh2
+          path: /projects/meh/2
+currentWorkspace: ""
+`
+	require.Nil(t, err)
+	assert.Equal(t, expectedContents, string(contents))
+	assert.Equal(t, "", cfg.GetCurrentWorkspace())
+
+	err = cfg.RemoveWorkspace("blah")
+	assert.Equal(t, common.ErrUnknownWorkspace{
+		Name: "blah",
+	}, err)
+}
+
+func Test_RenameWorkspace(t *testing.T) {
+	fs, cfg := useConfigWithProjects(t)
+
+	err := cfg.RenameWorkspace("blah", "other")
+	require.Nil(t, err)
+	assertInternalConfigsAreDifferent(t, cfg)
+
+	contents, err := afero.ReadFile(fs, configFilePath)
+	expectedContents := `logging:
+    level: debug
+    format: json
+workspaces:
+    - name: meh
+      path: /path/meh
+      projects:
+        - name: meh1
+          path: /projects/meh/1
+        - name: meh2
+          path: /projects/meh/2
+    - name: other
+      path: /path/blah
+      projects:
+        - name: blah1
+          path: /projects/blah/1
+        - name: blah2
+          path: /projects/blah/2
+currentWorkspace: other
+`
+	require.Nil(t, err)
+	assert.Equal(t, expectedContents, string(contents))
+	assert.Equal(t, "other", cfg.GetCurrentWorkspace())
+
+	err = cfg.RenameWorkspace("other", "meh")
+	assert.Equal(t, common.ErrWorkspaceAlreadyExists{
+		Name: "meh",
+	}, err)
+
+	err = cfg.RenameWorkspace("missing", "new")
+	assert.Equal(t, common.ErrUnknownWorkspace{
+		Name: "missing",
+	}, err)
+}
+
+func Test_SetWorkspacePath(t *testing.T) {
+	_, cfg := useConfigWithProjects(t)
+
+	err := cfg.SetWorkspacePath("meh", "/new/path/meh")
+	require.Nil(t, err)
+	assertInternalConfigsAreDifferent(t, cfg)
+
+	meta, err := cfg.GetWorkspaceMeta("meh")
+	require.Nil(t, err)
+	assert.Equal(t, "/new/path/meh", meta.Path)
+
+	project, err := cfg.GetProjectMeta("meh", "meh1")
+	require.Nil(t, err)
+	assert.Equal(t, "/projects/meh/1", project.Path)
+
+	err = cfg.SetWorkspacePath("missing", "/path")
+	assert.Equal(t, common.ErrUnknownWorkspace{
+		Name: "missing",
+	}, err)
+}
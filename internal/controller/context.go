@@ -0,0 +1,296 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+// contextResolution is the workspace, and optionally the project, that a
+// command should run against, along with why they were chosen.
+type contextResolution struct {
+	Workspace string
+	Project   string
+	Reason    string
+
+	// OtherMatches are less specific directories that also contain the working
+	// directory, if it was used.
+	OtherMatches []directoryMatch
+}
+
+// directoryMatch is a project, or the directory containing a workspace
+// config, which contains the working directory.
+type directoryMatch struct {
+	Workspace string
+	Project   string
+	Path      string
+}
+
+func (m directoryMatch) describe() string {
+	if m.Project != "" {
+		return fmt.Sprintf("project '%s' of workspace '%s' at %s", m.Project, m.Workspace, m.Path)
+	}
+
+	return fmt.Sprintf("workspace '%s' at %s", m.Workspace, m.Path)
+}
+
+// isWithin reports whether dir is path, or is below it. Paths are compared by
+// segment, so /src/api-gateway is not within /src/api.
+func isWithin(dir string, path string) bool {
+	if path == "" {
+		return false
+	}
+
+	rel, err := filepath.Rel(filepath.Clean(path), filepath.Clean(dir))
+
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// matchDirectory finds everything containing dir, most specific (i.e. longest
+// path) first. A project is more specific than a workspace in the same
+// directory.
+func matchDirectory(dir string, workspaces []common.WorkspaceMeta, projects []common.ProjectMeta) []directoryMatch {
+	matches := []directoryMatch{}
+
+	for _, p := range projects {
+		if isWithin(dir, p.Path) {
+			matches = append(matches, directoryMatch{
+				Workspace: p.WorkspaceName,
+				Project:   p.Name,
+				Path:      filepath.Clean(p.Path),
+			})
+		}
+	}
+
+	for _, ws := range workspaces {
+		wsDir := filepath.Dir(ws.Path)
+
+		if ws.Path != "" && isWithin(dir, wsDir) {
+			matches = append(matches, directoryMatch{
+				Workspace: ws.Name,
+				Path:      filepath.Clean(wsDir),
+			})
+		}
+	}
+
+	// A stable sort keeps projects ahead of workspaces with the same path
+	slices.SortStableFunc(matches, func(a directoryMatch, b directoryMatch) int {
+		return len(b.Path) - len(a.Path)
+	})
+
+	return matches
+}
+
+// The variables that the workspace, project and service can be set from, e.g.
+// by direnv or in CI.
+const (
+	WorkspaceEnv = "ORCA_WORKSPACE"
+	ProjectEnv   = "ORCA_PROJECT"
+	ServiceEnv   = "ORCA_SERVICE"
+)
+
+// EnvOption is an option, e.g. the workspace, which can also be set from the
+// environment.
+type EnvOption struct {
+	Name string
+	Env  string
+
+	// Given is whether it was set on the command line.
+	Given bool
+}
+
+// ResolveEnvOptions returns the values to use for options that weren't given
+// on the command line, from the variables returned by getenv. The options are
+// expected in order from the broadest to the narrowest. Once one has been
+// given, the narrower ones aren't taken from the environment either, e.g.
+// ORCA_PROJECT is ignored when using -w, as it may not be in that workspace.
+//
+// Anything still unset is resolved from the working directory, and then the
+// current workspace, by resolveContextNames.
+func ResolveEnvOptions(opts []EnvOption, getenv func(string) string) map[string]string {
+	values := map[string]string{}
+
+	for _, opt := range opts {
+		if opt.Given {
+			break
+		}
+
+		if value := getenv(opt.Env); value != "" {
+			values[opt.Name] = value
+		}
+	}
+
+	return values
+}
+
+func (c *Controller) matchWorkdir() ([]directoryMatch, string, error) {
+	dir, err := os.Getwd()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return matchDirectory(dir, c.cfg.GetAllWorkspaceMeta(), c.cfg.GetAllProjectMeta()), dir, nil
+}
+
+// contextNames are the workspace and project a command was asked to use, if
+// any, and whether they came from the environment rather than the command
+// line.
+type contextNames struct {
+	Workspace string
+	Project   string
+
+	WorkspaceFromEnv bool
+	ProjectFromEnv   bool
+}
+
+// origin describes where a name came from, for the reason it was used.
+func origin(fromEnv bool, env string) string {
+	if fromEnv {
+		return fmt.Sprintf("set by %s", env)
+	}
+
+	return "given"
+}
+
+// resolveContextNames works out which workspace and project to use. Anything
+// given explicitly is used first, then the working directory, and finally the
+// current workspace.
+func (c *Controller) resolveContextNames(names contextNames) (contextResolution, error) {
+	ws := names.Workspace
+	project := names.Project
+
+	// We've got a specific workspace and project
+	if ws != "" && project != "" {
+		reason := "the workspace and project were given"
+
+		if names.WorkspaceFromEnv || names.ProjectFromEnv {
+			reason = fmt.Sprintf(
+				"the workspace was %s, and the project was %s",
+				origin(names.WorkspaceFromEnv, WorkspaceEnv),
+				origin(names.ProjectFromEnv, ProjectEnv),
+			)
+		}
+
+		return contextResolution{
+			Workspace: ws,
+			Project:   project,
+			Reason:    reason,
+		}, nil
+	}
+
+	// No workspace, but a project was specified, so we'll assume that it's the
+	// current workspace
+	if ws == "" && project != "" {
+		return contextResolution{
+			Workspace: c.cfg.GetCurrentWorkspace(),
+			Project:   project,
+			Reason:    fmt.Sprintf("the project was %s, so the current workspace is used", origin(names.ProjectFromEnv, ProjectEnv)),
+		}, nil
+	}
+
+	// The workspace was specified, but not project was so we'll use that workspace
+	// and assume all projects.
+	if ws != "" && project == "" {
+		return contextResolution{
+			Workspace: ws,
+			Reason:    fmt.Sprintf("the workspace was %s, so all of its projects are used", origin(names.WorkspaceFromEnv, WorkspaceEnv)),
+		}, nil
+	}
+
+	// If we get here, both of the options were empty, so we'll try resolve from
+	// the working directory
+	matches, dir, err := c.matchWorkdir()
+
+	if err != nil {
+		return contextResolution{}, err
+	}
+
+	if len(matches) > 0 {
+		return contextResolution{
+			Workspace:    matches[0].Workspace,
+			Project:      matches[0].Project,
+			Reason:       fmt.Sprintf("the working directory %s is within %s", dir, matches[0].describe()),
+			OtherMatches: matches[1:],
+		}, nil
+	}
+
+	// We weren't in a project directory, so our final resort is to use the
+	// current workspace, and assume all projects should be started.
+	return contextResolution{
+		Workspace: c.cfg.GetCurrentWorkspace(),
+		Reason:    fmt.Sprintf("nothing was given, and the working directory %s is not within a workspace or project, so the current workspace is used", dir),
+	}, nil
+}
+
+func (c *Controller) resolveContext(ws string, project string) (runtimeContext, error) {
+	resolution, err := c.resolveContextNames(contextNames{
+		Workspace: ws,
+		Project:   project,
+	})
+
+	if err != nil {
+		return runtimeContext{}, err
+	}
+
+	return c.buildRuntimeContext(resolution.Workspace, resolution.Project)
+}
+
+type DebugContextDTO struct {
+	Workspace string
+	Project   string
+
+	// WorkspaceFromEnv and ProjectFromEnv are set when the options were taken
+	// from ORCA_WORKSPACE and ORCA_PROJECT, so the reason can say so.
+	WorkspaceFromEnv bool
+	ProjectFromEnv   bool
+}
+
+// DebugContext shows which workspace and project would be used, and why.
+func (c *Controller) DebugContext(dto DebugContextDTO) error {
+	resolution, err := c.resolveContextNames(contextNames{
+		Workspace:        dto.Workspace,
+		Project:          dto.Project,
+		WorkspaceFromEnv: dto.WorkspaceFromEnv,
+		ProjectFromEnv:   dto.ProjectFromEnv,
+	})
+
+	if err != nil {
+		return c.tui.RecordIfError("Failed to resolve the context!", err)
+	}
+
+	project := resolution.Project
+	if project == "" {
+		project = "(all projects)"
+	}
+
+	workspace := resolution.Workspace
+	if workspace == "" {
+		workspace = "(none)"
+	}
+
+	c.tui.Info(
+		fmt.Sprintf("Workspace: %s", workspace),
+		fmt.Sprintf("Project:   %s", project),
+		fmt.Sprintf("Because %s.", resolution.Reason),
+	)
+
+	if len(resolution.OtherMatches) > 0 {
+		c.tui.NewLine()
+		c.tui.Info("Also within, but less specific:")
+
+		for _, m := range resolution.OtherMatches {
+			c.tui.Info(fmt.Sprintf("  %s", m.describe()))
+		}
+	}
+
+	return nil
+}
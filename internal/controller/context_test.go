@@ -0,0 +1,358 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matchDirectory(t *testing.T) {
+	workspaces := []common.WorkspaceMeta{
+		{
+			Name: "main",
+			Path: "/src/orca.workspace.yaml",
+		},
+		{
+			Name: "nested",
+			Path: "/src/api/nested/ws/orca.workspace.yaml",
+		},
+	}
+
+	projects := []common.ProjectMeta{
+		{
+			Name:          "api",
+			Path:          "/src/api",
+			WorkspaceName: "main",
+		},
+		{
+			Name:          "api-gateway",
+			Path:          "/src/api-gateway",
+			WorkspaceName: "main",
+		},
+		{
+			Name:          "plugin",
+			Path:          "/src/api/plugins/plugin",
+			WorkspaceName: "main",
+		},
+		{
+			Name:          "self",
+			Path:          "/src/api/nested/ws/",
+			WorkspaceName: "nested",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		dir    string
+		expect []directoryMatch
+	}{
+		{
+			name: "project with a shared prefix is not matched",
+			dir:  "/src/api-gateway/cmd",
+			expect: []directoryMatch{
+				{Workspace: "main", Project: "api-gateway", Path: "/src/api-gateway"},
+				{Workspace: "main", Path: "/src"},
+			},
+		},
+		{
+			name: "project root",
+			dir:  "/src/api",
+			expect: []directoryMatch{
+				{Workspace: "main", Project: "api", Path: "/src/api"},
+				{Workspace: "main", Path: "/src"},
+			},
+		},
+		{
+			name: "nested project is more specific",
+			dir:  "/src/api/plugins/plugin/internal",
+			expect: []directoryMatch{
+				{Workspace: "main", Project: "plugin", Path: "/src/api/plugins/plugin"},
+				{Workspace: "main", Project: "api", Path: "/src/api"},
+				{Workspace: "main", Path: "/src"},
+			},
+		},
+		{
+			name: "project in the workspace config directory wins",
+			dir:  "/src/api/nested/ws",
+			expect: []directoryMatch{
+				{Workspace: "nested", Project: "self", Path: "/src/api/nested/ws"},
+				{Workspace: "nested", Path: "/src/api/nested/ws"},
+				{Workspace: "main", Project: "api", Path: "/src/api"},
+				{Workspace: "main", Path: "/src"},
+			},
+		},
+		{
+			name: "workspace config directory",
+			dir:  "/src/docs",
+			expect: []directoryMatch{
+				{Workspace: "main", Path: "/src"},
+			},
+		},
+		{
+			name:   "outside of everything",
+			dir:    "/home/someone",
+			expect: []directoryMatch{},
+		},
+		{
+			name:   "parent of everything",
+			dir:    "/",
+			expect: []directoryMatch{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert.Equal(tt, test.expect, matchDirectory(test.dir, workspaces, projects))
+		})
+	}
+}
+
+var testEnvOptions = []EnvOption{
+	{Name: "workspace", Env: "ORCA_WORKSPACE"},
+	{Name: "project", Env: "ORCA_PROJECT"},
+	{Name: "service", Env: "ORCA_SERVICE"},
+}
+
+// withGiven marks the named options as given on the command line.
+func withGiven(opts []EnvOption, given ...string) []EnvOption {
+	out := []EnvOption{}
+
+	for _, opt := range opts {
+		for _, name := range given {
+			if opt.Name == name {
+				opt.Given = true
+			}
+		}
+
+		out = append(out, opt)
+	}
+
+	return out
+}
+
+func Test_ResolveEnvOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []EnvOption
+		env    map[string]string
+		expect map[string]string
+	}{
+		{
+			name:   "nothing set",
+			opts:   testEnvOptions,
+			expect: map[string]string{},
+		},
+		{
+			name: "all from the environment",
+			opts: testEnvOptions,
+			env: map[string]string{
+				"ORCA_WORKSPACE": "main",
+				"ORCA_PROJECT":   "api",
+				"ORCA_SERVICE":   "php",
+			},
+			expect: map[string]string{
+				"workspace": "main",
+				"project":   "api",
+				"service":   "php",
+			},
+		},
+		{
+			name: "given option takes precedence",
+			opts: withGiven(testEnvOptions, "service"),
+			env: map[string]string{
+				"ORCA_WORKSPACE": "main",
+				"ORCA_PROJECT":   "api",
+				"ORCA_SERVICE":   "php",
+			},
+			expect: map[string]string{
+				"workspace": "main",
+				"project":   "api",
+			},
+		},
+		{
+			name: "narrower options ignored once the workspace is given",
+			opts: withGiven(testEnvOptions, "workspace"),
+			env: map[string]string{
+				"ORCA_WORKSPACE": "main",
+				"ORCA_PROJECT":   "api",
+				"ORCA_SERVICE":   "php",
+			},
+			expect: map[string]string{},
+		},
+		{
+			name: "service ignored once the project is given",
+			opts: withGiven(testEnvOptions, "project"),
+			env: map[string]string{
+				"ORCA_WORKSPACE": "main",
+				"ORCA_PROJECT":   "api",
+				"ORCA_SERVICE":   "php",
+			},
+			expect: map[string]string{
+				"workspace": "main",
+			},
+		},
+		{
+			name: "command without a workspace option",
+			opts: testEnvOptions[1:],
+			env: map[string]string{
+				"ORCA_WORKSPACE": "main",
+				"ORCA_PROJECT":   "api",
+			},
+			expect: map[string]string{
+				"project": "api",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			getenv := func(k string) string { return test.env[k] }
+
+			assert.Equal(tt, test.expect, ResolveEnvOptions(test.opts, getenv))
+		})
+	}
+}
+
+type contextConfig struct {
+	config
+
+	workspaces []common.WorkspaceMeta
+	projects   []common.ProjectMeta
+	current    string
+}
+
+func (c contextConfig) GetAllWorkspaceMeta() []common.WorkspaceMeta {
+	return c.workspaces
+}
+
+func (c contextConfig) GetAllProjectMeta() []common.ProjectMeta {
+	return c.projects
+}
+
+func (c contextConfig) GetCurrentWorkspace() string {
+	return c.current
+}
+
+// Test_resolveContextNames_Precedence runs the options through the same steps
+// as a command does: anything not given is taken from the environment, then
+// the working directory, and finally the current workspace.
+func Test_resolveContextNames_Precedence(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.Nil(t, err)
+
+	c := &Controller{
+		cfg: contextConfig{
+			projects: []common.ProjectMeta{
+				{Name: "api", Path: filepath.Join(root, "api"), WorkspaceName: "main"},
+			},
+			current: "current",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		given   map[string]string
+		env     map[string]string
+		expectW string
+		expectP string
+		reason  string
+	}{
+		{
+			name:  "flag over the environment",
+			dir:   filepath.Join(root, "api"),
+			given: map[string]string{"workspace": "flagged"},
+			env: map[string]string{
+				"ORCA_WORKSPACE": "env",
+				"ORCA_PROJECT":   "web",
+			},
+			expectW: "flagged",
+			reason:  "the workspace was given, so all of its projects are used",
+		},
+		{
+			name:  "flag and the environment",
+			dir:   filepath.Join(root, "api"),
+			given: map[string]string{"project": "web"},
+			env: map[string]string{
+				"ORCA_WORKSPACE": "env",
+			},
+			expectW: "env",
+			expectP: "web",
+			reason:  "the workspace was set by ORCA_WORKSPACE, and the project was given",
+		},
+		{
+			name: "environment over the working directory",
+			dir:  filepath.Join(root, "api"),
+			env: map[string]string{
+				"ORCA_WORKSPACE": "env",
+				"ORCA_PROJECT":   "web",
+			},
+			expectW: "env",
+			expectP: "web",
+			reason:  "the workspace was set by ORCA_WORKSPACE, and the project was set by ORCA_PROJECT",
+		},
+		{
+			name: "project from the environment",
+			dir:  filepath.Join(root, "api"),
+			env: map[string]string{
+				"ORCA_PROJECT": "web",
+			},
+			expectW: "current",
+			expectP: "web",
+			reason:  "the project was set by ORCA_PROJECT, so the current workspace is used",
+		},
+		{
+			name:    "working directory over the current workspace",
+			dir:     filepath.Join(root, "api"),
+			expectW: "main",
+			expectP: "api",
+			reason:  "the working directory " + filepath.Join(root, "api") + " is within project 'api'",
+		},
+		{
+			name:    "current workspace",
+			dir:     root,
+			expectW: "current",
+			reason:  "nothing was given",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			require.Nil(tt, os.MkdirAll(test.dir, 0755))
+			tt.Chdir(test.dir)
+
+			opts := []EnvOption{}
+			for _, opt := range testEnvOptions[:2] {
+				_, opt.Given = test.given[opt.Name]
+				opts = append(opts, opt)
+			}
+
+			values := ResolveEnvOptions(opts, func(k string) string { return test.env[k] })
+			names := contextNames{
+				Workspace:        values["workspace"],
+				Project:          values["project"],
+				WorkspaceFromEnv: values["workspace"] != "",
+				ProjectFromEnv:   values["project"] != "",
+			}
+
+			if ws, ok := test.given["workspace"]; ok {
+				names.Workspace = ws
+			}
+
+			if project, ok := test.given["project"]; ok {
+				names.Project = project
+			}
+
+			resolution, err := c.resolveContextNames(names)
+
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expectW, resolution.Workspace)
+			assert.Equal(tt, test.expectP, resolution.Project)
+			assert.Contains(tt, resolution.Reason, test.reason)
+		})
+	}
+}
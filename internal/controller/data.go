@@ -0,0 +1,501 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+const (
+	confirmYes = "Yes"
+	confirmNo  = "No"
+)
+
+type DataSnapshotDTO struct {
+	Workspace string
+	Project   string
+
+	// Name defaults to when the snapshot was taken.
+	Name string
+
+	// Force replaces an existing snapshot with the same name.
+	Force bool
+}
+
+type DataRestoreDTO struct {
+	Workspace string
+	Project   string
+	Name      string
+
+	SkipConfirmation bool
+
+	// Interactive is whether the user can be asked to confirm.
+	Interactive bool
+}
+
+type DataResetDTO struct {
+	Workspace string
+	Project   string
+
+	SkipConfirmation bool
+
+	// Interactive is whether the user can be asked to confirm.
+	Interactive bool
+}
+
+type DataLsDTO struct {
+	Workspace string
+}
+
+// dataProjects works out whose volumes to use. Across the whole workspace,
+// only the projects that have been cloned are included, as the volumes come
+// from their compose files.
+func (c *Controller) dataProjects(wsName string, projectName string) (*common.Workspace, []*common.Project, error) {
+	rc, err := c.resolveContext(wsName, projectName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rc.Project != nil {
+		if !rc.Project.IsRegistered {
+			return nil, nil, common.ErrInvalidExecutionContext{
+				Msg: fmt.Sprintf("project '%s' has not been cloned", rc.Project.Name),
+			}
+		}
+
+		return rc.Workspace, []*common.Project{rc.Project}, nil
+	}
+
+	projects := []*common.Project{}
+
+	for i := range rc.Workspace.Projects {
+		if rc.Workspace.Projects[i].IsRegistered {
+			projects = append(projects, &rc.Workspace.Projects[i])
+		}
+	}
+
+	return rc.Workspace, projects, nil
+}
+
+// mustBeStopped checks none of the projects are running, as their volumes
+// can't be safely copied, or replaced, while they're in use.
+func (c *Controller) mustBeStopped(ctx context.Context, ws *common.Workspace, projects []*common.Project) error {
+	for _, p := range projects {
+		running, err := c.compose.IsRunning(ctx, ws, p)
+
+		if err != nil {
+			return err
+		}
+
+		if running {
+			return c.tui.RecordIfError(
+				fmt.Sprintf("%s:%s is running, stop it first with 'orca down'!", p.Name, ws.Name),
+				common.ErrProjectIsRunning{Project: p.Name},
+			)
+		}
+	}
+
+	return nil
+}
+
+// confirm asks the user before anything is lost, returning false if they'd
+// rather not. Without a terminal to ask them in, they have to have confirmed
+// up front.
+func (c *Controller) confirm(title string, interactive bool) (bool, error) {
+	if !interactive {
+		return false, c.tui.RecordIfError("Use --yes to confirm, when not running in a terminal!", common.ErrInvalidExecutionContext{
+			Msg: "confirmation is required, but there is no terminal to ask in",
+		})
+	}
+
+	result, err := c.tui.PresentChoices([]string{
+		confirmNo,
+		confirmYes,
+	}, title)
+
+	if err != nil {
+		if _, ok := err.(common.ErrUserAbortedExecution); ok {
+			return false, err
+		}
+
+		return false, c.tui.RecordIfError("Something went wrong, this is most likely a bug!", err)
+	}
+
+	if result == confirmNo {
+		c.tui.Info("Aborting")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// recordIfSnapshotError explains why the snapshot can't be used, as only the
+// message is shown to the user.
+func (c *Controller) recordIfSnapshotError(name string, err error) error {
+	switch err.(type) {
+	case common.ErrInvalidInput:
+		return c.tui.RecordIfError(fmt.Sprintf("'%s' can't be used as a snapshot name, only letters, numbers, '.', '_' and '-' can!", name), err)
+	case common.ErrUnknownSnapshot:
+		return c.tui.RecordIfError(fmt.Sprintf("There is no snapshot '%s', use 'orca data ls' to see those there are!", name), err)
+	case common.ErrSnapshotExists:
+		return c.tui.RecordIfError(fmt.Sprintf("Snapshot '%s' already exists, use --force to replace it!", name), err)
+	}
+
+	return c.tui.RecordIfError(fmt.Sprintf("Failed to read snapshot '%s'!", name), err)
+}
+
+func describeDataScope(ws *common.Workspace, projects []*common.Project) string {
+	if len(projects) == 1 {
+		return fmt.Sprintf("%s:%s", projects[0].Name, ws.Name)
+	}
+
+	return fmt.Sprintf("every project in %s", ws.Name)
+}
+
+func (c *Controller) exportVolume(ctx context.Context, ws *common.Workspace, p *common.Project, name string, vol common.Volume) error {
+	w, err := c.snapshots.Create(ws.Name, name, p.Name, vol.Key)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.volumes.Export(ctx, ws, vol, w)
+
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// snapshotProjects saves the volumes of each project, returning the keys of
+// those which were saved. Volumes that haven't been created yet are skipped,
+// so they're removed again on restore.
+func (c *Controller) snapshotProjects(ctx context.Context, ws *common.Workspace, projects []*common.Project, name string) (map[string][]string, error) {
+	saved := map[string][]string{}
+
+	for _, p := range projects {
+		declared, err := c.volumes.Declared(ws, p)
+
+		if err != nil {
+			return nil, c.tui.RecordIfError(fmt.Sprintf("%s:%s failed to read the compose file!", p.Name, ws.Name), err)
+		}
+
+		saved[p.Name] = []string{}
+
+		for _, vol := range declared {
+			exists, err := c.volumes.Exists(ctx, ws, vol)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if !exists {
+				c.tui.Info(fmt.Sprintf("%s:%s volume '%s' hasn't been created yet, skipping.", p.Name, ws.Name, vol.Key))
+				continue
+			}
+
+			c.tui.Info(fmt.Sprintf("%s:%s saving volume '%s'...", p.Name, ws.Name, vol.Key))
+
+			if err := c.exportVolume(ctx, ws, p, name, vol); err != nil {
+				return nil, err
+			}
+
+			saved[p.Name] = append(saved[p.Name], vol.Key)
+		}
+	}
+
+	return saved, nil
+}
+
+// DataSnapshot saves the contents of the named volumes of a project, or of
+// every project in the workspace, so they can be restored later.
+func (c *Controller) DataSnapshot(ctx context.Context, dto DataSnapshotDTO) error {
+	ws, projects, err := c.dataProjects(dto.Workspace, dto.Project)
+
+	if err != nil {
+		return err
+	}
+
+	name := dto.Name
+
+	if name == "" {
+		name = c.snapshots.NewName()
+	}
+
+	exists, err := c.snapshots.Exists(ws.Name, name)
+
+	if err != nil {
+		return c.recordIfSnapshotError(name, err)
+	}
+
+	if exists && !dto.Force {
+		return c.recordIfSnapshotError(name, common.ErrSnapshotExists{
+			Workspace: ws.Name,
+			Name:      name,
+		})
+	}
+
+	if err := c.mustBeStopped(ctx, ws, projects); err != nil {
+		return err
+	}
+
+	// The existing snapshot is only replaced once the new one is complete, so
+	// it isn't lost if taking the new one fails
+	into := name
+
+	if exists {
+		into = c.snapshots.TempName(name)
+	}
+
+	saved, err := c.snapshotProjects(ctx, ws, projects, into)
+
+	if err == nil {
+		err = c.snapshots.Save(ws.Name, into, saved)
+	}
+
+	if err == nil && exists {
+		err = c.snapshots.Replace(ws.Name, into, name)
+	}
+
+	if err != nil {
+		// Don't leave a partial snapshot behind, it can't be restored anyway
+		if removeErr := c.snapshots.Remove(ws.Name, into); removeErr != nil {
+			c.tui.Error(fmt.Sprintf("Failed to remove the incomplete snapshot: %s", removeErr.Error()))
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return c.tui.RecordIfError(fmt.Sprintf("Failed to take snapshot '%s'!", name), err)
+	}
+
+	c.tui.Success(fmt.Sprintf("Saved snapshot '%s' of %s.", name, describeDataScope(ws, projects)))
+
+	return nil
+}
+
+func (c *Controller) importVolume(ctx context.Context, ws *common.Workspace, p *common.Project, name string, vol common.Volume) error {
+	r, err := c.snapshots.Open(ws.Name, name, p.Name, vol.Key)
+
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	return c.volumes.Import(ctx, ws, p, vol, r)
+}
+
+// restoreProject puts the project's volumes back how they were in the
+// snapshot. Any that didn't exist when it was taken are removed.
+func (c *Controller) restoreProject(ctx context.Context, ws *common.Workspace, p *common.Project, name string, saved []string) error {
+	declared, err := c.volumes.Declared(ws, p)
+
+	if err != nil {
+		return c.tui.RecordIfError(fmt.Sprintf("%s:%s failed to read the compose file!", p.Name, ws.Name), err)
+	}
+
+	for _, vol := range declared {
+		if slices.Contains(saved, vol.Key) {
+			c.tui.Info(fmt.Sprintf("%s:%s restoring volume '%s'...", p.Name, ws.Name, vol.Key))
+
+			if err := c.importVolume(ctx, ws, p, name, vol); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := c.removeVolume(ctx, ws, p, vol); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range saved {
+		if !slices.ContainsFunc(declared, func(vol common.Volume) bool { return vol.Key == key }) {
+			c.tui.Error(fmt.Sprintf("%s:%s volume '%s' is no longer in the compose file, skipping.", p.Name, ws.Name, key))
+		}
+	}
+
+	return nil
+}
+
+// DataRestore replaces the contents of the volumes with those saved in a
+// snapshot.
+func (c *Controller) DataRestore(ctx context.Context, dto DataRestoreDTO) error {
+	ws, projects, err := c.dataProjects(dto.Workspace, dto.Project)
+
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := c.snapshots.Load(ws.Name, dto.Name)
+
+	if err != nil {
+		return c.recordIfSnapshotError(dto.Name, err)
+	}
+
+	inSnapshot := slices.ContainsFunc(projects, func(p *common.Project) bool {
+		_, ok := snapshot.Projects[p.Name]
+
+		return ok
+	})
+
+	if !inSnapshot {
+		return c.tui.RecordIfError("Nothing to restore!", common.ErrInvalidExecutionContext{
+			Msg: fmt.Sprintf("none of the projects are in snapshot '%s'", snapshot.Name),
+		})
+	}
+
+	if err := c.mustBeStopped(ctx, ws, projects); err != nil {
+		return err
+	}
+
+	if !dto.SkipConfirmation {
+		ok, err := c.confirm(fmt.Sprintf("The data in %s will be replaced, are you sure?", describeDataScope(ws, projects)), dto.Interactive)
+
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	for _, p := range projects {
+		saved, ok := snapshot.Projects[p.Name]
+
+		if !ok {
+			c.tui.Info(fmt.Sprintf("%s:%s isn't in the snapshot, skipping.", p.Name, ws.Name))
+			continue
+		}
+
+		if err := c.restoreProject(ctx, ws, p, snapshot.Name, saved); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return c.tui.RecordIfError(fmt.Sprintf("%s:%s failed to restore snapshot '%s'!", p.Name, ws.Name, snapshot.Name), err)
+		}
+	}
+
+	c.tui.Success(fmt.Sprintf("Restored snapshot '%s' to %s.", snapshot.Name, describeDataScope(ws, projects)))
+
+	return nil
+}
+
+func (c *Controller) removeVolume(ctx context.Context, ws *common.Workspace, p *common.Project, vol common.Volume) error {
+	exists, err := c.volumes.Exists(ctx, ws, vol)
+
+	if err != nil || !exists {
+		return err
+	}
+
+	c.tui.Info(fmt.Sprintf("%s:%s removing volume '%s'...", p.Name, ws.Name, vol.Key))
+
+	return c.volumes.Remove(ctx, ws, vol)
+}
+
+// DataReset removes the named volumes, so the projects start from scratch
+// the next time they're started.
+func (c *Controller) DataReset(ctx context.Context, dto DataResetDTO) error {
+	ws, projects, err := c.dataProjects(dto.Workspace, dto.Project)
+
+	if err != nil {
+		return err
+	}
+
+	if err := c.mustBeStopped(ctx, ws, projects); err != nil {
+		return err
+	}
+
+	if !dto.SkipConfirmation {
+		ok, err := c.confirm(fmt.Sprintf("The data in %s will be deleted, are you sure?", describeDataScope(ws, projects)), dto.Interactive)
+
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	for _, p := range projects {
+		declared, err := c.volumes.Declared(ws, p)
+
+		if err != nil {
+			return c.tui.RecordIfError(fmt.Sprintf("%s:%s failed to read the compose file!", p.Name, ws.Name), err)
+		}
+
+		for _, vol := range declared {
+			if err := c.removeVolume(ctx, ws, p, vol); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				return c.tui.RecordIfError(fmt.Sprintf("%s:%s failed to remove volume '%s'!", p.Name, ws.Name, vol.Key), err)
+			}
+		}
+	}
+
+	c.tui.Success(fmt.Sprintf("Reset the data in %s.", describeDataScope(ws, projects)))
+
+	return nil
+}
+
+// formatSize shows a number of bytes in the largest unit that keeps it above
+// one.
+func formatSize(bytes int64) string {
+	size := float64(bytes)
+
+	for _, unit := range []string{"B", "KB", "MB", "GB"} {
+		if size < 1024 {
+			if unit == "B" {
+				return fmt.Sprintf("%d%s", bytes, unit)
+			}
+
+			return fmt.Sprintf("%.1f%s", size, unit)
+		}
+
+		size /= 1024
+	}
+
+	return fmt.Sprintf("%.1fTB", size)
+}
+
+// DataLs lists the snapshots of the workspace, oldest first.
+func (c *Controller) DataLs(dto DataLsDTO) error {
+	rc, err := c.resolveContext(dto.Workspace, "")
+
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := c.snapshots.List(rc.Workspace.Name)
+
+	if err != nil {
+		return c.tui.RecordIfError("Failed to list snapshots!", err)
+	}
+
+	if len(snapshots) == 0 {
+		c.tui.Info(fmt.Sprintf("There are no snapshots of %s yet, take one with 'orca data snapshot'.", rc.Workspace.Name))
+		return nil
+	}
+
+	rows := [][]string{}
+
+	for _, s := range snapshots {
+		rows = append(rows, []string{
+			s.Name,
+			s.Created.Local().Format(time.DateTime),
+			strings.Join(slices.Sorted(maps.Keys(s.Projects)), ", "),
+			formatSize(s.Size),
+		})
+	}
+
+	c.tui.Table([]string{"NAME", "CREATED", "PROJECTS", "SIZE"}, rows)
+
+	return nil
+}
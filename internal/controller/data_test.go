@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/snapshots"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVolumes keeps the contents of each volume in memory, recording what's
+// done to them.
+type fakeVolumes struct {
+	declared map[string][]common.Volume
+	contents map[string]string
+	calls    []string
+
+	// failExport makes exporting the named volume fail.
+	failExport string
+}
+
+func (v *fakeVolumes) Declared(ws *common.Workspace, p *common.Project) ([]common.Volume, error) {
+	return v.declared[p.Name], nil
+}
+
+func (v *fakeVolumes) Exists(ctx context.Context, ws *common.Workspace, vol common.Volume) (bool, error) {
+	_, ok := v.contents[vol.Name]
+
+	return ok, nil
+}
+
+func (v *fakeVolumes) Export(ctx context.Context, ws *common.Workspace, vol common.Volume, w io.Writer) error {
+	v.calls = append(v.calls, "export "+vol.Name)
+
+	if vol.Name == v.failExport {
+		return errors.New("exit status 1")
+	}
+
+	_, err := io.WriteString(w, v.contents[vol.Name])
+
+	return err
+}
+
+func (v *fakeVolumes) Import(ctx context.Context, ws *common.Workspace, p *common.Project, vol common.Volume, r io.Reader) error {
+	contents, err := io.ReadAll(r)
+
+	if err != nil {
+		return err
+	}
+
+	v.calls = append(v.calls, fmt.Sprintf("import %s: %s", vol.Name, contents))
+	v.contents[vol.Name] = string(contents)
+
+	return nil
+}
+
+func (v *fakeVolumes) Remove(ctx context.Context, ws *common.Workspace, vol common.Volume) error {
+	v.calls = append(v.calls, "remove "+vol.Name)
+	delete(v.contents, vol.Name)
+
+	return nil
+}
+
+type runningCompose struct {
+	compose
+
+	running []string
+}
+
+func (c runningCompose) IsRunning(ctx context.Context, ws *common.Workspace, p *common.Project) (bool, error) {
+	return slices.Contains(c.running, p.Name), nil
+}
+
+func Test_snapshotAndRestore(t *testing.T) {
+	ws := &common.Workspace{
+		Name: "test",
+		Projects: []common.Project{
+			{Name: "api"},
+		},
+	}
+	api := &ws.Projects[0]
+
+	volumes := &fakeVolumes{
+		declared: map[string][]common.Volume{
+			"api": {
+				{Key: "cache", Name: "orca-test-api_cache"},
+				{Key: "pgdata", Name: "orca-test-api_pgdata"},
+			},
+		},
+		contents: map[string]string{
+			"orca-test-api_pgdata": "seeded",
+		},
+	}
+
+	c := &Controller{
+		tui:       &recordingTui{},
+		volumes:   volumes,
+		snapshots: snapshots.NewStore(afero.NewMemMapFs(), "/snapshots"),
+	}
+
+	saved, err := c.snapshotProjects(context.Background(), ws, []*common.Project{api}, "seeded")
+
+	require.Nil(t, err)
+	// The cache hasn't been created yet
+	assert.Equal(t, map[string][]string{"api": {"pgdata"}}, saved)
+
+	// Then the project is used, changing the data
+	volumes.contents = map[string]string{
+		"orca-test-api_cache":  "cached",
+		"orca-test-api_pgdata": "changed",
+	}
+	volumes.calls = nil
+
+	require.Nil(t, c.restoreProject(context.Background(), ws, api, "seeded", saved["api"]))
+
+	assert.Equal(t, []string{
+		"remove orca-test-api_cache",
+		"import orca-test-api_pgdata: seeded",
+	}, volumes.calls)
+	assert.Equal(t, map[string]string{"orca-test-api_pgdata": "seeded"}, volumes.contents)
+}
+
+type stubConfig struct {
+	config
+}
+
+func (stubConfig) GetWorkspaceMeta(name string) (common.WorkspaceMeta, error) {
+	return common.WorkspaceMeta{Name: name}, nil
+}
+
+type stubWorkspaceRepo struct {
+	workspaceRepository
+
+	ws *common.Workspace
+}
+
+func (r stubWorkspaceRepo) Load(name string) (*common.Workspace, error) {
+	return r.ws, nil
+}
+
+func Test_DataSnapshot_ForceKeepsOldOnFailure(t *testing.T) {
+	ws := &common.Workspace{
+		Name: "test",
+		Projects: []common.Project{
+			{Name: "api", IsRegistered: true},
+		},
+	}
+
+	volumes := &fakeVolumes{
+		declared: map[string][]common.Volume{
+			"api": {
+				{Key: "pgdata", Name: "orca-test-api_pgdata"},
+			},
+		},
+		contents: map[string]string{
+			"orca-test-api_pgdata": "seeded",
+		},
+	}
+
+	store := snapshots.NewStore(afero.NewMemMapFs(), "/snapshots")
+
+	c := &Controller{
+		cfg:           stubConfig{},
+		workspaceRepo: stubWorkspaceRepo{ws: ws},
+		compose:       runningCompose{},
+		tui:           &recordingTui{},
+		volumes:       volumes,
+		snapshots:     store,
+	}
+
+	dto := DataSnapshotDTO{
+		Workspace: "test",
+		Name:      "seeded",
+		Force:     true,
+	}
+
+	require.Nil(t, c.DataSnapshot(context.Background(), dto))
+
+	volumes.contents["orca-test-api_pgdata"] = "changed"
+	volumes.failExport = "orca-test-api_pgdata"
+
+	assert.NotNil(t, c.DataSnapshot(context.Background(), dto))
+
+	list, err := store.List("test")
+	require.Nil(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "seeded", list[0].Name)
+
+	r, err := store.Open("test", "seeded", "api", "pgdata")
+	require.Nil(t, err)
+	defer r.Close()
+
+	contents, err := io.ReadAll(r)
+	require.Nil(t, err)
+	assert.Equal(t, "seeded", string(contents))
+
+	volumes.failExport = ""
+
+	require.Nil(t, c.DataSnapshot(context.Background(), dto))
+
+	require.Nil(t, c.restoreProject(context.Background(), ws, &ws.Projects[0], "seeded", []string{"pgdata"}))
+	assert.Equal(t, "changed", volumes.contents["orca-test-api_pgdata"])
+}
+
+func Test_mustBeStopped(t *testing.T) {
+	ws := &common.Workspace{
+		Name: "test",
+		Projects: []common.Project{
+			{Name: "db"},
+			{Name: "api"},
+		},
+	}
+	projects := []*common.Project{&ws.Projects[0], &ws.Projects[1]}
+
+	c := &Controller{
+		compose: runningCompose{running: []string{"api"}},
+		tui:     &recordingTui{},
+	}
+
+	err := c.mustBeStopped(context.Background(), ws, projects)
+
+	assert.Equal(t, common.ErrProjectIsRunning{Project: "api"}, err)
+
+	c.compose = runningCompose{}
+
+	assert.Nil(t, c.mustBeStopped(context.Background(), ws, projects))
+}
+
+func Test_formatSize(t *testing.T) {
+	tests := []struct {
+		bytes  int64
+		expect string
+	}{
+		{bytes: 0, expect: "0B"},
+		{bytes: 1023, expect: "1023B"},
+		{bytes: 1536, expect: "1.5KB"},
+		{bytes: 5 * 1024 * 1024, expect: "5.0MB"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expect, func(tt *testing.T) {
+			assert.Equal(tt, test.expect, formatSize(test.bytes))
+		})
+	}
+}
@@ -1,22 +1,30 @@
 package controller
 
 import (
+	"context"
+	"slices"
+
+	"github.com/panoptescloud/orca/internal/common"
 	"github.com/panoptescloud/orca/pkg/dag"
 )
 
 type DownDTO struct {
 	Workspace string
 	Project   string
+	Profile   string
+
+	// WithDependants stops everything that requires the project first.
+	WithDependants bool
 }
 
-func determineShutdownOrder(ctx runtimeContext) ([]string, error) {
-	if ctx.Project != nil {
+func determineShutdownOrder(rc runtimeContext) ([]string, error) {
+	if rc.Project != nil {
 		return []string{
-			ctx.Project.Name,
+			rc.Project.Name,
 		}, nil
 	}
 
-	g, err := dag.NewGraph(ctx.Workspace.Projects)
+	g, err := dag.NewGraph(rc.Workspace.Projects)
 
 	if err != nil {
 		return nil, err
@@ -25,36 +33,186 @@ func determineShutdownOrder(ctx runtimeContext) ([]string, error) {
 	return g.TopologicalKeysFromLeaves()
 }
 
-func (c *Controller) stopServices(ctx runtimeContext) error {
-	ordered, err := determineShutdownOrder(ctx)
+func determineShutdownOrderWithDependants(ws *common.Workspace, project string) ([]string, error) {
+	g, err := dag.NewGraph(ws.Projects)
+
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := g.Descendants(project)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := g.Subgraph(append(descendants, project))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sub.TopologicalKeysFromLeaves()
+}
+
+// determineProfileShutdownOrder works out which of the projects started by
+// the profile can be stopped. Anything still required by a running project
+// outside of the profile is left alone.
+func (c *Controller) determineProfileShutdownOrder(ctx context.Context, ws *common.Workspace, profile string) ([]string, error) {
+	g, err := dag.NewGraph(ws.Projects)
+
+	if err != nil {
+		return nil, err
+	}
+
+	closure, err := profileClosure(ws, g, profile)
 
 	if err != nil {
+		return nil, err
+	}
+
+	stillNeeded := []string{}
+
+	for _, p := range ws.Projects {
+		if slices.Contains(closure, p.Name) {
+			continue
+		}
+
+		running, err := c.compose.IsRunning(ctx, ws, &p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !running {
+			continue
+		}
+
+		ancestors, err := g.Ancestors(p.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		stillNeeded = append(stillNeeded, ancestors...)
+	}
+
+	toStop := slices.DeleteFunc(closure, func(name string) bool {
+		return slices.Contains(stillNeeded, name)
+	})
+
+	sub, err := g.Subgraph(toStop)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sub.TopologicalKeysFromLeaves()
+}
+
+// stopProject stops a single project, along with its own hooks.
+func (c *Controller) stopProject(ctx context.Context, ws *common.Workspace, p *common.Project) error {
+	if err := c.runProjectHooks(ctx, ws, p, common.HookPreDown); err != nil {
+		return err
+	}
+
+	if err := c.compose.Down(ctx, ws, p); err != nil {
+		return err
+	}
+
+	return c.runProjectHooks(ctx, ws, p, common.HookPostDown)
+}
+
+func (c *Controller) stopProjects(ctx context.Context, ws *common.Workspace, ordered []string) error {
+	if err := c.runWorkspaceHooks(ctx, ws, common.HookPreDown); err != nil {
+		if ctx.Err() != nil {
+			return c.reportInterrupted(ctx, "stopped", ordered, 0, false)
+		}
+
 		return err
 	}
 
-	for _, p := range ordered {
+	for i, p := range ordered {
+		if ctx.Err() != nil {
+			return c.reportInterrupted(ctx, "stopped", ordered, i, false)
+		}
+
 		// This shouldn't ever really return an error at this point, if it does
 		// something went wrong while building the runtime context
-		projectConfig, err := ctx.Workspace.GetProject(p)
+		projectConfig, err := ws.GetProject(p)
 
 		if err != nil {
 			return err
 		}
 
-		if err := c.compose.Down(ctx.Workspace, projectConfig); err != nil {
+		if err := c.stopProject(ctx, ws, projectConfig); err != nil {
+			if ctx.Err() != nil {
+				return c.reportInterrupted(ctx, "stopped", ordered, i, true)
+			}
+
 			return err
 		}
 	}
 
+	if err := c.runWorkspaceHooks(ctx, ws, common.HookPostDown); err != nil {
+		if ctx.Err() != nil {
+			return c.reportInterrupted(ctx, "stopped", ordered, len(ordered), false)
+		}
+
+		return err
+	}
+
 	return nil
 }
 
-func (c *Controller) Down(dto DownDTO) error {
-	ctx, err := c.resolveContext(dto.Workspace, dto.Project)
+func (c *Controller) stopServices(ctx context.Context, rc runtimeContext) error {
+	ordered, err := determineShutdownOrder(rc)
+
+	if err != nil {
+		return err
+	}
+
+	return c.stopProjects(ctx, rc.Workspace, ordered)
+}
+
+func (c *Controller) Down(ctx context.Context, dto DownDTO) error {
+	if dto.Profile != "" {
+		rc, err := c.resolveProfileContext(dto.Workspace, dto.Project)
+
+		if err != nil {
+			return err
+		}
+
+		ordered, err := c.determineProfileShutdownOrder(ctx, rc.Workspace, dto.Profile)
+
+		if err != nil {
+			return err
+		}
+
+		return c.stopProjects(ctx, rc.Workspace, ordered)
+	}
+
+	rc, err := c.resolveContext(dto.Workspace, dto.Project)
 
 	if err != nil {
 		return err
 	}
 
-	return c.stopServices(ctx)
+	if dto.WithDependants {
+		if rc.Project == nil {
+			return common.ErrInvalidExecutionContext{
+				Msg: "stopping with dependants must be run in a singular project context",
+			}
+		}
+
+		ordered, err := determineShutdownOrderWithDependants(rc.Workspace, rc.Project.Name)
+
+		if err != nil {
+			return err
+		}
+
+		return c.stopProjects(ctx, rc.Workspace, ordered)
+	}
+
+	return c.stopServices(ctx, rc)
 }
@@ -1,28 +1,96 @@
 package controller
 
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
 type ExecDTO struct {
 	Workspace string
 	Project   string
 	Service   string
 	Args      []string
+
+	User    string
+	Workdir string
+	Env     []string
+	Index   int
+	TTY     bool
+
+	// NoRunFallback fails when the service isn't running, rather than running
+	// the command in a new container.
+	NoRunFallback bool
+}
+
+func validateExecOptions(opts common.ExecOptions) error {
+	for _, env := range opts.Env {
+		if key, _, _ := strings.Cut(env, "="); key == "" {
+			return common.ErrInvalidInput{
+				To:  "exec.env",
+				Msg: fmt.Sprintf("'%s' must be KEY=VAL, or KEY to use the value from the environment", env),
+			}
+		}
+	}
+
+	if opts.Index < 0 {
+		return common.ErrInvalidInput{
+			To:  "exec.index",
+			Msg: "must be 1 or more",
+		}
+	}
+
+	return nil
 }
 
-func (c *Controller) ExecOrRun(dto ExecDTO) error {
-	ctx, err := c.resolveContext(dto.Workspace, dto.Project)
+// execOrRun execs into the service if it's running, otherwise the command is
+// run in a new container. A new container can't be used when a specific one
+// was asked for, or the fallback is disabled.
+func (c *Controller) execOrRun(ctx context.Context, rc runtimeContext, service string, args []string, opts common.ExecOptions, noRunFallback bool) error {
+	if err := validateExecOptions(opts); err != nil {
+		return c.tui.RecordIfError("Invalid options given!", err)
+	}
+
+	isRunning, err := c.compose.IsSvcRunning(ctx, rc.Workspace, rc.Project, service)
 
 	if err != nil {
 		return err
 	}
 
-	isRunning, err := c.compose.IsSvcRunning(ctx.Workspace, ctx.Project, dto.Service)
+	if isRunning {
+		return c.compose.Exec(ctx, rc.Workspace, rc.Project, service, args, opts)
+	}
+
+	if noRunFallback || opts.Index > 0 {
+		return c.tui.RecordIfError(fmt.Sprintf("The '%s' service is not running!", service), common.ErrServiceNotRunning{
+			Project: rc.Project.Name,
+			Service: service,
+		})
+	}
+
+	return c.compose.Run(ctx, rc.Workspace, rc.Project, service, args, opts)
+}
+
+func (c *Controller) ExecOrRun(ctx context.Context, dto ExecDTO) error {
+	rc, err := c.resolveContext(dto.Workspace, dto.Project)
 
 	if err != nil {
 		return err
 	}
 
-	if !isRunning {
-		return c.compose.Run(ctx.Workspace, ctx.Project, dto.Service, dto.Args)
+	if rc.Project == nil {
+		return c.tui.RecordIfError("Commands must be executed within a project context!", common.ErrInvalidExecutionContext{
+			Msg: "exec requires a project",
+		})
 	}
 
-	return c.compose.Exec(ctx.Workspace, ctx.Project, dto.Service, dto.Args)
+	return c.execOrRun(ctx, rc, dto.Service, dto.Args, common.ExecOptions{
+		User:    dto.User,
+		Workdir: dto.Workdir,
+		Env:     dto.Env,
+		Index:   dto.Index,
+		TTY:     dto.TTY,
+	}, dto.NoRunFallback)
 }
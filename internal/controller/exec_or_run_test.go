@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateExecOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      common.ExecOptions
+		expectErr string
+	}{
+		{
+			name: "valid",
+			opts: common.ExecOptions{Env: []string{"APP_ENV=test", "TOKEN", "EMPTY="}, Index: 2},
+		},
+		{
+			name:      "env without a key",
+			opts:      common.ExecOptions{Env: []string{"=test"}},
+			expectErr: "'=test' must be KEY=VAL",
+		},
+		{
+			name:      "negative index",
+			opts:      common.ExecOptions{Index: -1},
+			expectErr: "must be 1 or more",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			err := validateExecOptions(test.opts)
+
+			if test.expectErr == "" {
+				assert.Nil(tt, err)
+				return
+			}
+
+			assert.ErrorContains(tt, err, test.expectErr)
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package controller
 
 import (
-	"errors"
+	"context"
+	"path/filepath"
 	"strings"
 
 	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/hostsys"
 )
 
 type ExecuteExtensionDTO struct {
@@ -12,53 +14,70 @@ type ExecuteExtensionDTO struct {
 	Project   string
 	Name      string
 	Args      []string
+
+	// TTY allocates a TTY in the container, see common.ExecOptions.
+	TTY bool
 }
 
-// TODO: de-dupe this by reusing the ExecOrRun method
-func (c *Controller) executeExtensionInService(dto ExecuteExtensionDTO, ctx runtimeContext, ext common.Extension) error {
-	cmdArgs := strings.Split(ext.Command, " ")
+// extensionDir is where an extension runs on the host, its chdir is relative
+// to the project, or the workspace when there's no project.
+func extensionDir(rc runtimeContext, ext common.Extension) string {
+	dir := filepath.Dir(rc.Workspace.ConfigPath)
 
-	if len(dto.Args) > 0 {
-		cmdArgs = append(cmdArgs, dto.Args...)
-	} else {
-		cmdArgs = append(cmdArgs, ext.DefaultArgs...)
+	if rc.Project != nil {
+		dir = rc.Project.ProjectDir
 	}
 
-	isRunning, err := c.compose.IsSvcRunning(ctx.Workspace, ctx.Project, ext.Service)
+	if ext.Chdir == "" {
+		return dir
+	}
 
-	if err != nil {
-		return err
+	if filepath.IsAbs(ext.Chdir) {
+		return ext.Chdir
 	}
 
-	if !isRunning {
-		return c.compose.Run(ctx.Workspace, ctx.Project, ext.Service, cmdArgs)
+	return filepath.Join(dir, ext.Chdir)
+}
+
+// runExtension runs the extension's command, with args replacing its default
+// arguments if any are given. It's run in the extension's service when it has
+// one, otherwise on the host.
+func (c *Controller) runExtension(ctx context.Context, rc runtimeContext, ext common.Extension, args []string, tty bool) error {
+	cmdArgs := strings.Split(ext.Command, " ")
+
+	if len(args) > 0 {
+		cmdArgs = append(cmdArgs, args...)
+	} else {
+		cmdArgs = append(cmdArgs, ext.DefaultArgs...)
 	}
 
-	return c.compose.Exec(ctx.Workspace, ctx.Project, ext.Service, cmdArgs)
+	if ext.Service != "" {
+		return c.execOrRun(ctx, rc, ext.Service, cmdArgs, common.ExecOptions{
+			TTY: tty,
+		}, false)
+	}
+
+	return c.exec.Exec(ctx, cmdArgs[0], cmdArgs[1:], hostsys.WithHostIO(), hostsys.ChdirOpt(extensionDir(rc, ext)))
 }
 
-func (c *Controller) ExecuteExtension(dto ExecuteExtensionDTO) error {
-	ctx, err := c.resolveContext(dto.Workspace, dto.Project)
+func (c *Controller) ExecuteExtension(ctx context.Context, dto ExecuteExtensionDTO) error {
+	rc, err := c.resolveContext(dto.Workspace, dto.Project)
 
 	if err != nil {
 		return err
 	}
 
-	if ctx.Project == nil {
+	if rc.Project == nil {
 		return common.ErrInvalidExecutionContext{
 			Msg: "Extensions must be executed within a project context!",
 		}
 	}
 
-	ext, err := ctx.Project.FindExtension(dto.Name)
+	ext, err := rc.Project.FindExtension(dto.Name)
 
 	if err != nil {
 		return c.tui.RecordIfError("Extension does not exist in project context.", err)
 	}
 
-	if ext.Service != "" {
-		return c.executeExtensionInService(dto, ctx, ext)
-	}
-
-	return c.tui.RecordIfError("Extension must have a service defined", errors.New("alternative is not yet implemented"))
+	return c.runExtension(ctx, rc, ext, dto.Args, dto.TTY)
 }
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+// hookContext is where the hook runs. A project's hooks run within it, as do
+// the workspace's hooks which name a project.
+func hookContext(ws *common.Workspace, p *common.Project, hook common.Hook) (runtimeContext, error) {
+	rc := runtimeContext{
+		Workspace: ws,
+		Project:   p,
+	}
+
+	if p != nil || hook.Project == "" {
+		return rc, nil
+	}
+
+	project, err := ws.GetProject(hook.Project)
+
+	if err != nil {
+		return runtimeContext{}, err
+	}
+
+	rc.Project = project
+
+	return rc, nil
+}
+
+// execHook runs the hook through the same path as an extension, stopping it
+// if it runs for longer than its timeout.
+func (c *Controller) execHook(ctx context.Context, ws *common.Workspace, p *common.Project, hook common.Hook) error {
+	rc, err := hookContext(ws, p, hook)
+
+	if err != nil {
+		return err
+	}
+
+	hookCtx, cancel := context.WithTimeout(ctx, hook.Timeout)
+	defer cancel()
+
+	err = c.runExtension(hookCtx, rc, common.Extension{
+		Name:    hook.Name,
+		Chdir:   hook.Chdir,
+		Command: hook.Command,
+		Service: hook.Service,
+	}, nil, false)
+
+	if err != nil && errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+		return common.ErrHookTimedOut{
+			Timeout: hook.Timeout,
+		}
+	}
+
+	return err
+}
+
+// runHook runs a single hook. A failure only stops whatever triggered the
+// hook if it's set to abort, however being interrupted always does.
+func (c *Controller) runHook(ctx context.Context, ws *common.Workspace, p *common.Project, event common.HookEvent, hook common.Hook) error {
+	owner := ws.Name
+	if p != nil {
+		owner = fmt.Sprintf("%s:%s", p.Name, ws.Name)
+	}
+
+	c.tui.Info(fmt.Sprintf("%s running %s hook '%s'...", owner, event, hook.DisplayName()))
+
+	err := c.execHook(ctx, ws, p, hook)
+
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	err = common.ErrHookFailed{
+		Event: event,
+		Hook:  hook.DisplayName(),
+		Err:   err,
+	}
+
+	if hook.OnFailure == common.HookWarn {
+		c.tui.Error(fmt.Sprintf("%s, continuing anyway.", err.Error()))
+
+		return nil
+	}
+
+	return c.tui.RecordIfError(fmt.Sprintf("%s %s hook '%s' failed!", owner, event, hook.DisplayName()), err)
+}
+
+func (c *Controller) runHookList(ctx context.Context, ws *common.Workspace, p *common.Project, event common.HookEvent, hooks []common.Hook) error {
+	for _, hook := range hooks {
+		if err := c.runHook(ctx, ws, p, event, hook); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runProjectHooks runs the project's own hooks for the event.
+func (c *Controller) runProjectHooks(ctx context.Context, ws *common.Workspace, p *common.Project, event common.HookEvent) error {
+	return c.runHookList(ctx, ws, p, event, p.Config.Hooks.For(event))
+}
+
+// runWorkspaceHooks runs the hooks from the workspace config for the event.
+func (c *Controller) runWorkspaceHooks(ctx context.Context, ws *common.Workspace, event common.HookEvent) error {
+	return c.runHookList(ctx, ws, nil, event, ws.Hooks.For(event))
+}
+
+// RunHooks runs the hooks for an event that happens outside of the
+// controller, e.g. cloning or switching workspace. The hooks of each project
+// named (or every project, if nil) are run, along with the workspace's own
+// hooks.
+func (c *Controller) RunHooks(ctx context.Context, event common.HookEvent, wsName string, projects []string) error {
+	rc, err := c.buildRuntimeContext(wsName, "")
+
+	if err != nil {
+		return err
+	}
+
+	if projects == nil {
+		for _, p := range rc.Workspace.Projects {
+			projects = append(projects, p.Name)
+		}
+	}
+
+	if event.IsPre() {
+		if err := c.runWorkspaceHooks(ctx, rc.Workspace, event); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range projects {
+		p, err := rc.Workspace.GetProject(name)
+
+		if err != nil {
+			return err
+		}
+
+		// Hooks come from the project config, which isn't available until
+		// it's been cloned
+		if !p.IsRegistered {
+			continue
+		}
+
+		if err := c.runProjectHooks(ctx, rc.Workspace, p, event); err != nil {
+			return err
+		}
+	}
+
+	if !event.IsPre() {
+		return c.runWorkspaceHooks(ctx, rc.Workspace, event)
+	}
+
+	return nil
+}
@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/stretchr/testify/assert"
+)
+
+// hookRecorder records both the commands run on the host, and the projects
+// started (through upRecorder), so the order they happen in can be checked.
+type hookRecorder struct {
+	calls []string
+
+	// fail makes the command fail, and block makes it run until it's stopped.
+	fail  string
+	block string
+}
+
+func (r *hookRecorder) Exec(ctx context.Context, cmdName string, args []string, opts ...hostsys.ExecOpt) error {
+	cmd := strings.Join(append([]string{cmdName}, args...), " ")
+	r.calls = append(r.calls, cmd)
+
+	switch cmd {
+	case r.fail:
+		return errors.New("exit status 1")
+	case r.block:
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+type upRecorder struct {
+	compose
+
+	recorder *hookRecorder
+}
+
+func (r upRecorder) Up(ctx context.Context, ws *common.Workspace, p *common.Project) error {
+	r.recorder.calls = append(r.recorder.calls, "up "+p.Name)
+
+	return nil
+}
+
+func hook(command string, onFailure common.HookFailurePolicy) common.Hook {
+	return common.Hook{
+		Command:   command,
+		Timeout:   50 * time.Millisecond,
+		OnFailure: onFailure,
+	}
+}
+
+func Test_startProjects_Hooks(t *testing.T) {
+	ws := &common.Workspace{
+		Name: "test",
+		Projects: []common.Project{
+			{Name: "db"},
+			{
+				Name: "api",
+				Config: common.ProjectConfig{
+					Hooks: common.Hooks{
+						PreUp:  []common.Hook{hook("make env", common.HookAbort)},
+						PostUp: []common.Hook{hook("make migrate", common.HookWarn)},
+					},
+				},
+			},
+		},
+		Hooks: common.Hooks{
+			PreUp:  []common.Hook{hook("make certs", common.HookAbort)},
+			PostUp: []common.Hook{hook("make seed", common.HookAbort)},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		fail      string
+		block     string
+		expect    []string
+		expectErr error
+	}{
+		{
+			name: "run around each project, and the workspace",
+			expect: []string{
+				"make certs",
+				"up db",
+				"make env",
+				"up api",
+				"make migrate",
+				"make seed",
+			},
+		},
+		{
+			name: "abort stops everything after",
+			fail: "make env",
+			expect: []string{
+				"make certs",
+				"up db",
+				"make env",
+			},
+			expectErr: common.ErrHookFailed{
+				Event: common.HookPreUp,
+				Hook:  "make env",
+				Err:   errors.New("exit status 1"),
+			},
+		},
+		{
+			name: "warn carries on",
+			fail: "make migrate",
+			expect: []string{
+				"make certs",
+				"up db",
+				"make env",
+				"up api",
+				"make migrate",
+				"make seed",
+			},
+		},
+		{
+			name:  "timing out is a failure",
+			block: "make certs",
+			expect: []string{
+				"make certs",
+			},
+			expectErr: common.ErrHookFailed{
+				Event: common.HookPreUp,
+				Hook:  "make certs",
+				Err:   common.ErrHookTimedOut{Timeout: 50 * time.Millisecond},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			recorder := &hookRecorder{
+				fail:  test.fail,
+				block: test.block,
+			}
+
+			c := &Controller{
+				compose: upRecorder{recorder: recorder},
+				exec:    recorder,
+				tui:     &recordingTui{},
+			}
+
+			err := c.startProjects(context.Background(), ws, []string{"db", "api"})
+
+			assert.Equal(tt, test.expectErr, err)
+			assert.Equal(tt, test.expect, recorder.calls)
+		})
+	}
+}
+
+func Test_startProjects_HookInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws := &common.Workspace{
+		Name: "test",
+		Projects: []common.Project{
+			{
+				Name: "api",
+				Config: common.ProjectConfig{
+					Hooks: common.Hooks{
+						// Even though it's only meant to warn
+						PreUp: []common.Hook{hook("make env", common.HookWarn)},
+					},
+				},
+			},
+		},
+	}
+
+	recorder := &hookRecorder{
+		fail: "make env",
+	}
+
+	c := &Controller{
+		exec: recorder,
+		tui:  &recordingTui{},
+	}
+
+	cancel()
+	err := c.runProjectHooks(ctx, ws, &ws.Projects[0], common.HookPreUp)
+
+	assert.Equal(t, context.Canceled, err)
+}
+
+func Test_extensionDir(t *testing.T) {
+	ws := &common.Workspace{
+		ConfigPath: "/src/ws/orca.workspace.yaml",
+	}
+
+	api := &common.Project{
+		ProjectDir: "/src/api",
+	}
+
+	tests := []struct {
+		name    string
+		project *common.Project
+		chdir   string
+		expect  string
+	}{
+		{name: "project", project: api, expect: "/src/api"},
+		{name: "relative to project", project: api, chdir: "scripts", expect: "/src/api/scripts"},
+		{name: "absolute", project: api, chdir: "/tmp", expect: "/tmp"},
+		{name: "workspace", expect: "/src/ws"},
+		{name: "relative to workspace", chdir: "../shared", expect: "/src/shared"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			rc := runtimeContext{
+				Workspace: ws,
+				Project:   test.project,
+			}
+
+			assert.Equal(tt, test.expect, extensionDir(rc, common.Extension{Chdir: test.chdir}))
+		})
+	}
+}
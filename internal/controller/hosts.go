@@ -12,7 +12,7 @@ func (c *Controller) Hosts(dto HostsDTO) error {
 	ws, err := c.workspaceRepo.Load(dto.Workspace)
 
 	if err != nil {
-		return err
+		return c.recordIfConfigError(err)
 	}
 
 	for _, h := range ws.GetUniqueHosts() {
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func joinOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// reportInterrupted summarises how far through the projects an operation got
+// before it was interrupted. The project at current is the one that was being
+// worked on, if inProgress, otherwise it hadn't been started.
+func (c *Controller) reportInterrupted(ctx context.Context, done string, ordered []string, current int, inProgress bool) error {
+	c.tui.Error("Interrupted!")
+	c.tui.Info(fmt.Sprintf("%s: %s", done, joinOrNone(ordered[:current])))
+
+	notStarted := ordered[current:]
+
+	if inProgress {
+		c.tui.Info(fmt.Sprintf("interrupted: %s", ordered[current]))
+		notStarted = ordered[current+1:]
+	}
+
+	c.tui.Info(fmt.Sprintf("not started: %s", joinOrNone(notStarted)))
+
+	return ctx.Err()
+}
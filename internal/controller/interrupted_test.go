@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingTui struct {
+	lines []string
+}
+
+func (t *recordingTui) Info(msg ...string)    { t.lines = append(t.lines, msg...) }
+func (t *recordingTui) Error(msg ...string)   { t.lines = append(t.lines, msg...) }
+func (t *recordingTui) Success(msg ...string) { t.lines = append(t.lines, msg...) }
+func (t *recordingTui) NewLine()              {}
+
+func (t *recordingTui) Table(headers []string, rows [][]string) {}
+
+func (t *recordingTui) PresentChoices(opts []string, title string) (string, error) {
+	return confirmYes, nil
+}
+
+func (t *recordingTui) RecordIfError(msg string, err error) error {
+	if err != nil {
+		t.lines = append(t.lines, msg)
+	}
+
+	return err
+}
+
+// interruptingCompose cancels the context while starting the named project,
+// the same as a Ctrl-C part way through 'compose up'.
+type interruptingCompose struct {
+	compose
+
+	cancel    context.CancelFunc
+	interrupt string
+	started   []string
+}
+
+func (c *interruptingCompose) Up(ctx context.Context, ws *common.Workspace, p *common.Project) error {
+	if p.Name == c.interrupt {
+		c.cancel()
+
+		return ctx.Err()
+	}
+
+	c.started = append(c.started, p.Name)
+
+	return nil
+}
+
+func Test_startProjects_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws := &common.Workspace{
+		Projects: []common.Project{
+			{Name: "db"},
+			{Name: "api"},
+			{Name: "web"},
+		},
+	}
+
+	tui := &recordingTui{}
+	compose := &interruptingCompose{
+		cancel:    cancel,
+		interrupt: "api",
+	}
+
+	c := &Controller{
+		compose: compose,
+		tui:     tui,
+	}
+
+	err := c.startProjects(ctx, ws, []string{"db", "api", "web"})
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []string{"db"}, compose.started)
+	assert.Equal(t, []string{
+		"Interrupted!",
+		"started: db",
+		"interrupted: api",
+		"not started: web",
+	}, tui.lines)
+}
@@ -1,17 +1,224 @@
 package controller
 
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/pkg/logmux"
+)
+
 type LogsDTO struct {
 	Workspace string
 	Project   string
-	Service   string
+	Profile   string
+
+	// Service only shows the logs of services with this name, in any of the
+	// projects.
+	Service string
+
+	// Grep only shows lines matching the regular expression.
+	Grep string
+
+	Since    string
+	NoFollow bool
+
+	// Run replays the logs recorded during an earlier run, rather than
+	// showing those of the running containers.
+	Run string
+}
+
+type RecordLogsDTO struct {
+	Workspace string
+	Project   string
+	Profile   string
+
+	// Run is the id the logs are recorded under.
+	Run string
+}
+
+// buildLogFilter combines the service and grep filters, either can be empty.
+func buildLogFilter(service string, grep string) (logmux.Filter, error) {
+	if service == "" && grep == "" {
+		return nil, nil
+	}
+
+	var pattern *regexp.Regexp
+
+	if grep != "" {
+		var err error
+		pattern, err = regexp.Compile(grep)
+
+		if err != nil {
+			return nil, common.ErrInvalidInput{
+				To:  "logs.grep",
+				Msg: fmt.Sprintf("'%s' is not a valid regular expression: %s", grep, err.Error()),
+			}
+		}
+	}
+
+	return func(l logmux.Line) bool {
+		if service != "" {
+			if _, s, _ := strings.Cut(l.Prefix, "/"); s != service {
+				return false
+			}
+		}
+
+		return pattern == nil || pattern.MatchString(l.Text)
+	}, nil
+}
+
+// logProjects works out which projects to show the logs of. Across the whole
+// workspace, only the projects that have been cloned are included.
+func (c *Controller) logProjects(dto LogsDTO) (*common.Workspace, []*common.Project, error) {
+	if dto.Profile != "" {
+		rc, err := c.resolveProfileContext(dto.Workspace, dto.Project)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ordered, err := determineProfileStartupOrder(rc.Workspace, dto.Profile)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		projects := []*common.Project{}
+
+		for _, name := range ordered {
+			p, err := rc.Workspace.GetProject(name)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			projects = append(projects, p)
+		}
+
+		return rc.Workspace, projects, nil
+	}
+
+	rc, err := c.resolveContext(dto.Workspace, dto.Project)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rc.Project != nil {
+		return rc.Workspace, []*common.Project{rc.Project}, nil
+	}
+
+	projects := []*common.Project{}
+
+	for i := range rc.Workspace.Projects {
+		if rc.Workspace.Projects[i].IsRegistered {
+			projects = append(projects, &rc.Workspace.Projects[i])
+		}
+	}
+
+	return rc.Workspace, projects, nil
+}
+
+func (c *Controller) logSources(ctx context.Context, ws *common.Workspace, projects []*common.Project, opts common.LogsOptions) ([]logmux.Source, error) {
+	if len(projects) == 0 {
+		return nil, c.tui.RecordIfError("No logs to show!", common.ErrInvalidExecutionContext{
+			Msg: fmt.Sprintf("none of the projects in '%s' have been cloned", ws.Name),
+		})
+	}
+
+	sources := make([]logmux.Source, len(projects))
+
+	for i, p := range projects {
+		var err error
+		sources[i], err = c.compose.LogSource(ctx, ws, p, opts)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sources, nil
+}
+
+// replayLogs shows the recorded logs of the projects, in the order they were
+// recorded.
+func (c *Controller) replayLogs(ws *common.Workspace, projects []*common.Project, run string, filter logmux.Filter) error {
+	lines, err := c.logStore.Read(ws.Name, run)
+
+	if _, ok := err.(common.ErrUnknownLogRun); ok {
+		return c.tui.RecordIfError(fmt.Sprintf("No logs have been recorded for run '%s'!", run), err)
+	}
+
+	if err != nil {
+		return c.tui.RecordIfError("Failed to read the recorded logs!", err)
+	}
+
+	names := make([]string, len(projects))
+
+	for i, p := range projects {
+		names[i] = p.Name
+	}
+
+	return c.tui.RecordIfError("Failed to show the recorded logs!", logmux.Replay(lines, func(l logmux.Line) bool {
+		project, _, _ := strings.Cut(l.Prefix, "/")
+
+		return slices.Contains(names, project) && (filter == nil || filter(l))
+	}, c.logs))
+}
+
+func (c *Controller) Logs(ctx context.Context, dto LogsDTO) error {
+	filter, err := buildLogFilter(dto.Service, dto.Grep)
+
+	if err != nil {
+		return c.tui.RecordIfError("The grep pattern is not a valid regular expression!", err)
+	}
+
+	ws, projects, err := c.logProjects(dto)
+
+	if err != nil {
+		return err
+	}
+
+	if dto.Run != "" {
+		return c.replayLogs(ws, projects, dto.Run, filter)
+	}
+
+	sources, err := c.logSources(ctx, ws, projects, common.LogsOptions{
+		Since:  dto.Since,
+		Follow: !dto.NoFollow,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.tui.RecordIfError("Failed to read logs!", logmux.Stream(sources, filter, c.logs))
 }
 
-func (c *Controller) Logs(dto LogsDTO) error {
-	ctx, err := c.resolveContext(dto.Workspace, dto.Project)
+// RecordLogs follows the logs of the projects, storing them until the
+// containers stop.
+func (c *Controller) RecordLogs(ctx context.Context, dto RecordLogsDTO) error {
+	ws, projects, err := c.logProjects(LogsDTO{
+		Workspace: dto.Workspace,
+		Project:   dto.Project,
+		Profile:   dto.Profile,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	sources, err := c.logSources(ctx, ws, projects, common.LogsOptions{
+		Follow: true,
+	})
 
 	if err != nil {
 		return err
 	}
 
-	return c.compose.Logs(ctx.Workspace, ctx.Project, dto.Service)
+	return c.tui.RecordIfError("Failed to record logs!", c.logStore.Record(ws.Name, dto.Run, sources))
 }
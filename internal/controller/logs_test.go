@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/panoptescloud/orca/pkg/logmux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_buildLogFilter(t *testing.T) {
+	lines := []logmux.Line{
+		{Prefix: "api/php", Text: "GET /users 200"},
+		{Prefix: "api/nginx", Text: "GET /users 200"},
+		{Prefix: "admin/php", Text: "POST /login 500"},
+		{Prefix: "api", Text: "no such service"},
+	}
+
+	tests := []struct {
+		name    string
+		service string
+		grep    string
+		expect  []bool
+	}{
+		{
+			name:    "service across projects",
+			service: "php",
+			expect:  []bool{true, false, true, false},
+		},
+		{
+			name:   "grep",
+			grep:   `\s5\d\d$`,
+			expect: []bool{false, false, true, false},
+		},
+		{
+			name:    "both",
+			service: "php",
+			grep:    "GET",
+			expect:  []bool{true, false, false, false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			filter, err := buildLogFilter(test.service, test.grep)
+			require.Nil(tt, err)
+
+			for i, l := range lines {
+				assert.Equal(tt, test.expect[i], filter(l), l)
+			}
+		})
+	}
+}
+
+func Test_buildLogFilter_Invalid(t *testing.T) {
+	filter, err := buildLogFilter("", "GET (")
+	assert.Nil(t, filter)
+	assert.ErrorContains(t, err, "'GET (' is not a valid regular expression")
+
+	filter, err = buildLogFilter("", "")
+	assert.Nil(t, filter)
+	assert.Nil(t, err)
+}
@@ -1,14 +1,18 @@
 package controller
 
 import (
-	"os"
-	"strings"
+	"context"
+	"io"
 
 	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/panoptescloud/orca/internal/snapshots"
+	"github.com/panoptescloud/orca/pkg/logmux"
 )
 
 type config interface {
 	GetAllProjectMeta() []common.ProjectMeta
+	GetAllWorkspaceMeta() []common.WorkspaceMeta
 	GetCurrentWorkspace() string
 	GetWorkspaceMeta(name string) (common.WorkspaceMeta, error)
 }
@@ -19,21 +23,57 @@ type tui interface {
 	Success(msg ...string)
 	NewLine()
 	RecordIfError(msg string, err error) error
+	Table(headers []string, rows [][]string)
+	PresentChoices(opts []string, title string) (string, error)
 }
 
 type workspaceRepository interface {
 	Load(name string) (*common.Workspace, error)
+	DescribeConfig(wsName string, projectName string) ([]byte, error)
 }
 
 type compose interface {
-	Up(*common.Workspace, *common.Project) error
-	Down(ws *common.Workspace, p *common.Project) error
-	ShowConfig(ws *common.Workspace, p *common.Project) error
+	Up(ctx context.Context, ws *common.Workspace, p *common.Project) error
+	Down(ctx context.Context, ws *common.Workspace, p *common.Project) error
+	ShowConfig(ctx context.Context, ws *common.Workspace, p *common.Project) error
 	ShowCommand(ws *common.Workspace, p *common.Project) error
-	Exec(ws *common.Workspace, p *common.Project, service string, cmdArgs []string) error
-	Logs(ws *common.Workspace, p *common.Project, service string) error
-	IsSvcRunning(ws *common.Workspace, p *common.Project, service string) (bool, error)
-	Run(ws *common.Workspace, p *common.Project, service string, cmdArgs []string) error
+	Exec(ctx context.Context, ws *common.Workspace, p *common.Project, service string, cmdArgs []string, opts common.ExecOptions) error
+	LogSource(ctx context.Context, ws *common.Workspace, p *common.Project, opts common.LogsOptions) (logmux.Source, error)
+	IsSvcRunning(ctx context.Context, ws *common.Workspace, p *common.Project, service string) (bool, error)
+	IsRunning(ctx context.Context, ws *common.Workspace, p *common.Project) (bool, error)
+	Run(ctx context.Context, ws *common.Workspace, p *common.Project, service string, cmdArgs []string, opts common.ExecOptions) error
+}
+
+// executor runs the extensions and hooks which aren't run in a service.
+type executor interface {
+	Exec(ctx context.Context, cmdName string, args []string, opts ...hostsys.ExecOpt) error
+}
+
+type logStore interface {
+	Record(wsName string, run string, sources []logmux.Source) error
+	Read(wsName string, run string) ([]logmux.Line, error)
+}
+
+// volumes reads and replaces the contents of a project's named volumes.
+type volumes interface {
+	Declared(ws *common.Workspace, p *common.Project) ([]common.Volume, error)
+	Exists(ctx context.Context, ws *common.Workspace, vol common.Volume) (bool, error)
+	Export(ctx context.Context, ws *common.Workspace, vol common.Volume, w io.Writer) error
+	Import(ctx context.Context, ws *common.Workspace, p *common.Project, vol common.Volume, r io.Reader) error
+	Remove(ctx context.Context, ws *common.Workspace, vol common.Volume) error
+}
+
+type snapshotStore interface {
+	NewName() string
+	TempName(name string) string
+	Exists(wsName string, name string) (bool, error)
+	Create(wsName string, name string, project string, key string) (io.WriteCloser, error)
+	Save(wsName string, name string, projects map[string][]string) error
+	Load(wsName string, name string) (snapshots.Snapshot, error)
+	Open(wsName string, name string, project string, key string) (io.ReadCloser, error)
+	List(wsName string) ([]snapshots.Snapshot, error)
+	Replace(wsName string, from string, to string) error
+	Remove(wsName string, name string) error
 }
 
 type Controller struct {
@@ -41,6 +81,11 @@ type Controller struct {
 	workspaceRepo workspaceRepository
 	compose       compose
 	tui           tui
+	logs          logmux.Sink
+	logStore      logStore
+	exec          executor
+	volumes       volumes
+	snapshots     snapshotStore
 }
 
 type runtimeContext struct {
@@ -48,22 +93,19 @@ type runtimeContext struct {
 	Project   *common.Project
 }
 
-func (c *Controller) getProjectFromWorkdir() (*common.ProjectMeta, error) {
-	dir, err := os.Getwd()
-
-	if err != nil {
-		return nil, err
-	}
-
-	projects := c.cfg.GetAllProjectMeta()
-
-	for _, p := range projects {
-		if strings.HasPrefix(dir, p.Path) {
-			return &p, nil
+// recordIfConfigError surfaces problems with the workspace or project config
+// to the user, as they're almost always something the user needs to fix.
+func (c *Controller) recordIfConfigError(err error) error {
+	switch e := err.(type) {
+	case common.ErrInvalidConfig:
+		for _, line := range e.Lines() {
+			c.tui.Error(line)
 		}
+	case common.ErrConfigIsNotValidYAML, common.ErrWorkspaceConfigNotFound, common.ErrProjectConfigNotFound:
+		c.tui.Error(e.Error())
 	}
 
-	return nil, nil
+	return err
 }
 
 func (c *Controller) buildRuntimeContext(wsName string, projectName string) (runtimeContext, error) {
@@ -76,7 +118,7 @@ func (c *Controller) buildRuntimeContext(wsName string, projectName string) (run
 	ws, err := c.workspaceRepo.Load(meta.Name)
 
 	if err != nil {
-		return runtimeContext{}, err
+		return runtimeContext{}, c.recordIfConfigError(err)
 	}
 
 	if projectName == "" {
@@ -97,46 +139,36 @@ func (c *Controller) buildRuntimeContext(wsName string, projectName string) (run
 	}, nil
 }
 
-func (c *Controller) resolveContext(ws string, project string) (runtimeContext, error) {
-	// We've got a specific workspace and project
-	if ws != "" && project != "" {
-		return c.buildRuntimeContext(ws, project)
-	}
-
-	// No workspace, but a project was specified, so we'll assume that it's the
-	// current workspace
-	if ws == "" && project != "" {
-		return c.buildRuntimeContext(c.cfg.GetCurrentWorkspace(), project)
-	}
-
-	// The workspace was specified, but not project was so we'll use that workspace
-	// and assume all projects.
-	if ws != "" && project == "" {
-		return c.buildRuntimeContext(ws, "")
+// resolveProfileContext resolves the workspace a profile applies to. Profiles
+// always cover the whole workspace, so a project can't be given as well.
+func (c *Controller) resolveProfileContext(ws string, project string) (runtimeContext, error) {
+	if project != "" {
+		return runtimeContext{}, common.ErrInvalidExecutionContext{
+			Msg: "a profile cannot be combined with a specific project",
+		}
 	}
 
-	// If we get here, both of the options were empty, so we'll try resolve from
-	// the working directory
-	p, err := c.getProjectFromWorkdir()
+	rc, err := c.resolveContext(ws, "")
 
 	if err != nil {
 		return runtimeContext{}, err
 	}
 
-	if p != nil {
-		return c.buildRuntimeContext(p.WorkspaceName, p.Name)
-	}
+	rc.Project = nil
 
-	// We weren't in a project directory, so our final resort is to use the
-	// current workspace, and assume all projects should be started.
-	return c.buildRuntimeContext(c.cfg.GetCurrentWorkspace(), "")
+	return rc, nil
 }
 
-func NewController(cfg config, wsRepo workspaceRepository, compose compose, tui tui) *Controller {
+func NewController(cfg config, wsRepo workspaceRepository, compose compose, tui tui, logs logmux.Sink, logStore logStore, exec executor, volumes volumes, snapshots snapshotStore) *Controller {
 	return &Controller{
 		cfg:           cfg,
 		workspaceRepo: wsRepo,
 		compose:       compose,
 		tui:           tui,
+		logs:          logs,
+		logStore:      logStore,
+		exec:          exec,
+		volumes:       volumes,
+		snapshots:     snapshots,
 	}
 }
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"os"
+	"strings"
+	"text/template"
+)
+
+const DefaultPromptFormat = "{{.Workspace}}{{if .Project}}/{{.Project}}{{end}}"
+
+type PromptDTO struct {
+	// Format is a go template, given the Workspace and Project.
+	Format string
+}
+
+type promptContext struct {
+	Workspace string
+	Project   string
+}
+
+// Prompt prints the workspace, and project, that commands would use. They're
+// resolved in the same order as for other commands, from ORCA_WORKSPACE and
+// ORCA_PROJECT, then the working directory, and finally the current workspace.
+// Only the orca config is used, no workspace or compose files are loaded, so
+// it's quick enough to run as part of a shell prompt. Nothing is printed if
+// there is no workspace.
+func (c *Controller) Prompt(dto PromptDTO) error {
+	format := dto.Format
+
+	if format == "" {
+		format = DefaultPromptFormat
+	}
+
+	tpl, err := template.New("prompt").Parse(format)
+
+	if err != nil {
+		return c.tui.RecordIfError("Invalid prompt format!", err)
+	}
+
+	values := ResolveEnvOptions([]EnvOption{
+		{Name: "workspace", Env: WorkspaceEnv},
+		{Name: "project", Env: ProjectEnv},
+	}, os.Getenv)
+
+	resolution, err := c.resolveContextNames(contextNames{
+		Workspace:        values["workspace"],
+		Project:          values["project"],
+		WorkspaceFromEnv: values["workspace"] != "",
+		ProjectFromEnv:   values["project"] != "",
+	})
+
+	if err != nil {
+		return err
+	}
+
+	rc := promptContext{
+		Workspace: resolution.Workspace,
+		Project:   resolution.Project,
+	}
+
+	if rc.Workspace == "" {
+		return nil
+	}
+
+	out := &strings.Builder{}
+
+	if err := tpl.Execute(out, rc); err != nil {
+		return c.tui.RecordIfError("Invalid prompt format!", err)
+	}
+
+	c.tui.Info(out.String())
+
+	return nil
+}
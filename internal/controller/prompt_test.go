@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Prompt(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		dir     string
+		env     map[string]string
+		current string
+		expect  []string
+	}{
+		{
+			name:    "working directory",
+			dir:     filepath.Join(root, "api"),
+			current: "current",
+			expect:  []string{"main/api"},
+		},
+		{
+			name: "ORCA_WORKSPACE over the working directory",
+			dir:  filepath.Join(root, "api"),
+			env: map[string]string{
+				WorkspaceEnv: "direnv",
+			},
+			current: "current",
+			expect:  []string{"direnv"},
+		},
+		{
+			name: "ORCA_WORKSPACE and ORCA_PROJECT",
+			dir:  root,
+			env: map[string]string{
+				WorkspaceEnv: "direnv",
+				ProjectEnv:   "web",
+			},
+			current: "current",
+			expect:  []string{"direnv/web"},
+		},
+		{
+			name: "ORCA_PROJECT uses the current workspace",
+			dir:  filepath.Join(root, "api"),
+			env: map[string]string{
+				ProjectEnv: "web",
+			},
+			current: "current",
+			expect:  []string{"current/web"},
+		},
+		{
+			name:    "current workspace",
+			dir:     root,
+			current: "current",
+			expect:  []string{"current"},
+		},
+		{
+			name:   "no workspace",
+			dir:    root,
+			expect: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			require.Nil(tt, os.MkdirAll(test.dir, 0755))
+			tt.Chdir(test.dir)
+
+			tt.Setenv(WorkspaceEnv, test.env[WorkspaceEnv])
+			tt.Setenv(ProjectEnv, test.env[ProjectEnv])
+
+			tui := &recordingTui{}
+			c := &Controller{
+				tui: tui,
+				cfg: contextConfig{
+					projects: []common.ProjectMeta{
+						{Name: "api", Path: filepath.Join(root, "api"), WorkspaceName: "main"},
+					},
+					current: test.current,
+				},
+			}
+
+			require.Nil(tt, c.Prompt(PromptDTO{}))
+			assert.Equal(tt, test.expect, tui.lines)
+		})
+	}
+}
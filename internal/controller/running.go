@@ -0,0 +1,33 @@
+package controller
+
+import "context"
+
+// RunningProjects returns the names of the projects in the workspace which
+// currently have running containers.
+func (c *Controller) RunningProjects(ctx context.Context, wsName string) ([]string, error) {
+	rc, err := c.buildRuntimeContext(wsName, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	running := []string{}
+
+	for _, p := range rc.Workspace.Projects {
+		if !p.IsRegistered {
+			continue
+		}
+
+		isRunning, err := c.compose.IsRunning(ctx, rc.Workspace, &p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isRunning {
+			running = append(running, p.Name)
+		}
+	}
+
+	return running, nil
+}
@@ -8,17 +8,17 @@ type ShowComposeCommandDTO struct {
 }
 
 func (c *Controller) ShowComposeCommand(dto ShowComposeCommandDTO) error {
-	ctx, err := c.resolveContext(dto.Workspace, dto.Project)
+	rc, err := c.resolveContext(dto.Workspace, dto.Project)
 
 	if err != nil {
 		return err
 	}
 
-	if ctx.Project == nil {
+	if rc.Project == nil {
 		return common.ErrInvalidExecutionContext{
 			Msg: "this command must be run in a singular project context",
 		}
 	}
 
-	return c.compose.ShowCommand(ctx.Workspace, ctx.Project)
+	return c.compose.ShowCommand(rc.Workspace, rc.Project)
 }
@@ -1,5 +1,9 @@
 package controller
 
+import (
+	"context"
+)
+
 import "github.com/panoptescloud/orca/internal/common"
 
 type ShowComposeConfigDTO struct {
@@ -7,18 +11,18 @@ type ShowComposeConfigDTO struct {
 	Project   string
 }
 
-func (c *Controller) ShowComposeConfig(dto ShowComposeConfigDTO) error {
-	ctx, err := c.resolveContext(dto.Workspace, dto.Project)
+func (c *Controller) ShowComposeConfig(ctx context.Context, dto ShowComposeConfigDTO) error {
+	rc, err := c.resolveContext(dto.Workspace, dto.Project)
 
 	if err != nil {
 		return err
 	}
 
-	if ctx.Project == nil {
+	if rc.Project == nil {
 		return common.ErrInvalidExecutionContext{
 			Msg: "this command must be run in a singular project context",
 		}
 	}
 
-	return c.compose.ShowConfig(ctx.Workspace, ctx.Project)
+	return c.compose.ShowConfig(ctx, rc.Workspace, rc.Project)
 }
@@ -0,0 +1,33 @@
+package controller
+
+type ShowConfigDTO struct {
+	Workspace string
+	Project   string
+}
+
+// ShowConfig shows the effective workspace config, or project config when in a
+// project context, with the file each value came from. Only the names are
+// resolved, without loading the workspace, so that the config can still be
+// shown when it's invalid. Any issues are listed after it.
+func (c *Controller) ShowConfig(dto ShowConfigDTO) error {
+	resolution, err := c.resolveContextNames(contextNames{
+		Workspace: dto.Workspace,
+		Project:   dto.Project,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	contents, err := c.workspaceRepo.DescribeConfig(resolution.Workspace, resolution.Project)
+
+	if contents != nil {
+		c.tui.Info(string(contents))
+	}
+
+	if err != nil {
+		return c.recordIfConfigError(err)
+	}
+
+	return nil
+}
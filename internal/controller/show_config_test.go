@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	orcaconfig "github.com/panoptescloud/orca/internal/config"
+	"github.com/panoptescloud/orca/internal/repository"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShowConfig_InvalidConfig(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.Nil(t, afero.WriteFile(fs, "/ws/orca.workspace.yaml", []byte(`name: test
+include:
+  - base.yaml
+projects:
+  - name: api
+`), 0644))
+	require.Nil(t, afero.WriteFile(fs, "/ws/base.yaml", []byte(`overlays:
+  network:
+    enabled: true
+    createIn: api
+`), 0644))
+	// The primary compose file is required, so the workspace can't be loaded
+	require.Nil(t, afero.WriteFile(fs, "/projects/api/orca.project.yaml", []byte(`include:
+  - base.yaml
+envFiles:
+  - path: .env
+`), 0644))
+	require.Nil(t, afero.WriteFile(fs, "/projects/api/base.yaml", []byte(`composeFiles:
+  extras:
+    - path: docker-compose.dev.yaml
+`), 0644))
+
+	cfg := orcaconfig.NewDefaultConfig(fs, "/home/orca.yaml")
+	require.Nil(t, cfg.LoadOrCreate())
+	require.Nil(t, cfg.AddWorkspace("/ws/orca.workspace.yaml", "test"))
+	require.Nil(t, cfg.SetProjectPath("test", "api", "/projects/api"))
+
+	tests := []struct {
+		name      string
+		project   string
+		expect    []string
+		expectErr error
+	}{
+		{
+			name: "workspace",
+			expect: []string{`overlays:
+    network:
+        enabled: true # /ws/base.yaml:3
+        createIn: api # /ws/base.yaml:4
+name: test # /ws/orca.workspace.yaml:1
+projects:
+    - name: api # /ws/orca.workspace.yaml:5
+`},
+		},
+		{
+			name:    "project with a validation issue",
+			project: "api",
+			expect: []string{`composeFiles:
+    extras:
+        - path: docker-compose.dev.yaml # /projects/api/base.yaml:3
+envFiles:
+    - path: .env # /projects/api/orca.project.yaml:4
+`,
+				"/projects/api/orca.project.yaml:2:3: 'composeFiles.primary' is required",
+			},
+			expectErr: common.ErrInvalidConfig{
+				Path: "/projects/api/orca.project.yaml",
+				Issues: []common.ConfigIssue{
+					{Line: 2, Column: 3, Message: "'composeFiles.primary' is required"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			tui := &recordingTui{}
+			c := &Controller{
+				tui:           tui,
+				cfg:           cfg,
+				workspaceRepo: repository.NewWorkspaceRepository(fs, cfg),
+			}
+
+			err := c.ShowConfig(ShowConfigDTO{
+				Workspace: "test",
+				Project:   test.project,
+			})
+
+			assert.Equal(tt, test.expectErr, err)
+			assert.Equal(tt, test.expect, tui.lines)
+		})
+	}
+}
@@ -1,22 +1,31 @@
 package controller
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/panoptescloud/orca/internal/common"
 	"github.com/panoptescloud/orca/pkg/dag"
 )
 
 type UpDTO struct {
 	Workspace string
 	Project   string
+	Profile   string
+
+	// WithDeps starts everything the project requires as well, any that are
+	// already running are skipped.
+	WithDeps bool
 }
 
-func determineStartupOrder(ctx runtimeContext) ([]string, error) {
-	if ctx.Project != nil {
+func determineStartupOrder(rc runtimeContext) ([]string, error) {
+	if rc.Project != nil {
 		return []string{
-			ctx.Project.Name,
+			rc.Project.Name,
 		}, nil
 	}
 
-	g, err := dag.NewGraph(ctx.Workspace.Projects)
+	g, err := dag.NewGraph(rc.Workspace.Projects)
 
 	if err != nil {
 		return nil, err
@@ -25,53 +34,212 @@ func determineStartupOrder(ctx runtimeContext) ([]string, error) {
 	return g.TopologicalKeysFromRoots()
 }
 
-// TODO: Move to down.go when it's added
-// func determineShutdownOrder(ctx runtimeContext) ([]string, error) {
-// 	if ctx.Project != nil {
-// 		return []string{
-// 			ctx.Project.Name,
-// 		}, nil
-// 	}
+// profileClosure returns every project that's needed to run the profile, that
+// is the projects within it and everything they require.
+func profileClosure(ws *common.Workspace, g *dag.Graph, profile string) ([]string, error) {
+	projects, err := ws.GetProfile(profile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := g.Ancestors(projects...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ancestors, projects...), nil
+}
+
+func determineProfileStartupOrder(ws *common.Workspace, profile string) ([]string, error) {
+	g, err := dag.NewGraph(ws.Projects)
 
-// 	g, err := dag.NewGraph(ctx.WorkspaceConfig.Projects)
+	if err != nil {
+		return nil, err
+	}
+
+	closure, err := profileClosure(ws, g, profile)
+
+	if err != nil {
+		return nil, err
+	}
 
-// 	if err != nil {
-// 		return nil, err
-// 	}
+	sub, err := g.Subgraph(closure)
 
-// 	return g.TopologicalKeysFromLeaves()
-// }
+	if err != nil {
+		return nil, err
+	}
 
-func (c *Controller) startServices(ctx runtimeContext) error {
-	ordered, err := determineStartupOrder(ctx)
+	return sub.TopologicalKeysFromRoots()
+}
+
+func determineStartupOrderWithDeps(ws *common.Workspace, project string) ([]string, error) {
+	g, err := dag.NewGraph(ws.Projects)
 
 	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := g.Ancestors(project)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := g.Subgraph(append(ancestors, project))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sub.TopologicalKeysFromRoots()
+}
+
+// skipRunningRequirements removes any projects that are already running from
+// the list, other than the target project itself which is always started so
+// that any changes to it are applied.
+func (c *Controller) skipRunningRequirements(ctx context.Context, ws *common.Workspace, ordered []string, target string) ([]string, error) {
+	toStart := []string{}
+
+	for _, name := range ordered {
+		if name == target {
+			toStart = append(toStart, name)
+			continue
+		}
+
+		p, err := ws.GetProject(name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		running, err := c.compose.IsRunning(ctx, ws, p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if running {
+			c.tui.Info(fmt.Sprintf("%s:%s is already running, skipping", name, ws.Name))
+			continue
+		}
+
+		toStart = append(toStart, name)
+	}
+
+	return toStart, nil
+}
+
+// startProject starts a single project, along with its own hooks.
+func (c *Controller) startProject(ctx context.Context, ws *common.Workspace, p *common.Project) error {
+	if err := c.runProjectHooks(ctx, ws, p, common.HookPreUp); err != nil {
+		return err
+	}
+
+	if err := c.compose.Up(ctx, ws, p); err != nil {
+		return err
+	}
+
+	return c.runProjectHooks(ctx, ws, p, common.HookPostUp)
+}
+
+func (c *Controller) startProjects(ctx context.Context, ws *common.Workspace, ordered []string) error {
+	if err := c.runWorkspaceHooks(ctx, ws, common.HookPreUp); err != nil {
+		if ctx.Err() != nil {
+			return c.reportInterrupted(ctx, "started", ordered, 0, false)
+		}
+
 		return err
 	}
 
-	for _, p := range ordered {
+	for i, p := range ordered {
+		if ctx.Err() != nil {
+			return c.reportInterrupted(ctx, "started", ordered, i, false)
+		}
+
 		// This shouldn't ever really return an error at this point, if it does
 		// something went wrong while building the runtime context
-		projectConfig, err := ctx.Workspace.GetProject(p)
+		projectConfig, err := ws.GetProject(p)
 
 		if err != nil {
 			return err
 		}
 
-		if err := c.compose.Up(ctx.Workspace, projectConfig); err != nil {
+		if err := c.startProject(ctx, ws, projectConfig); err != nil {
+			if ctx.Err() != nil {
+				return c.reportInterrupted(ctx, "started", ordered, i, true)
+			}
+
 			return err
 		}
 	}
 
+	if err := c.runWorkspaceHooks(ctx, ws, common.HookPostUp); err != nil {
+		if ctx.Err() != nil {
+			return c.reportInterrupted(ctx, "started", ordered, len(ordered), false)
+		}
+
+		return err
+	}
+
 	return nil
 }
 
-func (c *Controller) Up(dto UpDTO) error {
-	ctx, err := c.resolveContext(dto.Workspace, dto.Project)
+func (c *Controller) startServices(ctx context.Context, rc runtimeContext) error {
+	ordered, err := determineStartupOrder(rc)
 
 	if err != nil {
 		return err
 	}
 
-	return c.startServices(ctx)
+	return c.startProjects(ctx, rc.Workspace, ordered)
+}
+
+func (c *Controller) Up(ctx context.Context, dto UpDTO) error {
+	if dto.Profile != "" {
+		rc, err := c.resolveProfileContext(dto.Workspace, dto.Project)
+
+		if err != nil {
+			return err
+		}
+
+		ordered, err := determineProfileStartupOrder(rc.Workspace, dto.Profile)
+
+		if err != nil {
+			return err
+		}
+
+		return c.startProjects(ctx, rc.Workspace, ordered)
+	}
+
+	rc, err := c.resolveContext(dto.Workspace, dto.Project)
+
+	if err != nil {
+		return err
+	}
+
+	if dto.WithDeps {
+		if rc.Project == nil {
+			return common.ErrInvalidExecutionContext{
+				Msg: "starting with dependencies must be run in a singular project context",
+			}
+		}
+
+		ordered, err := determineStartupOrderWithDeps(rc.Workspace, rc.Project.Name)
+
+		if err != nil {
+			return err
+		}
+
+		ordered, err = c.skipRunningRequirements(ctx, rc.Workspace, ordered, rc.Project.Name)
+
+		if err != nil {
+			return err
+		}
+
+		return c.startProjects(ctx, rc.Workspace, ordered)
+	}
+
+	return c.startServices(ctx, rc)
 }
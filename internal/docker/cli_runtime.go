@@ -0,0 +1,157 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/hostsys"
+)
+
+// cliContainer is a line of 'ps --format {{json .}}' output, where the labels
+// are a single comma separated string rather than a map.
+type cliContainer struct {
+	ID     string
+	Names  string
+	Status string
+	Labels string
+}
+
+// cliRuntime queries containers through a docker compatible CLI, for those
+// providers without an engine API to use instead.
+type cliRuntime struct {
+	cli cli
+	cmd string
+}
+
+func parseCLILabels(labels string) map[string]string {
+	out := map[string]string{}
+
+	for _, l := range strings.Split(labels, ",") {
+		if k, v, ok := strings.Cut(l, "="); ok {
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+func (r *cliRuntime) exec(ctx context.Context, args []string) (string, error) {
+	withStdout, outBuff := hostsys.WithStdout()
+	withStderr, errBuff := hostsys.WithStderr()
+
+	if err := r.cli.Exec(ctx, r.cmd, args, withStdout, withStderr); err != nil {
+		slog.Debug(fmt.Sprintf("stderr from %s %s", r.cmd, args[0]), "stderr", errBuff.String())
+		return "", common.ErrCommandExecutionFailed{
+			Msg: fmt.Sprintf("'%s %s' failed: %s", r.cmd, strings.Join(args, " "), strings.TrimSpace(errBuff.String())),
+		}
+	}
+
+	return outBuff.String(), nil
+}
+
+func (r *cliRuntime) ListContainers(ctx context.Context, filter ContainerFilter) ([]Container, error) {
+	args := []string{"ps", "-a", "--no-trunc", "--format", "{{json .}}"}
+
+	for _, k := range slices.Sorted(maps.Keys(filter.Labels)) {
+		args = append(args, "--filter", fmt.Sprintf("label=%s=%s", k, filter.Labels[k]))
+	}
+
+	if filter.RunningOnly {
+		args = append(args, "--filter", "status=running")
+	}
+
+	out, err := r.exec(ctx, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	containers := []Container{}
+
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+
+		c := cliContainer{}
+
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			return nil, common.ErrUnexpectedApiError{
+				Msg: fmt.Sprintf("could not decode the output of '%s ps': %s", r.cmd, err.Error()),
+			}
+		}
+
+		state := "exited"
+		if strings.HasPrefix(c.Status, "Up") {
+			state = "running"
+		}
+
+		containers = append(containers, Container{
+			ID:     c.ID,
+			Names:  []string{c.Names},
+			State:  state,
+			Labels: parseCLILabels(c.Labels),
+		})
+	}
+
+	return containers, nil
+}
+
+func (r *cliRuntime) NetworkExists(ctx context.Context, name string) (bool, error) {
+	out, err := r.exec(ctx, []string{"network", "ls", "--format", "{{.Name}}"})
+
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(strings.Split(strings.TrimSpace(out), "\n"), name), nil
+}
+
+func (r *cliRuntime) VolumeExists(ctx context.Context, name string) (bool, error) {
+	out, err := r.exec(ctx, []string{"volume", "ls", "--format", "{{.Name}}"})
+
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(strings.Split(strings.TrimSpace(out), "\n"), name), nil
+}
+
+func (r *cliRuntime) ListVolumes(ctx context.Context, labels map[string]string) ([]string, error) {
+	args := []string{"volume", "ls", "--format", "{{.Name}}"}
+
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		args = append(args, "--filter", fmt.Sprintf("label=%s=%s", k, labels[k]))
+	}
+
+	out, err := r.exec(ctx, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line != "" {
+			names = append(names, line)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func newCLIRuntime(cli cli, cmd string) *cliRuntime {
+	return &cliRuntime{
+		cli: cli,
+		cmd: cmd,
+	}
+}
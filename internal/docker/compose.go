@@ -1,8 +1,10 @@
 package docker
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/panoptescloud/orca/internal/common"
 	"github.com/panoptescloud/orca/internal/hostsys"
@@ -21,13 +23,29 @@ type tui interface {
 }
 
 type cli interface {
-	Exec(cmdName string, args []string, opts ...hostsys.ExecOpt) (err error)
+	Exec(ctx context.Context, cmdName string, args []string, opts ...hostsys.ExecOpt) (err error)
 }
 
+// containerRuntime answers questions about the state of containers, networks
+// and volumes, ideally without going through the CLI.
+type containerRuntime interface {
+	ListContainers(ctx context.Context, filter ContainerFilter) ([]Container, error)
+	NetworkExists(ctx context.Context, name string) (bool, error)
+	VolumeExists(ctx context.Context, name string) (bool, error)
+	ListVolumes(ctx context.Context, labels map[string]string) ([]string, error)
+}
+
+type providers interface {
+	For(ws *common.Workspace) (*Provider, error)
+}
+
+// Compose manages the lifecycle of projects through the compose CLI, and
+// queries their state through the provider's container runtime.
 type Compose struct {
 	cli              cli
 	tui              tui
 	overlayGenerator composeOverlayGenerator
+	providers        providers
 }
 
 func (c *Compose) getOverlay(ws *common.Workspace, p *common.Project) (string, error) {
@@ -39,33 +57,72 @@ func (c *Compose) goToProject(p *common.Project) error {
 	return os.Chdir(p.ProjectDir)
 }
 
-func buildBaseComposeCommand(ws *common.Workspace, p *common.Project, overlayPath string) []string {
+func composeProjectName(ws *common.Workspace, p *common.Project) string {
+	return fmt.Sprintf("orca-%s-%s", ws.Name, p.Name)
+}
+
+func (c *Compose) getProvider(ws *common.Workspace) (*Provider, error) {
+	provider, err := c.providers.For(ws)
+
+	if err != nil {
+		return nil, c.tui.RecordIfError("Failed to choose the compose provider!", err)
+	}
+
+	return provider, nil
+}
+
+// listServiceContainers returns the project's containers that match the
+// labels. One-off containers (from 'run') are excluded, the same as with
+// 'compose ps'. This is done here as not every provider labels them.
+func (c *Compose) listServiceContainers(ctx context.Context, ws *common.Workspace, p *common.Project, labels map[string]string) ([]Container, error) {
+	provider, err := c.getProvider(ws)
+
+	if err != nil {
+		return nil, err
+	}
+
+	labels[composeProjectLabel] = composeProjectName(ws, p)
+
+	containers, err := provider.runtime.ListContainers(ctx, ContainerFilter{
+		Labels:      labels,
+		RunningOnly: true,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(containers, func(c Container) bool {
+		return c.Labels[composeOneOffLabel] == "True"
+	}), nil
+}
+
+func buildBaseComposeCommand(provider *Provider, ws *common.Workspace, p *common.Project, overlayPath string) []string {
 	envArgs := []string{}
 
 	for _, e := range p.Config.EnvFiles {
 		envArgs = append(envArgs, "--env-file", e.Path)
 	}
 
-	args := []string{
-		"docker",
-		"compose",
+	args := append(provider.composeCommand(),
 		"-f",
 		p.Config.ComposeFiles.Primary,
 		"-f",
 		overlayPath,
 		"-p",
-		fmt.Sprintf("orca-%s-%s", ws.Name, p.Name),
-	}
+		composeProjectName(ws, p),
+	)
 
 	args = append(args, envArgs...)
 
 	return args
 }
 
-func NewCompose(cli cli, tui tui, overlayGenerator composeOverlayGenerator) *Compose {
+func NewCompose(cli cli, tui tui, overlayGenerator composeOverlayGenerator, providers providers) *Compose {
 	return &Compose{
 		cli:              cli,
 		tui:              tui,
 		overlayGenerator: overlayGenerator,
+		providers:        providers,
 	}
 }
@@ -1,14 +1,46 @@
 package docker
 
 import (
+	"context"
 	"os/exec"
+	"slices"
+	"strconv"
 
 	"github.com/panoptescloud/orca/internal/common"
 	"github.com/panoptescloud/orca/internal/hostsys"
 )
 
-// TODO: guard against nil inputs
-func (c *Compose) Exec(ws *common.Workspace, p *common.Project, service string, cmdArgs []string) error {
+// execOptionArgs converts the options to arguments for 'exec' or 'run', which
+// accept the same ones apart from the index.
+func execOptionArgs(provider *Provider, opts common.ExecOptions, withIndex bool) []string {
+	args := []string{"-T"}
+
+	if opts.TTY {
+		args = slices.Clone(provider.interactiveArgs)
+	}
+
+	if opts.User != "" {
+		args = append(args, "--user", opts.User)
+	}
+
+	if opts.Workdir != "" {
+		args = append(args, "--workdir", opts.Workdir)
+	}
+
+	for _, env := range opts.Env {
+		args = append(args, "-e", env)
+	}
+
+	if withIndex && opts.Index > 0 {
+		args = append(args, "--index", strconv.Itoa(opts.Index))
+	}
+
+	return args
+}
+
+// execInService runs the compose subcommand attached to the terminal, it's
+// shared by exec and run.
+func (c *Compose) execInService(ctx context.Context, ws *common.Workspace, p *common.Project, buildArgs func(provider *Provider) []string) error {
 	if err := c.goToProject(p); err != nil {
 		return err
 	}
@@ -18,22 +50,32 @@ func (c *Compose) Exec(ws *common.Workspace, p *common.Project, service string,
 		return c.tui.RecordIfError("Failed to generate overlays!", err)
 	}
 
-	cmd := buildBaseComposeCommand(ws, p, overlay)
-	cmd = append(cmd, "exec", "-it", service)
-	cmd = append(cmd, cmdArgs...)
+	provider, err := c.getProvider(ws)
+	if err != nil {
+		return err
+	}
 
-	err = c.cli.Exec(cmd[0], cmd[1:], hostsys.WithHostIO(), hostsys.ChdirOpt(p.ProjectDir))
+	cmd := buildBaseComposeCommand(provider, ws, p, overlay)
+	cmd = append(cmd, buildArgs(provider)...)
 
-	if err != nil {
-		// If it's exit code 130, it's because they exited an interactive container
-		// typically. Not sure if this is always true though, need to see how this
-		// works across more use-cases.
-		if exitError, ok := err.(*exec.ExitError); ok && exitError.ProcessState.ExitCode() == 130 {
-			return nil
-		}
+	err = c.cli.Exec(ctx, cmd[0], cmd[1:], hostsys.WithHostIO(), hostsys.ChdirOpt(p.ProjectDir))
 
-		return c.tui.RecordIfError("Command failed!", err)
+	// Exit code 130 is typically from Ctrl-C'ing an interactive session, which
+	// isn't worth reporting, but is still passed on.
+	if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 130 {
+		return err
 	}
 
-	return nil
+	return c.tui.RecordIfError("Command failed!", err)
+}
+
+// TODO: guard against nil inputs
+func (c *Compose) Exec(ctx context.Context, ws *common.Workspace, p *common.Project, service string, cmdArgs []string, opts common.ExecOptions) error {
+	return c.execInService(ctx, ws, p, func(provider *Provider) []string {
+		args := []string{"exec"}
+		args = append(args, execOptionArgs(provider, opts, true)...)
+		args = append(args, service)
+
+		return append(args, cmdArgs...)
+	})
 }
@@ -0,0 +1,97 @@
+package docker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Compose_ExecAndRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   string
+		run        bool
+		opts       common.ExecOptions
+		expectArgs []string
+	}{
+		{
+			name:       "exec without a tty",
+			provider:   "docker",
+			expectArgs: []string{"exec", "-T", "php", "php", "-v"},
+		},
+		{
+			name:       "exec with a tty",
+			provider:   "docker",
+			opts:       common.ExecOptions{TTY: true},
+			expectArgs: []string{"exec", "-it", "php", "php", "-v"},
+		},
+		{
+			name:       "podman with a tty",
+			provider:   "podman",
+			opts:       common.ExecOptions{TTY: true},
+			expectArgs: []string{"exec", "php", "php", "-v"},
+		},
+		{
+			name:     "exec with every option",
+			provider: "docker",
+			opts: common.ExecOptions{
+				User:    "www-data",
+				Workdir: "/app",
+				Env:     []string{"APP_ENV=test", "TOKEN"},
+				Index:   2,
+			},
+			expectArgs: []string{
+				"exec", "-T", "--user", "www-data", "--workdir", "/app",
+				"-e", "APP_ENV=test", "-e", "TOKEN", "--index", "2", "php", "php", "-v",
+			},
+		},
+		{
+			name:     "run ignores the index",
+			provider: "docker",
+			run:      true,
+			opts: common.ExecOptions{
+				User:  "www-data",
+				Index: 2,
+				TTY:   true,
+			},
+			expectArgs: []string{"run", "-it", "--user", "www-data", "--rm", "php", "php", "-v"},
+		},
+	}
+
+	ws := &common.Workspace{Name: "test"}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			p := &common.Project{
+				Name:       "api",
+				ProjectDir: tt.TempDir(),
+				Config: common.ProjectConfig{
+					ComposeFiles: common.ComposeFiles{Primary: "docker-compose.yaml"},
+				},
+			}
+
+			tt.Chdir(p.ProjectDir)
+
+			cli := &fakeCli{}
+			providers := docker.NewProviders(cli, func(string) string { return "" }, test.provider, nil)
+			c := docker.NewCompose(cli, fakeTui{}, fakeOverlayGenerator{}, providers)
+
+			var err error
+
+			if test.run {
+				err = c.Run(context.Background(), ws, p, "php", []string{"php", "-v"}, test.opts)
+			} else {
+				err = c.Exec(context.Background(), ws, p, "php", []string{"php", "-v"}, test.opts)
+			}
+
+			require.Nil(tt, err)
+
+			base := []string{test.provider, "compose", "-f", "docker-compose.yaml", "-f", "/overlays/api.yaml", "-p", "orca-test-api"}
+			assert.Equal(tt, [][]string{append(base, test.expectArgs...)}, cli.calls)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+// IsRunning reports whether any of the project's services are running. Only
+// the compose project name is needed for this, so it works for projects that
+// aren't registered locally too.
+func (c *Compose) IsRunning(ctx context.Context, ws *common.Workspace, p *common.Project) (bool, error) {
+	containers, err := c.listServiceContainers(ctx, ws, p, map[string]string{})
+
+	if err != nil {
+		return false, c.tui.RecordIfError("Failed to check if project is running", err)
+	}
+
+	return len(containers) > 0, nil
+}
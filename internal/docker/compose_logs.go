@@ -1,32 +1,79 @@
 package docker
 
 import (
+	"context"
+	"io"
+	"regexp"
+	"strings"
+
 	"github.com/panoptescloud/orca/internal/common"
 	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/panoptescloud/orca/pkg/logmux"
 )
 
+// replicaSuffixPattern matches the index compose adds to each container of a
+// service, e.g. the '-1' in 'php-1'.
+var replicaSuffixPattern = regexp.MustCompile(`-\d+$`)
+
+// parseLogLine turns a line of 'compose logs' output, e.g. 'php-1  | hello',
+// into one prefixed with the project and service, e.g. 'api/php'. Anything
+// else compose prints is prefixed with just the project.
+func parseLogLine(project string, raw string) logmux.Line {
+	name, text, found := strings.Cut(raw, "|")
+
+	if !found {
+		return logmux.Line{
+			Prefix: project,
+			Text:   raw,
+		}
+	}
+
+	name = strings.Trim(strings.TrimSpace(name), "[]")
+	service := replicaSuffixPattern.ReplaceAllString(name, "")
+
+	return logmux.Line{
+		Prefix: project + "/" + service,
+		Text:   strings.TrimPrefix(text, " "),
+	}
+}
+
+// LogSource prepares to read the project's logs, so they can be streamed along
+// with others. The overlays are generated now, as that can't be done for
+// several projects at once.
 // TODO: guard against nil arguments
-func (c *Compose) Logs(ws *common.Workspace, p *common.Project, service string) error {
+func (c *Compose) LogSource(ctx context.Context, ws *common.Workspace, p *common.Project, opts common.LogsOptions) (logmux.Source, error) {
 	if err := c.goToProject(p); err != nil {
-		return err
+		return logmux.Source{}, err
 	}
 
 	overlay, err := c.getOverlay(ws, p)
 	if err != nil {
-		return c.tui.RecordIfError("Failed to generate overlays!", err)
+		return logmux.Source{}, c.tui.RecordIfError("Failed to generate overlays!", err)
 	}
 
-	cmd := buildBaseComposeCommand(ws, p, overlay)
-	cmd = append(cmd, "logs", "-f")
-	if service != "" {
-		cmd = append(cmd, service)
+	provider, err := c.getProvider(ws)
+	if err != nil {
+		return logmux.Source{}, err
 	}
 
-	err = c.cli.Exec(cmd[0], cmd[1:], hostsys.WithHostIO(), hostsys.ChdirOpt(p.ProjectDir))
+	cmd := buildBaseComposeCommand(provider, ws, p, overlay)
+	cmd = append(cmd, "logs", "--no-color")
 
-	if err != nil {
-		return c.tui.RecordIfError("Failed to tail logs!", err)
+	if opts.Follow {
+		cmd = append(cmd, "--follow")
+	}
+
+	if opts.Since != "" {
+		cmd = append(cmd, "--since", opts.Since)
 	}
 
-	return nil
+	return logmux.Source{
+		Name: p.Name,
+		Start: func(w io.Writer) error {
+			return c.cli.Exec(ctx, cmd[0], cmd[1:], hostsys.WithOutputTo(w), hostsys.ChdirOpt(p.ProjectDir))
+		},
+		Parse: func(raw string) logmux.Line {
+			return parseLogLine(p.Name, raw)
+		},
+	}, nil
 }
@@ -0,0 +1,120 @@
+package docker_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/docker"
+	"github.com/panoptescloud/orca/pkg/logmux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOverlayGenerator struct{}
+
+func (fakeOverlayGenerator) CreateOrRetrieve(ws *common.Workspace, p *common.Project) (string, error) {
+	return "/overlays/" + p.Name + ".yaml", nil
+}
+
+func Test_Compose_LogSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       common.LogsOptions
+		expectArgs []string
+	}{
+		{
+			name:       "everything so far",
+			opts:       common.LogsOptions{},
+			expectArgs: []string{"logs", "--no-color"},
+		},
+		{
+			name: "following since",
+			opts: common.LogsOptions{
+				Since:  "10m",
+				Follow: true,
+			},
+			expectArgs: []string{"logs", "--no-color", "--follow", "--since", "10m"},
+		},
+	}
+
+	ws := &common.Workspace{Name: "test"}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			p := &common.Project{
+				Name:       "api",
+				ProjectDir: tt.TempDir(),
+				Config: common.ProjectConfig{
+					ComposeFiles: common.ComposeFiles{Primary: "docker-compose.yaml"},
+				},
+			}
+
+			// The project dir is changed into to generate the overlays
+			tt.Chdir(p.ProjectDir)
+
+			cli := &fakeCli{
+				output: map[string]string{"compose": "php-1  | hello\nnginx-12 | GET / 200\n"},
+			}
+
+			providers := docker.NewProviders(cli, func(string) string { return "" }, "docker", nil)
+			c := docker.NewCompose(cli, fakeTui{}, fakeOverlayGenerator{}, providers)
+
+			source, err := c.LogSource(context.Background(), ws, p, test.opts)
+			require.Nil(tt, err)
+			assert.Equal(tt, "api", source.Name)
+
+			out := &bytes.Buffer{}
+			require.Nil(tt, source.Start(out))
+
+			base := []string{"docker", "compose", "-f", "docker-compose.yaml", "-f", "/overlays/api.yaml", "-p", "orca-test-api"}
+			assert.Equal(tt, [][]string{append(base, test.expectArgs...)}, cli.calls)
+			assert.Equal(tt, "php-1  | hello\nnginx-12 | GET / 200\n", out.String())
+		})
+	}
+}
+
+func Test_Compose_LogSource_Parse(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		expect logmux.Line
+	}{
+		{
+			name:   "service",
+			raw:    "php-1  | hello | world",
+			expect: logmux.Line{Prefix: "api/php", Text: "hello | world"},
+		},
+		{
+			name:   "service with a number in the name",
+			raw:    "worker-2-3 | started",
+			expect: logmux.Line{Prefix: "api/worker-2", Text: "started"},
+		},
+		{
+			name:   "bracketed",
+			raw:    "[db] | ready",
+			expect: logmux.Line{Prefix: "api/db", Text: "ready"},
+		},
+		{
+			name:   "compose message",
+			raw:    "no such service: nope",
+			expect: logmux.Line{Prefix: "api", Text: "no such service: nope"},
+		},
+	}
+
+	p := &common.Project{Name: "api", ProjectDir: t.TempDir()}
+	t.Chdir(p.ProjectDir)
+
+	providers := docker.NewProviders(nil, func(string) string { return "" }, "docker", nil)
+	c := docker.NewCompose(nil, fakeTui{}, fakeOverlayGenerator{}, providers)
+
+	source, err := c.LogSource(context.Background(), &common.Workspace{Name: "test"}, p, common.LogsOptions{})
+	require.Nil(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert.Equal(tt, test.expect, source.Parse(test.raw))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+// OverlayNetworkExists reports whether the network shared by the workspace
+// has been created, which happens when the project it's created in is started.
+func (c *Compose) OverlayNetworkExists(ctx context.Context, ws *common.Workspace) (bool, error) {
+	provider, err := c.providers.For(ws)
+
+	if err != nil {
+		return false, err
+	}
+
+	return provider.runtime.NetworkExists(ctx, overlayNetworkName)
+}
@@ -10,15 +10,17 @@ import (
 type ComposeParser struct {
 }
 
-func (cp *ComposeParser) Parse(paths []string, envFiles []string) (*types.Project, error) {
+func (cp *ComposeParser) load(paths []string, envFiles []string, extra ...composecli.ProjectOptionsFn) (*types.Project, error) {
 	opts, err := composecli.NewProjectOptions(
 		paths,
-		composecli.WithEnvFiles(envFiles...),
-		// Pass all profiles when generating overlays. If not certain services may
-		// be skipped, but we still want them in the overlay. This is mostly to
-		// handle scenarios for one-off commands, that may not be running at all
-		// unless manually invoked.
-		composecli.WithProfiles([]string{"*"}),
+		append([]composecli.ProjectOptionsFn{
+			composecli.WithEnvFiles(envFiles...),
+			// Pass all profiles when generating overlays. If not certain services may
+			// be skipped, but we still want them in the overlay. This is mostly to
+			// handle scenarios for one-off commands, that may not be running at all
+			// unless manually invoked.
+			composecli.WithProfiles([]string{"*"}),
+		}, extra...)...,
 	)
 
 	if err != nil {
@@ -32,6 +34,16 @@ func (cp *ComposeParser) Parse(paths []string, envFiles []string) (*types.Projec
 	return opts.LoadProject(context.Background())
 }
 
+func (cp *ComposeParser) Parse(paths []string, envFiles []string) (*types.Project, error) {
+	return cp.load(paths, envFiles)
+}
+
+// ParseNamed parses the files as the named compose project, so that the names
+// of its resources (e.g. volumes) are the same as when it's run.
+func (cp *ComposeParser) ParseNamed(name string, paths []string, envFiles []string) (*types.Project, error) {
+	return cp.load(paths, envFiles, composecli.WithName(name))
+}
+
 func NewComposeParser() *ComposeParser {
 	return &ComposeParser{}
 }
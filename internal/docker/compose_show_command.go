@@ -17,7 +17,12 @@ func (c *Compose) ShowCommand(ws *common.Workspace, p *common.Project) error {
 		return c.tui.RecordIfError("Failed to generate overlays!", err)
 	}
 
-	cmd := buildBaseComposeCommand(ws, p, overlay)
+	provider, err := c.getProvider(ws)
+	if err != nil {
+		return err
+	}
+
+	cmd := buildBaseComposeCommand(provider, ws, p, overlay)
 
 	c.tui.Info(strings.Join(cmd, " "))
 
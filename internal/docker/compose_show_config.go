@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"context"
 	"errors"
 
 	"github.com/panoptescloud/orca/internal/common"
@@ -8,7 +9,7 @@ import (
 )
 
 // TODO: guard against nil inputs
-func (c *Compose) ShowConfig(ws *common.Workspace, p *common.Project) error {
+func (c *Compose) ShowConfig(ctx context.Context, ws *common.Workspace, p *common.Project) error {
 	if err := c.goToProject(p); err != nil {
 		return err
 	}
@@ -18,13 +19,18 @@ func (c *Compose) ShowConfig(ws *common.Workspace, p *common.Project) error {
 		return c.tui.RecordIfError("Failed to generate overlays!", err)
 	}
 
-	cmd := buildBaseComposeCommand(ws, p, overlay)
+	provider, err := c.getProvider(ws)
+	if err != nil {
+		return err
+	}
+
+	cmd := buildBaseComposeCommand(provider, ws, p, overlay)
 	cmd = append(cmd, "config")
 
 	stdErrOpt, stderr := hostsys.WithStderr()
 	stdOutOpt, stdout := hostsys.WithStdout()
 
-	err = c.cli.Exec(cmd[0], cmd[1:], stdErrOpt, stdOutOpt, hostsys.ChdirOpt(p.ProjectDir))
+	err = c.cli.Exec(ctx, cmd[0], cmd[1:], stdErrOpt, stdOutOpt, hostsys.ChdirOpt(p.ProjectDir))
 
 	if err != nil {
 		return c.tui.RecordIfError("Failed to display config!", errors.New(stderr.String()))
@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/panoptescloud/orca/internal/common"
@@ -8,7 +9,7 @@ import (
 )
 
 // TODO: guard against nil arguments
-func (c *Compose) Up(ws *common.Workspace, p *common.Project) error {
+func (c *Compose) Up(ctx context.Context, ws *common.Workspace, p *common.Project) error {
 	if err := c.goToProject(p); err != nil {
 		return err
 	}
@@ -18,12 +19,17 @@ func (c *Compose) Up(ws *common.Workspace, p *common.Project) error {
 		return c.tui.RecordIfError("Failed to generate overlays!", err)
 	}
 
+	provider, err := c.getProvider(ws)
+	if err != nil {
+		return err
+	}
+
 	c.tui.Info(fmt.Sprintf("%s:%s[%s] starting...", p.Name, ws.Name, p.ProjectDir))
 	c.tui.NewLine()
-	cmd := buildBaseComposeCommand(ws, p, overlay)
+	cmd := buildBaseComposeCommand(provider, ws, p, overlay)
 	cmd = append(cmd, "up", "-d")
 
-	err = c.cli.Exec(cmd[0], cmd[1:], hostsys.WithHostIO(), hostsys.ChdirOpt(p.ProjectDir))
+	err = c.cli.Exec(ctx, cmd[0], cmd[1:], hostsys.WithHostIO(), hostsys.ChdirOpt(p.ProjectDir))
 
 	if err != nil {
 		return c.tui.RecordIfError(fmt.Sprintf("%s:%s[%s] failed!", p.Name, ws.Name, p.ProjectDir), err)
@@ -0,0 +1,121 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// defaultDockerContext is the context the docker CLI uses when none has been
+// chosen, it's the engine at the default socket (or DOCKER_HOST).
+const defaultDockerContext = "default"
+
+// dockerCLIConfig is the part of the docker CLI's config.json that's needed.
+type dockerCLIConfig struct {
+	CurrentContext string `json:"currentContext"`
+}
+
+// dockerContextMeta is the part of a context's meta.json that's needed.
+type dockerContextMeta struct {
+	Endpoints map[string]struct {
+		Host string `json:"Host"`
+	} `json:"Endpoints"`
+}
+
+func dockerConfigDir(getenv func(string) string) string {
+	if dir := getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".docker")
+}
+
+func currentDockerContext(configDir string) (string, error) {
+	contents, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return defaultDockerContext, nil
+		}
+
+		return "", err
+	}
+
+	cfg := dockerCLIConfig{}
+
+	if err := json.Unmarshal(contents, &cfg); err != nil {
+		return "", fmt.Errorf("docker config is not valid JSON: %w", err)
+	}
+
+	return cfg.CurrentContext, nil
+}
+
+// dockerContextHost reads the endpoint of a context created with 'docker
+// context create', or by tools like Docker Desktop, Colima and OrbStack. The
+// CLI stores each one in a directory named after the hash of its name.
+func dockerContextHost(configDir string, name string) (string, error) {
+	hash := sha256.Sum256([]byte(name))
+	path := filepath.Join(configDir, "contexts", "meta", hex.EncodeToString(hash[:]), "meta.json")
+
+	contents, err := os.ReadFile(path)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("docker context '%s' does not exist", name)
+		}
+
+		return "", err
+	}
+
+	meta := dockerContextMeta{}
+
+	if err := json.Unmarshal(contents, &meta); err != nil {
+		return "", fmt.Errorf("docker context '%s' is not valid JSON: %w", name, err)
+	}
+
+	host := meta.Endpoints["docker"].Host
+
+	if host == "" {
+		return "", fmt.Errorf("docker context '%s' has no docker endpoint", name)
+	}
+
+	return host, nil
+}
+
+// DockerHost finds the endpoint of the docker engine in the same way the
+// docker CLI does. DOCKER_HOST takes precedence, then the context named by
+// DOCKER_CONTEXT, then the current context in the CLI's config. The default
+// context is the engine at the default socket.
+func DockerHost(getenv func(string) string) (string, error) {
+	if host := getenv("DOCKER_HOST"); host != "" {
+		return host, nil
+	}
+
+	configDir := dockerConfigDir(getenv)
+	name := getenv("DOCKER_CONTEXT")
+
+	if name == "" {
+		current, err := currentDockerContext(configDir)
+
+		if err != nil {
+			return "", err
+		}
+
+		name = current
+	}
+
+	if name == "" || name == defaultDockerContext {
+		return "unix://" + DefaultEngineSocket, nil
+	}
+
+	return dockerContextHost(configDir, name)
+}
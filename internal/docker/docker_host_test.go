@@ -0,0 +1,96 @@
+package docker_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDockerContext creates a context in the same layout as 'docker context
+// create' does.
+func writeDockerContext(t *testing.T, configDir string, name string, host string) {
+	hash := sha256.Sum256([]byte(name))
+	dir := filepath.Join(configDir, "contexts", "meta", hex.EncodeToString(hash[:]))
+
+	require.Nil(t, os.MkdirAll(dir, 0755))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "meta.json"), []byte(`{"Name":"`+name+`","Metadata":{},"Endpoints":{"docker":{"Host":"`+host+`","SkipTLSVerify":false}}}`), 0644))
+}
+
+func Test_DockerHost(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		current   string
+		expect    string
+		expectErr error
+	}{
+		{
+			name:   "nothing set",
+			expect: "unix:///var/run/docker.sock",
+		},
+		{
+			name: "DOCKER_HOST takes precedence",
+			env: map[string]string{
+				"DOCKER_HOST":    "tcp://127.0.0.1:2375",
+				"DOCKER_CONTEXT": "desktop-linux",
+			},
+			current: "colima",
+			expect:  "tcp://127.0.0.1:2375",
+		},
+		{
+			name:    "current context",
+			current: "desktop-linux",
+			expect:  "unix:///home/dev/.docker/run/docker.sock",
+		},
+		{
+			name: "DOCKER_CONTEXT over the current context",
+			env: map[string]string{
+				"DOCKER_CONTEXT": "colima",
+			},
+			current: "desktop-linux",
+			expect:  "unix:///home/dev/.colima/default/docker.sock",
+		},
+		{
+			name: "default context",
+			env: map[string]string{
+				"DOCKER_CONTEXT": "default",
+			},
+			current: "colima",
+			expect:  "unix:///var/run/docker.sock",
+		},
+		{
+			name:      "unknown context",
+			current:   "orbstack",
+			expectErr: errors.New("docker context 'orbstack' does not exist"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			configDir := tt.TempDir()
+			writeDockerContext(tt, configDir, "desktop-linux", "unix:///home/dev/.docker/run/docker.sock")
+			writeDockerContext(tt, configDir, "colima", "unix:///home/dev/.colima/default/docker.sock")
+
+			if test.current != "" {
+				require.Nil(tt, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"auths":{},"currentContext":"`+test.current+`"}`), 0644))
+			}
+
+			env := map[string]string{"DOCKER_CONFIG": configDir}
+			for k, v := range test.env {
+				env[k] = v
+			}
+
+			host, err := docker.DockerHost(func(k string) string { return env[k] })
+
+			assert.Equal(tt, test.expectErr, err)
+			assert.Equal(tt, test.expect, host)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+const (
+	// DefaultEngineSocket is where the docker engine listens, unless DOCKER_HOST
+	// or the docker context says otherwise.
+	DefaultEngineSocket = "/var/run/docker.sock"
+
+	// engineApiVersion is the oldest version of the API (docker 20.10) with
+	// everything used here, so that older engines still work.
+	engineApiVersion = "v1.41"
+
+	engineTimeout = 10 * time.Second
+
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+	composeOneOffLabel  = "com.docker.compose.oneoff"
+)
+
+// Container is the part of the engine's container summary that orca uses.
+type Container struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	State  string            `json:"State"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// Service is the compose service the container belongs to, if any.
+func (c Container) Service() string {
+	return c.Labels[composeServiceLabel]
+}
+
+type ContainerFilter struct {
+	// Labels must all match exactly.
+	Labels map[string]string
+
+	// RunningOnly excludes containers that are stopped, paused etc.
+	RunningOnly bool
+}
+
+type network struct {
+	ID   string `json:"Id"`
+	Name string `json:"Name"`
+}
+
+type volumeList struct {
+	Volumes []struct {
+		Name string `json:"Name"`
+	} `json:"Volumes"`
+}
+
+// Engine queries the docker engine API directly over its unix socket. This
+// avoids running the docker CLI (often several times) just to answer a
+// question about the state of containers.
+type Engine struct {
+	socket string
+	client *http.Client
+}
+
+func encodeFilters(filters map[string][]string) (string, error) {
+	out, err := json.Marshal(filters)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+func (e *Engine) get(ctx context.Context, path string, query url.Values, out any) error {
+	// The host is ignored, as every request is sent over the socket
+	u := url.URL{
+		Scheme:   "http",
+		Host:     "docker",
+		Path:     fmt.Sprintf("/%s%s", engineApiVersion, path),
+		RawQuery: query.Encode(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Do(req)
+
+	if err != nil {
+		return common.ErrContainerEngineUnavailable{
+			Socket: e.socket,
+			Reason: err.Error(),
+		}
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := struct {
+			Message string `json:"message"`
+		}{}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+
+		return common.ErrUnexpectedApiError{
+			Msg: fmt.Sprintf("got %d response from the container engine for %s: %s", resp.StatusCode, path, apiErr.Message),
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return common.ErrUnexpectedApiError{
+			Msg: fmt.Sprintf("could not decode the container engine response for %s: %s", path, err.Error()),
+		}
+	}
+
+	return nil
+}
+
+// ListContainers returns every container (running or not) matching the filter.
+func (e *Engine) ListContainers(ctx context.Context, filter ContainerFilter) ([]Container, error) {
+	filters := map[string][]string{}
+
+	for _, k := range slices.Sorted(maps.Keys(filter.Labels)) {
+		filters["label"] = append(filters["label"], fmt.Sprintf("%s=%s", k, filter.Labels[k]))
+	}
+
+	if filter.RunningOnly {
+		filters["status"] = []string{"running"}
+	}
+
+	encoded, err := encodeFilters(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	containers := []Container{}
+
+	err = e.get(ctx, "/containers/json", url.Values{
+		"all":     []string{"true"},
+		"filters": []string{encoded},
+	}, &containers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return containers, nil
+}
+
+// NetworkExists reports whether there is a network with exactly this name.
+func (e *Engine) NetworkExists(ctx context.Context, name string) (bool, error) {
+	// The engine matches names partially, so they are checked again below
+	encoded, err := encodeFilters(map[string][]string{
+		"name": {name},
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	networks := []network{}
+
+	if err := e.get(ctx, "/networks", url.Values{"filters": []string{encoded}}, &networks); err != nil {
+		return false, err
+	}
+
+	for _, n := range networks {
+		if n.Name == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// VolumeExists reports whether there is a volume with exactly this name.
+func (e *Engine) VolumeExists(ctx context.Context, name string) (bool, error) {
+	// As with networks, names are matched partially
+	encoded, err := encodeFilters(map[string][]string{
+		"name": {name},
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	volumes := volumeList{}
+
+	if err := e.get(ctx, "/volumes", url.Values{"filters": []string{encoded}}, &volumes); err != nil {
+		return false, err
+	}
+
+	for _, v := range volumes.Volumes {
+		if v.Name == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ListVolumes returns the names of the volumes with all of the labels, sorted.
+func (e *Engine) ListVolumes(ctx context.Context, labels map[string]string) ([]string, error) {
+	filters := map[string][]string{}
+
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		filters["label"] = append(filters["label"], fmt.Sprintf("%s=%s", k, labels[k]))
+	}
+
+	encoded, err := encodeFilters(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := volumeList{}
+
+	if err := e.get(ctx, "/volumes", url.Values{"filters": []string{encoded}}, &volumes); err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for _, v := range volumes.Volumes {
+		names = append(names, v.Name)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func NewEngine(socket string) *Engine {
+	return &Engine{
+		socket: socket,
+		client: &http.Client{
+			Timeout: engineTimeout,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+					var d net.Dialer
+
+					return d.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
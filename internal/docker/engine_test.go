@@ -0,0 +1,280 @@
+package docker_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEngine serves canned responses on a unix socket, recording the query of
+// each request it receives.
+type fakeEngine struct {
+	socket   string
+	status   int
+	response any
+	queries  []url.Values
+	paths    []string
+}
+
+func (f *fakeEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.paths = append(f.paths, r.URL.Path)
+	f.queries = append(f.queries, r.URL.Query())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(f.status)
+	_ = json.NewEncoder(w).Encode(f.response)
+}
+
+func (f *fakeEngine) filters(t *testing.T, i int) map[string][]string {
+	filters := map[string][]string{}
+	require.Nil(t, json.Unmarshal([]byte(f.queries[i].Get("filters")), &filters))
+
+	return filters
+}
+
+func newFakeEngine(t *testing.T, status int, response any) *fakeEngine {
+	// Socket paths are limited to ~100 characters, which the test temp dir can
+	// easily exceed.
+	dir, err := os.MkdirTemp("", "orca-engine")
+	require.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	f := &fakeEngine{
+		socket:   filepath.Join(dir, "docker.sock"),
+		status:   status,
+		response: response,
+	}
+
+	l, err := net.Listen("unix", f.socket)
+	require.Nil(t, err)
+
+	srv := &http.Server{Handler: f}
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+
+	return f
+}
+
+type fakeTui struct{}
+
+func (fakeTui) Info(msg ...string)    {}
+func (fakeTui) Error(msg ...string)   {}
+func (fakeTui) Success(msg ...string) {}
+func (fakeTui) NewLine()              {}
+
+func (fakeTui) RecordIfError(msg string, err error) error {
+	return err
+}
+
+func Test_Engine_ListContainers(t *testing.T) {
+	f := newFakeEngine(t, http.StatusOK, []map[string]any{
+		{
+			"Id":     "abc123",
+			"Names":  []string{"/orca-test-api-php-1"},
+			"State":  "running",
+			"Labels": map[string]string{"com.docker.compose.service": "php"},
+		},
+	})
+
+	containers, err := docker.NewEngine(f.socket).ListContainers(context.Background(), docker.ContainerFilter{
+		Labels: map[string]string{
+			"com.docker.compose.project": "orca-test-api",
+			"com.docker.compose.service": "php",
+		},
+		RunningOnly: true,
+	})
+
+	require.Nil(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, "abc123", containers[0].ID)
+	assert.Equal(t, "php", containers[0].Service())
+
+	assert.Equal(t, "/v1.41/containers/json", f.paths[0])
+	assert.Equal(t, "true", f.queries[0].Get("all"))
+	assert.Equal(t, map[string][]string{
+		"label": {
+			"com.docker.compose.project=orca-test-api",
+			"com.docker.compose.service=php",
+		},
+		"status": {"running"},
+	}, f.filters(t, 0))
+}
+
+func Test_Engine_NetworkExists(t *testing.T) {
+	tests := []struct {
+		name     string
+		networks []map[string]string
+		expect   bool
+	}{
+		{
+			name:     "exact match",
+			networks: []map[string]string{{"Name": "orca-ws"}},
+			expect:   true,
+		},
+		{
+			name:     "partial match only",
+			networks: []map[string]string{{"Name": "orca-ws-old"}},
+			expect:   false,
+		},
+		{
+			name:     "none",
+			networks: []map[string]string{},
+			expect:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			f := newFakeEngine(tt, http.StatusOK, test.networks)
+
+			exists, err := docker.NewEngine(f.socket).NetworkExists(context.Background(), "orca-ws")
+
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expect, exists)
+			assert.Equal(tt, "/v1.41/networks", f.paths[0])
+			assert.Equal(tt, map[string][]string{"name": {"orca-ws"}}, f.filters(tt, 0))
+		})
+	}
+}
+
+func Test_Engine_Errors(t *testing.T) {
+	t.Run("error response", func(tt *testing.T) {
+		f := newFakeEngine(tt, http.StatusInternalServerError, map[string]string{"message": "something broke"})
+
+		_, err := docker.NewEngine(f.socket).ListContainers(context.Background(), docker.ContainerFilter{})
+
+		assert.Equal(tt, common.ErrUnexpectedApiError{
+			Msg: "got 500 response from the container engine for /containers/json: something broke",
+		}, err)
+	})
+
+	t.Run("not running", func(tt *testing.T) {
+		socket := filepath.Join(tt.TempDir(), "missing.sock")
+
+		_, err := docker.NewEngine(socket).NetworkExists(context.Background(), "orca-ws")
+
+		assert.IsType(tt, common.ErrContainerEngineUnavailable{}, err)
+	})
+}
+
+func Test_Compose_IsSvcRunning(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []map[string]any
+		expect     bool
+	}{
+		{
+			name: "running",
+			containers: []map[string]any{
+				{"Id": "abc123", "Labels": map[string]string{"com.docker.compose.oneoff": "False"}},
+			},
+			expect: true,
+		},
+		{
+			name: "only a one-off container",
+			containers: []map[string]any{
+				{"Id": "abc123", "Labels": map[string]string{"com.docker.compose.oneoff": "True"}},
+			},
+			expect: false,
+		},
+		{
+			name:       "not running",
+			containers: []map[string]any{},
+			expect:     false,
+		},
+	}
+
+	ws := &common.Workspace{Name: "test"}
+	p := &common.Project{Name: "api"}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			f := newFakeEngine(tt, http.StatusOK, test.containers)
+			getenv := func(name string) string {
+				if name == "DOCKER_HOST" {
+					return "unix://" + f.socket
+				}
+
+				return ""
+			}
+
+			providers := docker.NewProviders(nil, getenv, "docker", nil)
+			c := docker.NewCompose(nil, fakeTui{}, nil, providers)
+
+			running, err := c.IsSvcRunning(context.Background(), ws, p, "php")
+
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expect, running)
+			assert.Equal(tt, map[string][]string{
+				"label": {
+					"com.docker.compose.project=orca-test-api",
+					"com.docker.compose.service=php",
+				},
+				"status": {"running"},
+			}, f.filters(tt, 0))
+		})
+	}
+}
+
+func Test_Engine_VolumeExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		volumes []map[string]string
+		expect  bool
+	}{
+		{
+			name:    "exact match",
+			volumes: []map[string]string{{"Name": "orca-ws-api_pgdata"}},
+			expect:  true,
+		},
+		{
+			name:    "partial match only",
+			volumes: []map[string]string{{"Name": "orca-ws-api_pgdata-old"}},
+			expect:  false,
+		},
+		{
+			name:    "none",
+			volumes: []map[string]string{},
+			expect:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			f := newFakeEngine(tt, http.StatusOK, map[string]any{"Volumes": test.volumes})
+
+			exists, err := docker.NewEngine(f.socket).VolumeExists(context.Background(), "orca-ws-api_pgdata")
+
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expect, exists)
+			assert.Equal(tt, "/v1.41/volumes", f.paths[0])
+			assert.Equal(tt, map[string][]string{"name": {"orca-ws-api_pgdata"}}, f.filters(tt, 0))
+		})
+	}
+}
+
+func Test_Engine_ListVolumes(t *testing.T) {
+	f := newFakeEngine(t, http.StatusOK, map[string]any{"Volumes": []map[string]string{
+		{"Name": "orca-ws-api_pgdata"},
+		{"Name": "orca-ws-api_cache"},
+	}})
+
+	names, err := docker.NewEngine(f.socket).ListVolumes(context.Background(), map[string]string{
+		"com.docker.compose.project": "orca-ws-api",
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []string{"orca-ws-api_cache", "orca-ws-api_pgdata"}, names)
+	assert.Equal(t, "/v1.41/volumes", f.paths[0])
+	assert.Equal(t, map[string][]string{"label": {"com.docker.compose.project=orca-ws-api"}}, f.filters(t, 0))
+}
@@ -14,6 +14,10 @@ import (
 )
 
 const networkOverlaidLabel = "orca.panoptescloud.overlay-enabled/network"
+
+// overlayNetworkName is the docker network shared by every project in the
+// workspace when the network overlay is enabled.
+const overlayNetworkName = "orca-ws"
 const aliasesOverlaidLabel = "orca.panoptescloud.overlay-enabled/aliases"
 
 const tlsInjectCertsLabel = "orca.pantoptescloud.tls/inject-certs"
@@ -79,12 +83,12 @@ func (ogc *overlayGenerationContext) AddRootNetworkConfig() {
 
 	if ogc.ws.OverlayConfig.Network.CreateIn == ogc.p.Name {
 		ogc.new.Networks["orca"] = types.NetworkConfig{
-			Name:   "orca-ws",
+			Name:   overlayNetworkName,
 			Labels: labels,
 		}
 	} else {
 		ogc.new.Networks["orca"] = types.NetworkConfig{
-			Name:     "orca-ws",
+			Name:     overlayNetworkName,
 			External: true,
 			Labels:   labels,
 		}
@@ -267,6 +271,11 @@ func (cog *ComposeOverlayGenerator) CreateOrRetrieve(ws *common.Workspace, p *co
 	return overlayPath, nil
 }
 
+// RemoveOverlays removes every overlay generated for the workspace.
+func (cog *ComposeOverlayGenerator) RemoveOverlays(wsName string) error {
+	return cog.fs.RemoveAll(fmt.Sprintf("%s/%s", cog.overlayDir, wsName))
+}
+
 func NewComposeOverlayGenerator(fs afero.Fs, parser overlayComposeParser, overlayDir string, tlsCertsDir string) *ComposeOverlayGenerator {
 	return &ComposeOverlayGenerator{
 		fs:          fs,
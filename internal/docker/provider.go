@@ -0,0 +1,147 @@
+package docker
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+// defaultPodmanSocket is where a rootful podman service listens.
+const defaultPodmanSocket = "/run/podman/podman.sock"
+
+// Provider is an implementation of compose. They all accept the same commands
+// as docker compose, the differences between them are kept in here.
+type Provider struct {
+	Name string
+
+	// interactiveArgs are given to exec and run for an interactive session.
+	// podman-compose doesn't accept -i or -t, but allocates a TTY by default.
+	interactiveArgs []string
+
+	runtime containerRuntime
+}
+
+func (p *Provider) composeCommand() []string {
+	return []string{p.Name, "compose"}
+}
+
+// podmanSocketFromEnv finds the socket of the podman API service, preferring
+// the rootless one for the current user.
+func podmanSocketFromEnv(getenv func(string) string) string {
+	if socket, ok := strings.CutPrefix(getenv("CONTAINER_HOST"), "unix://"); ok && socket != "" {
+		return socket
+	}
+
+	if runtimeDir := getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return filepath.Join(runtimeDir, "podman", "podman.sock")
+	}
+
+	return defaultPodmanSocket
+}
+
+// Providers chooses the compose provider for each workspace. The one in the
+// user's config takes precedence, as it's specific to their machine, followed
+// by the one in the workspace config, and finally the one that was detected.
+type Providers struct {
+	cli        cli
+	getenv     func(string) string
+	configured string
+
+	// detect is only used when no provider has been chosen, as it has to search
+	// the PATH.
+	detect func() string
+
+	built map[string]*Provider
+}
+
+func isSocket(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.Mode()&os.ModeSocket != 0
+}
+
+// dockerRuntime queries the engine through its API, if it's reachable over a
+// local unix socket. Otherwise (e.g. a tcp or ssh endpoint) the docker CLI is
+// used, as it knows how to reach the engine.
+func (ps *Providers) dockerRuntime() containerRuntime {
+	host, err := DockerHost(ps.getenv)
+
+	if err != nil {
+		slog.Debug("could not find the docker endpoint, using the CLI", "error", err)
+		return newCLIRuntime(ps.cli, common.ComposeProviderDocker)
+	}
+
+	if socket, ok := strings.CutPrefix(host, "unix://"); ok && isSocket(socket) {
+		return NewEngine(socket)
+	}
+
+	slog.Debug("docker endpoint is not a local unix socket, using the CLI", "host", host)
+
+	return newCLIRuntime(ps.cli, common.ComposeProviderDocker)
+}
+
+// Get builds the named provider. Docker and podman are both queried through
+// their (compatible) engine APIs, nerdctl doesn't have one so its CLI is used.
+// Docker also falls back to its CLI when the engine isn't on a local socket.
+func (ps *Providers) Get(name string) (*Provider, error) {
+	if p, ok := ps.built[name]; ok {
+		return p, nil
+	}
+
+	var p *Provider
+
+	switch name {
+	case common.ComposeProviderDocker:
+		p = &Provider{
+			Name:            name,
+			interactiveArgs: []string{"-it"},
+			runtime:         ps.dockerRuntime(),
+		}
+	case common.ComposeProviderPodman:
+		p = &Provider{
+			Name:            name,
+			interactiveArgs: []string{},
+			runtime:         NewEngine(podmanSocketFromEnv(ps.getenv)),
+		}
+	case common.ComposeProviderNerdctl:
+		p = &Provider{
+			Name:            name,
+			interactiveArgs: []string{"-it"},
+			runtime:         newCLIRuntime(ps.cli, name),
+		}
+	default:
+		return nil, common.ErrUnknownComposeProvider{
+			Name: name,
+		}
+	}
+
+	ps.built[name] = p
+
+	return p, nil
+}
+
+// For returns the provider to use for the workspace.
+func (ps *Providers) For(ws *common.Workspace) (*Provider, error) {
+	if ps.configured != "" {
+		return ps.Get(ps.configured)
+	}
+
+	if ws != nil && ws.ComposeProvider != "" {
+		return ps.Get(ws.ComposeProvider)
+	}
+
+	return ps.Get(ps.detect())
+}
+
+func NewProviders(cli cli, getenv func(string) string, configured string, detect func() string) *Providers {
+	return &Providers{
+		cli:        cli,
+		getenv:     getenv,
+		configured: configured,
+		detect:     detect,
+		built:      map[string]*Provider{},
+	}
+}
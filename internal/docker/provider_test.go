@@ -0,0 +1,195 @@
+package docker_test
+
+import (
+	"context"
+	"net/http"
+	"os/exec"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/docker"
+	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCli records the commands it's asked to run, and writes the output
+// configured for the subcommand to stdout.
+type fakeCli struct {
+	calls  [][]string
+	output map[string]string
+}
+
+func (f *fakeCli) Exec(_ context.Context, cmdName string, args []string, opts ...hostsys.ExecOpt) error {
+	f.calls = append(f.calls, append([]string{cmdName}, args...))
+
+	cmd := &exec.Cmd{}
+	for _, opt := range opts {
+		opt(cmd)
+	}
+
+	if cmd.Stdout != nil {
+		_, _ = cmd.Stdout.Write([]byte(f.output[args[0]]))
+	}
+
+	return nil
+}
+
+func Test_Providers_For(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		workspace  string
+		expect     string
+	}{
+		{
+			name:   "detected",
+			expect: "nerdctl",
+		},
+		{
+			name:      "from the workspace",
+			workspace: "podman",
+			expect:    "podman",
+		},
+		{
+			name:       "user config takes precedence",
+			configured: "docker",
+			workspace:  "podman",
+			expect:     "docker",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			providers := docker.NewProviders(nil, func(string) string { return "" }, test.configured, func() string {
+				return "nerdctl"
+			})
+
+			p, err := providers.For(&common.Workspace{ComposeProvider: test.workspace})
+
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expect, p.Name)
+		})
+	}
+}
+
+func Test_Providers_Unknown(t *testing.T) {
+	providers := docker.NewProviders(nil, func(string) string { return "" }, "finch", nil)
+
+	_, err := providers.For(&common.Workspace{})
+
+	assert.Equal(t, common.ErrUnknownComposeProvider{Name: "finch"}, err)
+}
+
+func Test_Providers_DockerEndpoint(t *testing.T) {
+	f := newFakeEngine(t, http.StatusOK, []map[string]any{})
+
+	tests := []struct {
+		name       string
+		host       string
+		expectCli  bool
+		expectPath bool
+	}{
+		{
+			name:       "local socket uses the engine",
+			host:       "unix://" + f.socket,
+			expectPath: true,
+		},
+		{
+			name:      "tcp uses the cli",
+			host:      "tcp://127.0.0.1:2375",
+			expectCli: true,
+		},
+		{
+			name:      "missing socket uses the cli",
+			host:      "unix:///nonexistent/docker.sock",
+			expectCli: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			f.paths = nil
+
+			configDir := tt.TempDir()
+			writeDockerContext(tt, configDir, "remote", test.host)
+
+			env := map[string]string{
+				"DOCKER_CONFIG":  configDir,
+				"DOCKER_CONTEXT": "remote",
+			}
+
+			cli := &fakeCli{}
+			providers := docker.NewProviders(cli, func(k string) string { return env[k] }, "docker", nil)
+			c := docker.NewCompose(cli, fakeTui{}, nil, providers)
+
+			running, err := c.IsSvcRunning(context.Background(), &common.Workspace{Name: "test"}, &common.Project{Name: "api"}, "php")
+
+			require.Nil(tt, err)
+			assert.False(tt, running)
+			assert.Equal(tt, test.expectCli, len(cli.calls) == 1 && cli.calls[0][0] == "docker")
+			assert.Equal(tt, test.expectPath, len(f.paths) == 1)
+		})
+	}
+}
+
+func Test_Compose_IsSvcRunning_ThroughCli(t *testing.T) {
+	cli := &fakeCli{
+		output: map[string]string{
+			"ps": `{"ID":"abc123","Names":"orca-test-api-php-1","Status":"Up 2 minutes","Labels":"com.docker.compose.project=orca-test-api,com.docker.compose.service=php"}
+{"ID":"def456","Names":"orca-test-api-php-run-1","Status":"Up 1 minute","Labels":"com.docker.compose.oneoff=True"}
+`,
+		},
+	}
+
+	providers := docker.NewProviders(cli, func(string) string { return "" }, "nerdctl", nil)
+	c := docker.NewCompose(cli, fakeTui{}, nil, providers)
+
+	running, err := c.IsSvcRunning(context.Background(), &common.Workspace{Name: "test"}, &common.Project{Name: "api"}, "php")
+
+	require.Nil(t, err)
+	assert.True(t, running)
+	assert.Equal(t, [][]string{
+		{
+			"nerdctl", "ps", "-a", "--no-trunc", "--format", "{{json .}}",
+			"--filter", "label=com.docker.compose.project=orca-test-api",
+			"--filter", "label=com.docker.compose.service=php",
+			"--filter", "status=running",
+		},
+	}, cli.calls)
+}
+
+func Test_Compose_OverlayNetworkExists_ThroughCli(t *testing.T) {
+	tests := []struct {
+		name     string
+		networks string
+		expect   bool
+	}{
+		{
+			name:     "exists",
+			networks: "bridge\norca-ws\n",
+			expect:   true,
+		},
+		{
+			name:     "missing",
+			networks: "bridge\norca-ws-old\n",
+			expect:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			cli := &fakeCli{
+				output: map[string]string{"network": test.networks},
+			}
+
+			providers := docker.NewProviders(cli, func(string) string { return "" }, "nerdctl", nil)
+			c := docker.NewCompose(cli, fakeTui{}, nil, providers)
+
+			exists, err := c.OverlayNetworkExists(context.Background(), &common.Workspace{})
+
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expect, exists)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/hostsys"
+)
+
+const (
+	// volumeHelperImage is run with a volume mounted, to copy its contents in
+	// or out. It only needs a shell and tar.
+	volumeHelperImage = "alpine:3"
+
+	// volumeMountPoint is where the volume is mounted in the helper container.
+	volumeMountPoint = "/data"
+
+	composeVolumeLabel = "com.docker.compose.volume"
+
+	// importScript extracts the archive into a staging dir within the volume,
+	// and only once that's succeeded swaps it for the existing contents. If
+	// the archive can't be extracted, the volume is left as it was.
+	importScript = `set -e
+staging=%[1]s/%[2]s
+trap 'rm -rf "$staging"' EXIT
+rm -rf "$staging"
+mkdir "$staging"
+tar -xzf - -C "$staging"
+find %[1]s -mindepth 1 -maxdepth 1 ! -name %[2]s -exec rm -rf {} +
+find "$staging" -mindepth 1 -maxdepth 1 -exec mv {} %[1]s/ \;`
+
+	// importStagingDir is hidden, and unlikely to be used by anything else.
+	importStagingDir = ".orca-import"
+)
+
+type volumeComposeParser interface {
+	ParseNamed(name string, paths []string, envFiles []string) (*types.Project, error)
+}
+
+// Volumes manages the contents of the named volumes in a project. As the
+// volumes may be in a VM (e.g. docker desktop), their contents are streamed
+// through a short lived container with the volume mounted, rather than being
+// read from disk.
+type Volumes struct {
+	cli       cli
+	parser    volumeComposeParser
+	providers providers
+}
+
+// Declared returns the named volumes in the project's compose file, sorted by
+// their key. External volumes are left out, as they aren't the project's to
+// manage.
+func (v *Volumes) Declared(ws *common.Workspace, p *common.Project) ([]common.Volume, error) {
+	// Compose is run from the project dir, so that's what the env files are
+	// relative to, rather than wherever orca is
+	envFiles := p.EnvFilePaths()
+
+	for i, path := range envFiles {
+		if !filepath.IsAbs(path) {
+			envFiles[i] = filepath.Join(p.ProjectDir, path)
+		}
+	}
+
+	composeProject, err := v.parser.ParseNamed(
+		composeProjectName(ws, p),
+		[]string{
+			fmt.Sprintf("%s/%s", p.ProjectDir, p.Config.ComposeFiles.Primary),
+		},
+		envFiles,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := []common.Volume{}
+
+	for _, key := range slices.Sorted(maps.Keys(composeProject.Volumes)) {
+		cfg := composeProject.Volumes[key]
+
+		if cfg.External {
+			continue
+		}
+
+		volumes = append(volumes, common.Volume{
+			Key:  key,
+			Name: cfg.Name,
+		})
+	}
+
+	return volumes, nil
+}
+
+// Exists reports whether the volume has been created, compose only does so
+// the first time the project is started.
+func (v *Volumes) Exists(ctx context.Context, ws *common.Workspace, vol common.Volume) (bool, error) {
+	provider, err := v.providers.For(ws)
+
+	if err != nil {
+		return false, err
+	}
+
+	return provider.runtime.VolumeExists(ctx, vol.Name)
+}
+
+// Created returns the names of the volumes compose has created for the
+// project, whether or not they're still declared in its compose file.
+func (v *Volumes) Created(ctx context.Context, ws *common.Workspace, p *common.Project) ([]string, error) {
+	provider, err := v.providers.For(ws)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.runtime.ListVolumes(ctx, map[string]string{
+		composeProjectLabel: composeProjectName(ws, p),
+	})
+}
+
+func (v *Volumes) run(ctx context.Context, provider *Provider, args []string, opts ...hostsys.ExecOpt) error {
+	withStderr, errBuff := hostsys.WithStderr()
+
+	if err := v.cli.Exec(ctx, provider.Name, args, append(opts, withStderr)...); err != nil {
+		slog.Debug(fmt.Sprintf("stderr from %s %s", provider.Name, args[0]), "stderr", errBuff.String())
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return common.ErrCommandExecutionFailed{
+			Msg: fmt.Sprintf("'%s %s' failed: %s", provider.Name, strings.Join(args, " "), strings.TrimSpace(errBuff.String())),
+		}
+	}
+
+	return nil
+}
+
+// Export writes the contents of the volume to w, as a gzipped tar.
+func (v *Volumes) Export(ctx context.Context, ws *common.Workspace, vol common.Volume, w io.Writer) error {
+	provider, err := v.providers.For(ws)
+
+	if err != nil {
+		return err
+	}
+
+	return v.run(ctx, provider, []string{
+		"run", "--rm",
+		"-v", fmt.Sprintf("%s:%s:ro", vol.Name, volumeMountPoint),
+		volumeHelperImage,
+		"tar", "-czf", "-", "-C", volumeMountPoint, ".",
+	}, hostsys.WithStdoutTo(w))
+}
+
+// Import replaces the contents of the volume with the gzipped tar read from
+// r, keeping the existing contents if it can't be extracted. If the volume
+// doesn't exist yet, it's created with the labels compose would have given
+// it, so compose will use it rather than complaining.
+func (v *Volumes) Import(ctx context.Context, ws *common.Workspace, p *common.Project, vol common.Volume, r io.Reader) error {
+	provider, err := v.providers.For(ws)
+
+	if err != nil {
+		return err
+	}
+
+	exists, err := provider.runtime.VolumeExists(ctx, vol.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		err := v.run(ctx, provider, []string{
+			"volume", "create",
+			"--label", fmt.Sprintf("%s=%s", composeProjectLabel, composeProjectName(ws, p)),
+			"--label", fmt.Sprintf("%s=%s", composeVolumeLabel, vol.Key),
+			vol.Name,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return v.run(ctx, provider, []string{
+		"run", "--rm", "-i",
+		"-v", fmt.Sprintf("%s:%s", vol.Name, volumeMountPoint),
+		volumeHelperImage,
+		"sh", "-c", fmt.Sprintf(importScript, volumeMountPoint, importStagingDir),
+	}, hostsys.WithStdinFrom(r))
+}
+
+// Remove deletes the volume, along with everything in it.
+func (v *Volumes) Remove(ctx context.Context, ws *common.Workspace, vol common.Volume) error {
+	provider, err := v.providers.For(ws)
+
+	if err != nil {
+		return err
+	}
+
+	return v.run(ctx, provider, []string{"volume", "rm", vol.Name})
+}
+
+func NewVolumes(cli cli, parser volumeComposeParser, providers providers) *Volumes {
+	return &Volumes{
+		cli:       cli,
+		parser:    parser,
+		providers: providers,
+	}
+}
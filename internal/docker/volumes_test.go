@@ -0,0 +1,173 @@
+package docker_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const volumesTestCompose = `services:
+  db:
+    image: postgres
+    volumes:
+      - pgdata:/var/lib/postgresql/data
+      - shared:/shared
+  cache:
+    image: redis
+    volumes:
+      - cache:/data
+volumes:
+  pgdata: {}
+  cache:
+    name: ${CACHE_VOLUME}
+  shared:
+    external: true
+`
+
+// importScript is what the volume's contents are replaced with, it's run with
+// sh in the helper container.
+const importScript = `set -e
+staging=/data/.orca-import
+trap 'rm -rf "$staging"' EXIT
+rm -rf "$staging"
+mkdir "$staging"
+tar -xzf - -C "$staging"
+find /data -mindepth 1 -maxdepth 1 ! -name .orca-import -exec rm -rf {} +
+find "$staging" -mindepth 1 -maxdepth 1 -exec mv {} /data/ \;`
+
+func Test_Volumes_Declared(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "docker-compose.yaml"), []byte(volumesTestCompose), 0644))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, ".env.orca"), []byte("CACHE_VOLUME=api-cache\n"), 0644))
+
+	ws := &common.Workspace{Name: "test"}
+	p := &common.Project{
+		Name:       "api",
+		ProjectDir: dir,
+		Config: common.ProjectConfig{
+			ComposeFiles: common.ComposeFiles{Primary: "docker-compose.yaml"},
+			EnvFiles:     []common.EnvFile{{Path: ".env.orca"}},
+		},
+	}
+
+	volumes := docker.NewVolumes(nil, docker.NewComposeParser(), nil)
+
+	declared, err := volumes.Declared(ws, p)
+
+	require.Nil(t, err)
+	assert.Equal(t, []common.Volume{
+		{Key: "cache", Name: "api-cache"},
+		{Key: "pgdata", Name: "orca-test-api_pgdata"},
+	}, declared)
+}
+
+func Test_Volumes_ThroughCli(t *testing.T) {
+	ws := &common.Workspace{Name: "test"}
+	p := &common.Project{Name: "api"}
+	vol := common.Volume{Key: "pgdata", Name: "orca-test-api_pgdata"}
+
+	tests := []struct {
+		name    string
+		volumes string
+		run     func(v *docker.Volumes) error
+		expect  [][]string
+	}{
+		{
+			name: "export",
+			run: func(v *docker.Volumes) error {
+				return v.Export(context.Background(), ws, vol, &bytes.Buffer{})
+			},
+			expect: [][]string{
+				{
+					"nerdctl", "run", "--rm", "-v", "orca-test-api_pgdata:/data:ro", "alpine:3",
+					"tar", "-czf", "-", "-C", "/data", ".",
+				},
+			},
+		},
+		{
+			name:    "import into an existing volume",
+			volumes: "orca-test-api_pgdata\n",
+			run: func(v *docker.Volumes) error {
+				return v.Import(context.Background(), ws, p, vol, &bytes.Buffer{})
+			},
+			expect: [][]string{
+				{"nerdctl", "volume", "ls", "--format", "{{.Name}}"},
+				{
+					"nerdctl", "run", "--rm", "-i", "-v", "orca-test-api_pgdata:/data", "alpine:3",
+					"sh", "-c", importScript,
+				},
+			},
+		},
+		{
+			name: "import creates the volume for compose",
+			run: func(v *docker.Volumes) error {
+				return v.Import(context.Background(), ws, p, vol, &bytes.Buffer{})
+			},
+			expect: [][]string{
+				{"nerdctl", "volume", "ls", "--format", "{{.Name}}"},
+				{
+					"nerdctl", "volume", "create",
+					"--label", "com.docker.compose.project=orca-test-api",
+					"--label", "com.docker.compose.volume=pgdata",
+					"orca-test-api_pgdata",
+				},
+				{
+					"nerdctl", "run", "--rm", "-i", "-v", "orca-test-api_pgdata:/data", "alpine:3",
+					"sh", "-c", importScript,
+				},
+			},
+		},
+		{
+			name:    "created",
+			volumes: "orca-test-api_pgdata\norca-test-api_cache\n",
+			run: func(v *docker.Volumes) error {
+				names, err := v.Created(context.Background(), ws, p)
+
+				if err == nil && !slices.Equal(names, []string{"orca-test-api_cache", "orca-test-api_pgdata"}) {
+					return fmt.Errorf("unexpected volumes: %v", names)
+				}
+
+				return err
+			},
+			expect: [][]string{
+				{
+					"nerdctl", "volume", "ls", "--format", "{{.Name}}",
+					"--filter", "label=com.docker.compose.project=orca-test-api",
+				},
+			},
+		},
+		{
+			name: "remove",
+			run: func(v *docker.Volumes) error {
+				return v.Remove(context.Background(), ws, vol)
+			},
+			expect: [][]string{
+				{"nerdctl", "volume", "rm", "orca-test-api_pgdata"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			cli := &fakeCli{
+				output: map[string]string{"volume": test.volumes},
+			}
+
+			providers := docker.NewProviders(cli, func(string) string { return "" }, "nerdctl", nil)
+
+			err := test.run(docker.NewVolumes(cli, docker.NewComposeParser(), providers))
+
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expect, cli.calls)
+		})
+	}
+}
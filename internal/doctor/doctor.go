@@ -0,0 +1,447 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/pkg/dag"
+	"github.com/spf13/afero"
+)
+
+// DefaultHostsFile is where the host entries are checked for.
+const DefaultHostsFile = "/etc/hosts"
+
+// detachedHead is the branch reported by git when HEAD is detached.
+const detachedHead = "(detached)"
+
+type tui interface {
+	Info(msg ...string)
+	Error(msg ...string)
+	Success(msg ...string)
+	NewLine()
+	RecordIfError(msg string, err error) error
+}
+
+type config interface {
+	GetCurrentWorkspace() string
+}
+
+type workspaceRepo interface {
+	LoadWithProblems(name string) (*common.Workspace, map[string]error, error)
+	Validate(name string) ([]error, error)
+}
+
+type git interface {
+	StatusIn(ctx context.Context, dir string) (common.RepoStatus, error)
+}
+
+type composeParser interface {
+	Parse(paths []string, envFiles []string) (*types.Project, error)
+}
+
+type docker interface {
+	OverlayNetworkExists(ctx context.Context, ws *common.Workspace) (bool, error)
+}
+
+type certificates interface {
+	GetRootCertificateExpiry() (time.Time, error)
+	GetCertificateExpiry(domain string) (time.Time, error)
+}
+
+type hostSystem interface {
+	VerifySetup() error
+}
+
+// Doctor checks everything a workspace needs in order to run, and suggests how
+// to fix anything that's wrong.
+type Doctor struct {
+	fs            afero.Fs
+	tui           tui
+	cfg           config
+	workspaceRepo workspaceRepo
+	git           git
+	composeParser composeParser
+	docker        docker
+	certificates  certificates
+	hostSystem    hostSystem
+	hostsFile     string
+	now           func() time.Time
+}
+
+// diagnosis records the outcome of the checks as they're made.
+type diagnosis struct {
+	wsName   string
+	problems int
+}
+
+func (d *Doctor) pass(msg string) {
+	d.tui.Success(fmt.Sprintf("✓ %s", msg))
+}
+
+func (d *Doctor) fail(diag *diagnosis, msg string, fix string) {
+	diag.problems++
+	d.tui.Error(fmt.Sprintf("✗ %s", msg))
+	d.tui.Info(fmt.Sprintf("  fix: %s", fix))
+}
+
+func (d *Doctor) section(title string) {
+	d.tui.NewLine()
+	d.tui.Info(title)
+}
+
+func (d *Doctor) checkTools(diag *diagnosis) {
+	d.section("Tools")
+
+	if err := d.hostSystem.VerifySetup(); err != nil {
+		d.fail(diag, "required tools are missing or not working", "install the tools listed above, see 'orca sys check'")
+	}
+}
+
+func (d *Doctor) checkConfig(diag *diagnosis) {
+	d.section("Config")
+
+	problems, err := d.workspaceRepo.Validate(diag.wsName)
+
+	if err != nil {
+		d.fail(diag, fmt.Sprintf("could not validate the config: %s", err.Error()), "check the workspace is registered with 'orca ws ls'")
+		return
+	}
+
+	if len(problems) == 0 {
+		d.pass("workspace and project config is valid")
+		return
+	}
+
+	for _, p := range problems {
+		lines := []string{p.Error()}
+
+		if invalid, ok := p.(common.ErrInvalidConfig); ok {
+			lines = invalid.Lines()
+		}
+
+		for _, line := range lines {
+			d.fail(diag, line, "correct the config, 'orca ws validate' lists every problem")
+		}
+	}
+}
+
+func (d *Doctor) checkRequires(diag *diagnosis, ws *common.Workspace) {
+	g, err := dag.NewGraph(ws.Projects)
+
+	if err == nil {
+		_, err = g.TopologicalKeysFromRoots()
+	}
+
+	if err != nil {
+		d.fail(diag, fmt.Sprintf("projects 'requires' do not form a valid graph: %s", err.Error()), "remove the cycle, or the requirement on an unknown project")
+		return
+	}
+
+	d.pass("project requirements form a valid graph")
+}
+
+func (d *Doctor) checkGit(ctx context.Context, diag *diagnosis, p common.Project) {
+	status, err := d.git.StatusIn(ctx, p.ProjectDir)
+
+	if err != nil {
+		d.fail(diag, fmt.Sprintf("%s is not a git repository: %s", p.ProjectDir, err.Error()), fmt.Sprintf("re-clone it with 'orca ws clone -w %s -p %s'", diag.wsName, p.Name))
+		return
+	}
+
+	if status.Branch == detachedHead {
+		d.fail(diag, "HEAD is detached", "check out a branch")
+		return
+	}
+
+	expected := p.RepositoryConfig.Branch
+
+	if expected != "" && status.Branch != expected {
+		d.fail(diag, fmt.Sprintf("on branch '%s', expected '%s'", status.Branch, expected), fmt.Sprintf("run 'git checkout %s' in %s", expected, p.ProjectDir))
+		return
+	}
+
+	d.pass(fmt.Sprintf("git repository on branch '%s'", status.Branch))
+}
+
+func resolvePath(dir string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+func (d *Doctor) checkFileExists(diag *diagnosis, kind string, path string) bool {
+	exists, err := afero.Exists(d.fs, path)
+
+	if err != nil || !exists {
+		d.fail(diag, fmt.Sprintf("%s %s does not exist", kind, path), "create it, or correct the path in the project config")
+		return false
+	}
+
+	return true
+}
+
+func (d *Doctor) checkComposeFiles(diag *diagnosis, p common.Project) {
+	envFiles := []string{}
+	envFilesExist := true
+
+	for _, e := range p.Config.EnvFiles {
+		path := resolvePath(p.ProjectDir, e.Path)
+
+		if !d.checkFileExists(diag, "env file", path) {
+			envFilesExist = false
+			continue
+		}
+
+		envFiles = append(envFiles, path)
+	}
+
+	for _, extra := range p.Config.ComposeFiles.Extras {
+		d.checkFileExists(diag, "compose file", resolvePath(p.ProjectDir, extra.Path))
+	}
+
+	primary := resolvePath(p.ProjectDir, p.Config.ComposeFiles.Primary)
+
+	if !d.checkFileExists(diag, "compose file", primary) || !envFilesExist {
+		return
+	}
+
+	if _, err := d.composeParser.Parse([]string{primary}, envFiles); err != nil {
+		d.fail(diag, fmt.Sprintf("compose file %s could not be parsed: %s", primary, err.Error()), fmt.Sprintf("run 'docker compose config' in %s for more detail", p.ProjectDir))
+		return
+	}
+
+	d.pass("compose files and env files are valid")
+}
+
+// checkProject checks the project is on disk and in a usable state. If its
+// config couldn't be loaded, that's already been reported by checkConfig, so
+// only the checks that don't need it are run.
+func (d *Doctor) checkProject(ctx context.Context, diag *diagnosis, p common.Project, configErr error) {
+	d.section(fmt.Sprintf("Project '%s'", p.Name))
+
+	if !p.IsRegistered {
+		d.fail(diag, "not cloned", fmt.Sprintf("clone it with 'orca ws clone -w %s -p %s', or use 'orca ws project set-path' if it already exists", diag.wsName, p.Name))
+		return
+	}
+
+	isDir, err := afero.IsDir(d.fs, p.ProjectDir)
+
+	if err != nil || !isDir {
+		d.fail(diag, fmt.Sprintf("directory %s does not exist", p.ProjectDir), fmt.Sprintf("use 'orca ws project set-path -w %s %s <path>' if it has moved", diag.wsName, p.Name))
+		return
+	}
+
+	d.pass(fmt.Sprintf("cloned to %s", p.ProjectDir))
+
+	d.checkGit(ctx, diag, p)
+
+	if configErr != nil {
+		d.tui.Info("  compose files not checked, as the project config could not be loaded")
+		return
+	}
+
+	d.checkComposeFiles(diag, p)
+}
+
+func (d *Doctor) checkNetwork(ctx context.Context, diag *diagnosis, ws *common.Workspace) {
+	if !ws.OverlayConfig.Network.Enabled {
+		return
+	}
+
+	exists, err := d.docker.OverlayNetworkExists(ctx, ws)
+
+	if err != nil {
+		d.fail(diag, fmt.Sprintf("could not check for the overlay network: %s", err.Error()), "check the container engine is running")
+		return
+	}
+
+	if !exists {
+		d.fail(diag, "the overlay network does not exist", fmt.Sprintf("start '%s' with 'orca up -w %s -p %s', the network is created with it", ws.OverlayConfig.Network.CreateIn, diag.wsName, ws.OverlayConfig.Network.CreateIn))
+		return
+	}
+
+	d.pass("the overlay network exists")
+}
+
+func (d *Doctor) checkCertificate(diag *diagnosis, name string, expiry func() (time.Time, error)) {
+	fix := fmt.Sprintf("run 'orca tls gen -w %s'", diag.wsName)
+	notAfter, err := expiry()
+
+	if err != nil {
+		if _, ok := err.(common.ErrFileNotFound); ok {
+			d.fail(diag, fmt.Sprintf("%s does not exist", name), fix)
+			return
+		}
+
+		d.fail(diag, fmt.Sprintf("%s could not be read: %s", name, err.Error()), fmt.Sprintf("remove it, then %s", fix))
+		return
+	}
+
+	if d.now().After(notAfter) {
+		d.fail(diag, fmt.Sprintf("%s expired on %s", name, notAfter.Format(time.DateOnly)), fmt.Sprintf("remove it, then %s", fix))
+		return
+	}
+
+	d.pass(fmt.Sprintf("%s is valid until %s", name, notAfter.Format(time.DateOnly)))
+}
+
+func (d *Doctor) checkCertificates(diag *diagnosis, ws *common.Workspace) {
+	certs := ws.GetUniqueTLSCertificates()
+
+	if len(certs) == 0 {
+		return
+	}
+
+	d.checkCertificate(diag, "the root certificate", d.certificates.GetRootCertificateExpiry)
+
+	for _, c := range certs {
+		d.checkCertificate(diag, fmt.Sprintf("the certificate for '%s'", c), func() (time.Time, error) {
+			return d.certificates.GetCertificateExpiry(c)
+		})
+	}
+}
+
+// readHostEntries returns every host name in the hosts file.
+func (d *Doctor) readHostEntries() ([]string, error) {
+	contents, err := afero.ReadFile(d.fs, d.hostsFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []string{}
+
+	for _, line := range strings.Split(string(contents), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+
+		if len(fields) > 1 {
+			hosts = append(hosts, fields[1:]...)
+		}
+	}
+
+	return hosts, nil
+}
+
+func (d *Doctor) checkHosts(diag *diagnosis, ws *common.Workspace) {
+	required := ws.GetUniqueHosts()
+
+	if len(required) == 0 {
+		return
+	}
+
+	fix := fmt.Sprintf("add the output of 'orca hosts -w %s' to %s", diag.wsName, d.hostsFile)
+	entries, err := d.readHostEntries()
+
+	if err != nil {
+		d.fail(diag, fmt.Sprintf("could not read %s: %s", d.hostsFile, err.Error()), fix)
+		return
+	}
+
+	missing := []string{}
+
+	for _, h := range required {
+		if !slices.Contains(entries, h) {
+			missing = append(missing, h)
+		}
+	}
+
+	if len(missing) > 0 {
+		d.fail(diag, fmt.Sprintf("missing host entries: %s", strings.Join(missing, ", ")), fix)
+		return
+	}
+
+	d.pass("all host entries are present")
+}
+
+type DiagnoseDTO struct {
+	WorkspaceName string
+}
+
+// Diagnose runs every check for the workspace, reporting each problem along
+// with how to fix it. An error is returned if any problems were found.
+func (d *Doctor) Diagnose(ctx context.Context, dto DiagnoseDTO) error {
+	diag := &diagnosis{
+		wsName: dto.WorkspaceName,
+	}
+
+	if diag.wsName == "" {
+		diag.wsName = d.cfg.GetCurrentWorkspace()
+	}
+
+	d.checkTools(diag)
+	d.checkConfig(diag)
+
+	// Problems with a project's config have been reported above, but
+	// shouldn't stop everything else being checked
+	ws, configErrs, err := d.workspaceRepo.LoadWithProblems(diag.wsName)
+
+	if err != nil {
+		d.tui.NewLine()
+		d.tui.Error("The remaining checks need the config to load, fix the problems above and run again.")
+
+		return common.ErrCommandExecutionFailed{
+			Msg: fmt.Sprintf("%d problem(s) found", max(diag.problems, 1)),
+		}
+	}
+
+	d.checkRequires(diag, ws)
+
+	for _, p := range ws.Projects {
+		d.checkProject(ctx, diag, p, configErrs[p.Name])
+	}
+
+	d.section("Workspace")
+	d.checkNetwork(ctx, diag, ws)
+	d.checkCertificates(diag, ws)
+	d.checkHosts(diag, ws)
+
+	d.tui.NewLine()
+
+	if diag.problems > 0 {
+		d.tui.Error(fmt.Sprintf("%d problem(s) found.", diag.problems))
+
+		return common.ErrCommandExecutionFailed{
+			Msg: fmt.Sprintf("%d problem(s) found", diag.problems),
+		}
+	}
+
+	d.tui.Success("No problems found!")
+
+	return nil
+}
+
+func NewDoctor(
+	fs afero.Fs,
+	tui tui,
+	cfg config,
+	workspaceRepo workspaceRepo,
+	git git,
+	composeParser composeParser,
+	docker docker,
+	certificates certificates,
+	hostSystem hostSystem,
+	hostsFile string,
+) *Doctor {
+	return &Doctor{
+		fs:            fs,
+		tui:           tui,
+		cfg:           cfg,
+		workspaceRepo: workspaceRepo,
+		git:           git,
+		composeParser: composeParser,
+		docker:        docker,
+		certificates:  certificates,
+		hostSystem:    hostSystem,
+		hostsFile:     hostsFile,
+		now:           time.Now,
+	}
+}
@@ -0,0 +1,292 @@
+package doctor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/config"
+	"github.com/panoptescloud/orca/internal/doctor"
+	"github.com/panoptescloud/orca/internal/repository"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTui struct {
+	errors []string
+	infos  []string
+}
+
+func (r *recordingTui) Info(msg ...string)    { r.infos = append(r.infos, msg...) }
+func (r *recordingTui) Error(msg ...string)   { r.errors = append(r.errors, msg...) }
+func (r *recordingTui) Success(msg ...string) {}
+func (r *recordingTui) NewLine()              {}
+
+func (r *recordingTui) RecordIfError(msg string, err error) error {
+	return err
+}
+
+type fakeConfig struct{}
+
+func (fakeConfig) GetCurrentWorkspace() string { return "test" }
+
+type fakeRepo struct {
+	ws       *common.Workspace
+	problems []error
+}
+
+func (f fakeRepo) LoadWithProblems(name string) (*common.Workspace, map[string]error, error) {
+	if len(f.problems) > 0 {
+		return nil, nil, f.problems[0]
+	}
+
+	return f.ws, map[string]error{}, nil
+}
+
+func (f fakeRepo) Validate(name string) ([]error, error) {
+	return f.problems, nil
+}
+
+type fakeGit struct {
+	branches map[string]string
+}
+
+func (f fakeGit) StatusIn(ctx context.Context, dir string) (common.RepoStatus, error) {
+	branch, ok := f.branches[dir]
+
+	if !ok {
+		return common.RepoStatus{}, errors.New("not a git repository")
+	}
+
+	return common.RepoStatus{Branch: branch}, nil
+}
+
+type fakeParser struct{}
+
+func (fakeParser) Parse(paths []string, envFiles []string) (*types.Project, error) {
+	return &types.Project{}, nil
+}
+
+type fakeDocker struct {
+	networkExists bool
+}
+
+func (f fakeDocker) OverlayNetworkExists(ctx context.Context, ws *common.Workspace) (bool, error) {
+	return f.networkExists, nil
+}
+
+type fakeCertificates struct {
+	expiries map[string]time.Time
+}
+
+func (f fakeCertificates) GetRootCertificateExpiry() (time.Time, error) {
+	return f.GetCertificateExpiry("root")
+}
+
+func (f fakeCertificates) GetCertificateExpiry(domain string) (time.Time, error) {
+	expiry, ok := f.expiries[domain]
+
+	if !ok {
+		return time.Time{}, common.ErrFileNotFound{Path: domain}
+	}
+
+	return expiry, nil
+}
+
+type fakeHostSystem struct{}
+
+func (fakeHostSystem) VerifySetup() error { return nil }
+
+func buildWorkspace() *common.Workspace {
+	return &common.Workspace{
+		Name: "test",
+		OverlayConfig: common.OverlayConfig{
+			Network: common.NetworkOverlayConfig{
+				Enabled:  true,
+				CreateIn: "api",
+			},
+		},
+		Projects: []common.Project{
+			{
+				Name:         "api",
+				IsRegistered: true,
+				ProjectDir:   "/projects/api",
+				RepositoryConfig: common.ProjectRepositoryConfig{
+					Branch: "main",
+				},
+				Config: common.ProjectConfig{
+					ComposeFiles: common.ComposeFiles{
+						Primary: "docker-compose.yaml",
+					},
+					EnvFiles: []common.EnvFile{
+						{Path: ".env"},
+					},
+					Hosts:           []string{"api.test"},
+					TLSCertificates: []string{"api.test"},
+				},
+			},
+			{
+				Name:     "web",
+				Requires: []string{"api"},
+			},
+		},
+	}
+}
+
+func Test_Diagnose(t *testing.T) {
+	tests := []struct {
+		name           string
+		branch         string
+		envFile        bool
+		networkExists  bool
+		hosts          string
+		certExpiry     time.Time
+		expectErrors   []string
+		expectProblems int
+	}{
+		{
+			name:          "only the unregistered project",
+			branch:        "main",
+			envFile:       true,
+			networkExists: true,
+			hosts:         "127.0.0.1 localhost\n127.0.0.1    api.test # orca\n",
+			certExpiry:    time.Now().AddDate(1, 0, 0),
+			expectErrors: []string{
+				"✗ not cloned",
+				"1 problem(s) found.",
+			},
+			expectProblems: 1,
+		},
+		{
+			name:       "everything else broken",
+			branch:     "feature",
+			hosts:      "127.0.0.1 localhost\n# 127.0.0.1 api.test\n",
+			certExpiry: time.Now().AddDate(-1, 0, 0),
+			expectErrors: []string{
+				"✗ on branch 'feature', expected 'main'",
+				"✗ env file /projects/api/.env does not exist",
+				"✗ not cloned",
+				"✗ the overlay network does not exist",
+				"✗ the certificate for 'api.test' expired on " + time.Now().AddDate(-1, 0, 0).Format(time.DateOnly),
+				"✗ missing host entries: api.test",
+				"6 problem(s) found.",
+			},
+			expectProblems: 6,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.Nil(tt, afero.WriteFile(fs, "/projects/api/docker-compose.yaml", []byte("services: {}"), 0644))
+			require.Nil(tt, afero.WriteFile(fs, "/etc/hosts", []byte(test.hosts), 0644))
+
+			if test.envFile {
+				require.Nil(tt, afero.WriteFile(fs, "/projects/api/.env", []byte(""), 0644))
+			}
+
+			tui := &recordingTui{}
+			d := doctor.NewDoctor(
+				fs,
+				tui,
+				fakeConfig{},
+				fakeRepo{ws: buildWorkspace()},
+				fakeGit{branches: map[string]string{"/projects/api": test.branch}},
+				fakeParser{},
+				fakeDocker{networkExists: test.networkExists},
+				fakeCertificates{expiries: map[string]time.Time{
+					"root":     time.Now().AddDate(10, 0, 0),
+					"api.test": test.certExpiry,
+				}},
+				fakeHostSystem{},
+				"/etc/hosts",
+			)
+
+			err := d.Diagnose(context.Background(), doctor.DiagnoseDTO{})
+
+			assert.Equal(tt, test.expectErrors, tui.errors)
+			assert.Equal(tt, common.ErrCommandExecutionFailed{
+				Msg: fmt.Sprintf("%d problem(s) found", test.expectProblems),
+			}, err)
+		})
+	}
+}
+
+func Test_Diagnose_InvalidConfig(t *testing.T) {
+	tui := &recordingTui{}
+	d := doctor.NewDoctor(
+		afero.NewMemMapFs(),
+		tui,
+		fakeConfig{},
+		fakeRepo{problems: []error{common.ErrWorkspaceConfigNotFound{Path: "/ws/orca.workspace.yaml"}}},
+		fakeGit{},
+		fakeParser{},
+		fakeDocker{},
+		fakeCertificates{},
+		fakeHostSystem{},
+		"/etc/hosts",
+	)
+
+	err := d.Diagnose(context.Background(), doctor.DiagnoseDTO{})
+
+	assert.Equal(t, common.ErrCommandExecutionFailed{Msg: "1 problem(s) found"}, err)
+	assert.Equal(t, []string{
+		"✗ workspace config expected at '/ws/orca.workspace.yaml' was not found",
+		"The remaining checks need the config to load, fix the problems above and run again.",
+	}, tui.errors)
+}
+
+func Test_Diagnose_MovedProject(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.Nil(t, afero.WriteFile(fs, "/ws/orca.workspace.yaml", []byte(`name: test
+overlays:
+  network:
+    enabled: true
+    createIn: api
+projects:
+  - name: api
+  - name: web
+`), 0644))
+	require.Nil(t, afero.WriteFile(fs, "/projects/api/orca.project.yaml", []byte(`composeFiles:
+  primary: docker-compose.yaml
+`), 0644))
+	require.Nil(t, afero.WriteFile(fs, "/projects/api/docker-compose.yaml", []byte("services: {}"), 0644))
+	require.Nil(t, afero.WriteFile(fs, "/etc/hosts", []byte("127.0.0.1 localhost\n"), 0644))
+
+	cfg := config.NewDefaultConfig(fs, "/home/orca.yaml")
+	require.Nil(t, cfg.LoadOrCreate())
+	require.Nil(t, cfg.AddWorkspace("/ws/orca.workspace.yaml", "test"))
+	require.Nil(t, cfg.SetProjectPath("test", "api", "/projects/api"))
+	// Registered, but since moved
+	require.Nil(t, cfg.SetProjectPath("test", "web", "/projects/web"))
+
+	tui := &recordingTui{}
+	d := doctor.NewDoctor(
+		fs,
+		tui,
+		fakeConfig{},
+		repository.NewWorkspaceRepository(fs, cfg),
+		fakeGit{branches: map[string]string{"/projects/api": "main"}},
+		fakeParser{},
+		fakeDocker{networkExists: false},
+		fakeCertificates{},
+		fakeHostSystem{},
+		"/etc/hosts",
+	)
+
+	err := d.Diagnose(context.Background(), doctor.DiagnoseDTO{})
+
+	assert.Equal(t, common.ErrCommandExecutionFailed{Msg: "3 problem(s) found"}, err)
+	assert.Equal(t, []string{
+		"✗ project config expected at '/projects/web/orca.project.yaml' was not found",
+		"✗ directory /projects/web does not exist",
+		"✗ the overlay network does not exist",
+		"3 problem(s) found.",
+	}, tui.errors)
+	assert.Contains(t, tui.infos, "  fix: use 'orca ws project set-path -w test web <path>' if it has moved")
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -104,13 +105,13 @@ func parseGitBranchOutput(output string) Branches {
 	return branches
 }
 
-func (g *Git) searchBranches(dto SearchBranchesDTO) (Branches, error) {
-	if err := g.mustBeInAGitRepository(); err != nil {
+func (g *Git) searchBranches(ctx context.Context, dto SearchBranchesDTO) (Branches, error) {
+	if err := g.mustBeInAGitRepository(ctx); err != nil {
 		return nil, err
 	}
 
 	opt, stdout := hostsys.WithStdout()
-	err := g.exec.Exec("git", []string{
+	err := g.exec.Exec(ctx, "git", []string{
 		"branch",
 		"-l",
 	}, opt)
@@ -130,8 +131,8 @@ func (g *Git) searchBranches(dto SearchBranchesDTO) (Branches, error) {
 	return branches.StrictSearch(dto.Search), nil
 }
 
-func (g *Git) ShowBranches(dto SearchBranchesDTO) error {
-	branches, err := g.searchBranches(dto)
+func (g *Git) ShowBranches(ctx context.Context, dto SearchBranchesDTO) error {
+	branches, err := g.searchBranches(ctx, dto)
 	if err != nil {
 		return g.tui.RecordIfError(
 			"Failed to list branches!",
@@ -156,8 +157,8 @@ func (g *Git) ShowBranches(dto SearchBranchesDTO) error {
 	return nil
 }
 
-func (g *Git) GetCurrentBranch() (string, error) {
-	branches, err := g.searchBranches(SearchBranchesDTO{})
+func (g *Git) GetCurrentBranch(ctx context.Context) (string, error) {
+	branches, err := g.searchBranches(ctx, SearchBranchesDTO{})
 
 	if err != nil {
 		return "", err
@@ -174,15 +175,15 @@ type PullBranchDTO struct {
 	Name string
 }
 
-func (g *Git) PullBranch(dto PullBranchDTO) error {
-	if err := g.mustBeInAGitRepository(); err != nil {
+func (g *Git) PullBranch(ctx context.Context, dto PullBranchDTO) error {
+	if err := g.mustBeInAGitRepository(ctx); err != nil {
 		return err
 	}
 
 	branchToPull := dto.Name
 
 	if dto.Name == "" {
-		current, err := g.GetCurrentBranch()
+		current, err := g.GetCurrentBranch(ctx)
 
 		if err != nil {
 			return err
@@ -191,7 +192,7 @@ func (g *Git) PullBranch(dto PullBranchDTO) error {
 		branchToPull = current
 	}
 
-	err := g.exec.Exec("git", []string{
+	err := g.exec.Exec(ctx, "git", []string{
 		"pull",
 		"origin",
 		branchToPull,
@@ -1,6 +1,8 @@
 package git
 
 import (
+	"context"
+
 	"github.com/panoptescloud/orca/internal/common"
 	"github.com/panoptescloud/orca/internal/hostsys"
 )
@@ -9,8 +11,8 @@ type CheckoutDTO struct {
 	Name string
 }
 
-func (g *Git) performCheckout(branch string) error {
-	err := g.exec.Exec("git", []string{
+func (g *Git) performCheckout(ctx context.Context, branch string) error {
+	err := g.exec.Exec(ctx, "git", []string{
 		"checkout",
 		branch,
 	}, hostsys.WithHostIO())
@@ -18,20 +20,20 @@ func (g *Git) performCheckout(branch string) error {
 	return err
 }
 
-func (g *Git) Checkout(dto CheckoutDTO) (string, error) {
-	if err := g.mustBeInAGitRepository(); err != nil {
+func (g *Git) Checkout(ctx context.Context, dto CheckoutDTO) (string, error) {
+	if err := g.mustBeInAGitRepository(ctx); err != nil {
 		return "", err
 	}
 
 	// Checkout the previously checked out branch, handle it as a special case
 	if dto.Name == "-" {
-		err := g.performCheckout("-")
+		err := g.performCheckout(ctx, "-")
 
 		if err != nil {
 			return "", err
 		}
 
-		branch, err := g.GetCurrentBranch()
+		branch, err := g.GetCurrentBranch(ctx)
 
 		if err != nil {
 			return "", g.tui.RecordIfError("Checked out successfully, but failed to determine branch name after, this may have side-affects on further processes!", err)
@@ -40,7 +42,7 @@ func (g *Git) Checkout(dto CheckoutDTO) (string, error) {
 		return branch, nil
 	}
 
-	branches, err := g.searchBranches(SearchBranchesDTO{
+	branches, err := g.searchBranches(ctx, SearchBranchesDTO{
 		Search: dto.Name,
 	})
 
@@ -63,7 +65,7 @@ func (g *Git) Checkout(dto CheckoutDTO) (string, error) {
 	}
 
 	if branchesLength == 1 {
-		err := g.performCheckout(branches[0].Name)
+		err := g.performCheckout(ctx, branches[0].Name)
 
 		return branches[0].Name, g.tui.RecordIfError("Failed to checkout branch", err)
 	}
@@ -78,7 +80,7 @@ func (g *Git) Checkout(dto CheckoutDTO) (string, error) {
 		return "", g.tui.RecordIfError("Something went wrong, this is most likely a bug!", err)
 	}
 
-	err = g.performCheckout(chosen)
+	err = g.performCheckout(ctx, chosen)
 
 	return chosen, g.tui.RecordIfError("Failed to checkout branch", err)
 }
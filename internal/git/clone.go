@@ -1,24 +1,36 @@
 package git
 
 import (
+	"context"
+	"strconv"
+
 	"github.com/panoptescloud/orca/internal/common"
-	"github.com/panoptescloud/orca/internal/hostsys"
 )
 
-func (g *Git) Clone(repoURL string, target string) error {
+// Clone clones the repository into target. The output is captured rather than
+// shown, so that many repositories can be cloned at once; if it fails the
+// error will contain what git reported.
+func (g *Git) Clone(ctx context.Context, repoURL string, target string, opts common.CloneOptions) error {
 	if repoURL == "" || target == "" {
 		return common.ErrInvalidInput{
 			To:  "git.clone",
 			Msg: "'repoURL' and 'target' cannot be empty",
 		}
 	}
-	return g.exec.Exec(
-		"git",
-		[]string{
-			"clone",
-			repoURL,
-			target,
-		},
-		hostsys.WithHostIO(),
-	)
+
+	args := []string{"clone"}
+
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+
+	if opts.Filter != "" {
+		args = append(args, "--filter", opts.Filter)
+	}
+
+	args = append(args, "--", repoURL, target)
+
+	_, err := g.execIn(ctx, "", args...)
+
+	return err
 }
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"strings"
 
 	"github.com/panoptescloud/orca/internal/hostsys"
@@ -11,13 +12,13 @@ type LoglDTO struct {
 	Amount int
 }
 
-func (g *Git) Logl(dto LoglDTO) error {
-	if err := g.mustBeInAGitRepository(); err != nil {
+func (g *Git) Logl(ctx context.Context, dto LoglDTO) error {
+	if err := g.mustBeInAGitRepository(ctx); err != nil {
 		return err
 	}
 
 	opt, stdout := hostsys.WithStdout()
-	err := g.exec.Exec("git", []string{
+	err := g.exec.Exec(ctx, "git", []string{
 		"log",
 		"--oneline",
 	}, opt)
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"log/slog"
 	"path/filepath"
 	"strings"
@@ -22,7 +23,7 @@ type tui interface {
 }
 
 type executor interface {
-	Exec(cmdName string, args []string, opts ...hostsys.ExecOpt) error
+	Exec(ctx context.Context, cmdName string, args []string, opts ...hostsys.ExecOpt) error
 }
 
 type Git struct {
@@ -30,8 +31,8 @@ type Git struct {
 	tui  tui
 }
 
-func (self *Git) mustBeInAGitRepository() error {
-	if self.isInGitRepository() {
+func (self *Git) mustBeInAGitRepository(ctx context.Context) error {
+	if self.isInGitRepository(ctx) {
 		return nil
 	}
 
@@ -44,19 +45,19 @@ func (self *Git) mustBeInAGitRepository() error {
 
 }
 
-func (self *Git) isInGitRepository() bool {
-	err := self.exec.Exec("git", []string{"rev-parse", "--is-inside-work-tree"})
+func (self *Git) isInGitRepository(ctx context.Context) bool {
+	err := self.exec.Exec(ctx, "git", []string{"rev-parse", "--is-inside-work-tree"})
 
 	return err == nil
 }
 
-func (self *Git) GetRepositoryRootFromPath(path string) (string, error) {
+func (self *Git) GetRepositoryRootFromPath(ctx context.Context, path string) (string, error) {
 	stdoutOpt, stdout := hostsys.WithStdout()
 	stderrOpt, stderr := hostsys.WithStderr()
 
 	dir := filepath.Dir(path)
 
-	err := self.exec.Exec("git", []string{
+	err := self.exec.Exec(ctx, "git", []string{
 		"rev-parse",
 		"--show-toplevel",
 	}, hostsys.ChdirOpt(dir), stdoutOpt, stderrOpt)
@@ -1,17 +1,21 @@
 package git
 
-import "github.com/panoptescloud/orca/internal/hostsys"
+import (
+	"context"
+
+	"github.com/panoptescloud/orca/internal/hostsys"
+)
 
 type PushDTO struct {
 	Force bool
 }
 
-func (g *Git) Push(dto PushDTO) error {
-	if err := g.mustBeInAGitRepository(); err != nil {
+func (g *Git) Push(ctx context.Context, dto PushDTO) error {
+	if err := g.mustBeInAGitRepository(ctx); err != nil {
 		return err
 	}
 
-	branch, err := g.GetCurrentBranch()
+	branch, err := g.GetCurrentBranch(ctx)
 
 	if err != nil {
 		return g.tui.RecordIfError("Failed to determine current branch, and no branch was supplied!", err)
@@ -27,5 +31,5 @@ func (g *Git) Push(dto PushDTO) error {
 
 	args = append(args, "origin", branch)
 
-	return g.exec.Exec("git", args, hostsys.WithHostIO())
+	return g.exec.Exec(ctx, "git", args, hostsys.WithHostIO())
 }
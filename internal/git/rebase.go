@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/panoptescloud/orca/internal/hostsys"
@@ -11,12 +12,12 @@ type RebaseInteractivelyDTO struct {
 	Amount int
 }
 
-func (g *Git) RebaseInteractively(dto RebaseInteractivelyDTO) error {
-	if err := g.mustBeInAGitRepository(); err != nil {
+func (g *Git) RebaseInteractively(ctx context.Context, dto RebaseInteractivelyDTO) error {
+	if err := g.mustBeInAGitRepository(ctx); err != nil {
 		return err
 	}
 
-	return g.exec.Exec("git", []string{
+	return g.exec.Exec(ctx, "git", []string{
 		"rebase",
 		"-i",
 		fmt.Sprintf("HEAD~%d", dto.Amount),
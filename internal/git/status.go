@@ -0,0 +1,130 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/hostsys"
+)
+
+// parseStatusPorcelainV2 parses the output of
+// 'git status --porcelain=v2 --branch'.
+func parseStatusPorcelainV2(output string) common.RepoStatus {
+	status := common.RepoStatus{}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		header, isHeader := strings.CutPrefix(line, "# ")
+
+		if !isHeader {
+			status.Dirty = true
+			continue
+		}
+
+		key, value, _ := strings.Cut(header, " ")
+
+		switch key {
+		case "branch.head":
+			status.Branch = value
+		case "branch.upstream":
+			status.HasUpstream = true
+		case "branch.ab":
+			ahead, behind, _ := strings.Cut(value, " ")
+			status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+			status.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+		}
+	}
+
+	return status
+}
+
+// execIn runs git within the given directory, capturing the output rather than
+// passing it through to the terminal. This allows it to be used for many
+// repositories at once.
+func (g *Git) execIn(ctx context.Context, dir string, args ...string) (string, error) {
+	stdoutOpt, stdout := hostsys.WithStdout()
+	stderrOpt, stderr := hostsys.WithStderr()
+
+	err := g.exec.Exec(ctx, "git", args, hostsys.ChdirOpt(dir), stdoutOpt, stderrOpt)
+
+	if err != nil {
+		errOutput := strings.TrimSpace(stderr.String())
+		slog.Debug("git command failed", "in", dir, "args", args, "err", err, "stderr", errOutput)
+
+		if errOutput == "" {
+			errOutput = err.Error()
+		}
+
+		return "", common.ErrCommandExecutionFailed{
+			Msg: errOutput,
+		}
+	}
+
+	return stdout.String(), nil
+}
+
+// StatusIn returns the status of the repository in dir. Untracked files are
+// ignored, as they're rarely a reason to avoid switching branches or pulling.
+func (g *Git) StatusIn(ctx context.Context, dir string) (common.RepoStatus, error) {
+	out, err := g.execIn(ctx, dir, "status", "--porcelain=v2", "--branch", "--untracked-files=no")
+
+	if err != nil {
+		return common.RepoStatus{}, err
+	}
+
+	return parseStatusPorcelainV2(out), nil
+}
+
+// PullIn fast-forwards the current branch of the repository in dir from its
+// upstream.
+func (g *Git) PullIn(ctx context.Context, dir string) error {
+	_, err := g.execIn(ctx, dir, "pull", "--ff-only")
+
+	return err
+}
+
+func (g *Git) branchExistsIn(ctx context.Context, dir string, branch string) bool {
+	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/origin/" + branch} {
+		if _, err := g.execIn(ctx, dir, "rev-parse", "--verify", "--quiet", ref); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckoutIn checks out the branch in the repository in dir, if it exists
+// either locally or on origin. False is returned when it doesn't exist.
+func (g *Git) CheckoutIn(ctx context.Context, dir string, branch string) (bool, error) {
+	if branch == "" || strings.HasPrefix(branch, "-") {
+		return false, common.ErrInvalidInput{
+			To:  "git.checkout",
+			Msg: fmt.Sprintf("'%s' is not a valid branch name", branch),
+		}
+	}
+
+	if !g.branchExistsIn(ctx, dir, branch) {
+		return false, nil
+	}
+
+	_, err := g.execIn(ctx, dir, "checkout", branch)
+
+	return err == nil, err
+}
+
+func (g *Git) Status(ctx context.Context) error {
+	if err := g.mustBeInAGitRepository(ctx); err != nil {
+		return err
+	}
+
+	err := g.exec.Exec(ctx, "git", []string{"status"}, hostsys.WithHostIO())
+
+	return g.tui.RecordIfError("Failed to get status!", err)
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseStatusPorcelainV2(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		expect common.RepoStatus
+	}{
+		{
+			name: "clean and up to date",
+			in: `# branch.oid 1a2b3c
+# branch.head main
+# branch.upstream origin/main
+# branch.ab +0 -0
+`,
+			expect: common.RepoStatus{
+				Branch:      "main",
+				HasUpstream: true,
+			},
+		},
+		{
+			name: "ahead, behind and dirty",
+			in: `# branch.oid 1a2b3c
+# branch.head feature/x
+# branch.upstream origin/feature/x
+# branch.ab +2 -5
+1 .M N... 100644 100644 100644 1a2b3c 1a2b3c main.go
+`,
+			expect: common.RepoStatus{
+				Branch:      "feature/x",
+				HasUpstream: true,
+				Ahead:       2,
+				Behind:      5,
+				Dirty:       true,
+			},
+		},
+		{
+			name: "no upstream",
+			in: `# branch.oid 1a2b3c
+# branch.head local-only
+`,
+			expect: common.RepoStatus{
+				Branch: "local-only",
+			},
+		},
+		{
+			name: "detached",
+			in: `# branch.oid 1a2b3c
+# branch.head (detached)
+`,
+			expect: common.RepoStatus{
+				Branch: "(detached)",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert.Equal(tt, test.expect, parseStatusPorcelainV2(test.in))
+		})
+	}
+}
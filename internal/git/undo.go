@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/panoptescloud/orca/internal/common"
@@ -13,8 +14,8 @@ type UndoLastXCommitsDTO struct {
 	SkipConfirmation bool
 }
 
-func (g *Git) UndoLastXCommits(dto UndoLastXCommitsDTO) error {
-	if err := g.mustBeInAGitRepository(); err != nil {
+func (g *Git) UndoLastXCommits(ctx context.Context, dto UndoLastXCommitsDTO) error {
+	if err := g.mustBeInAGitRepository(ctx); err != nil {
 		return err
 	}
 
@@ -38,7 +39,7 @@ func (g *Git) UndoLastXCommits(dto UndoLastXCommitsDTO) error {
 		}
 	}
 
-	return g.exec.Exec("git", []string{
+	return g.exec.Exec(ctx, "git", []string{
 		"reset",
 		"--hard",
 		fmt.Sprintf("HEAD~%d", dto.Amount),
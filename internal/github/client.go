@@ -14,8 +14,8 @@ type GithubClient struct {
 	client *github.Client
 }
 
-func (self *GithubClient) LatestVersion(owner string, repo string) (*semver.Version, error) {
-	rel, resp, err := self.client.Repositories.GetLatestRelease(context.TODO(), owner, repo)
+func (self *GithubClient) LatestVersion(ctx context.Context, owner string, repo string) (*semver.Version, error) {
+	rel, resp, err := self.client.Repositories.GetLatestRelease(ctx, owner, repo)
 
 	if err != nil {
 		return nil, common.ErrUnexpectedApiError{
@@ -37,8 +37,8 @@ func (self *GithubClient) LatestVersion(owner string, repo string) (*semver.Vers
 	}
 }
 
-func (self *GithubClient) VersionExists(owner string, repo string, v string) (bool, error) {
-	_, resp, err := self.client.Repositories.GetReleaseByTag(context.TODO(), owner, repo, v)
+func (self *GithubClient) VersionExists(ctx context.Context, owner string, repo string, v string) (bool, error) {
+	_, resp, err := self.client.Repositories.GetReleaseByTag(ctx, owner, repo, v)
 
 	if err != nil {
 		return false, common.ErrUnexpectedApiError{
@@ -0,0 +1,155 @@
+package hostsys
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// writeFlags are the flags which mean a file is being opened to be changed.
+const writeFlags = os.O_WRONLY | os.O_RDWR | os.O_CREATE | os.O_TRUNC | os.O_APPEND
+
+// dryRunFs reports every change to the filesystem instead of making it. The
+// changes are kept in memory on top of the real filesystem (copy-on-write),
+// so anything written can still be read back later in the same run, e.g. the
+// overlays used by compose commands.
+type dryRunFs struct {
+	afero.Fs
+
+	out io.Writer
+
+	mu       sync.Mutex
+	reported map[string]bool
+}
+
+// NewDryRunFs wraps base so that nothing is written to it, with each change
+// that would have been made printed to out.
+func NewDryRunFs(base afero.Fs, out io.Writer) afero.Fs {
+	return &dryRunFs{
+		Fs:       afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(base), afero.NewMemMapFs()),
+		out:      out,
+		reported: map[string]bool{},
+	}
+}
+
+// report prints the change, only once for each path, as files are often
+// written to more than once.
+func (fs *dryRunFs) report(op string, path string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	key := op + " " + path
+
+	if fs.reported[key] {
+		return
+	}
+
+	fs.reported[key] = true
+
+	fmt.Fprintf(fs.out, "%s %s: %s\n", dryRunPrefix, op, path)
+}
+
+func (fs *dryRunFs) Create(name string) (afero.File, error) {
+	fs.report("write", name)
+
+	return fs.Fs.Create(name)
+}
+
+func (fs *dryRunFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&writeFlags != 0 {
+		fs.report("write", name)
+	}
+
+	return fs.Fs.OpenFile(name, flag, perm)
+}
+
+func (fs *dryRunFs) Mkdir(name string, perm os.FileMode) error {
+	if exists, _ := afero.DirExists(fs.Fs, name); !exists {
+		fs.report("mkdir", name)
+	}
+
+	return fs.Fs.Mkdir(name, perm)
+}
+
+func (fs *dryRunFs) MkdirAll(path string, perm os.FileMode) error {
+	if exists, _ := afero.DirExists(fs.Fs, path); !exists {
+		fs.report("mkdir", path)
+	}
+
+	return fs.Fs.MkdirAll(path, perm)
+}
+
+// ignoreOnDisk hides the error from removing or renaming something that only
+// exists on disk, as the copy-on-write layer can't change it. It stays visible
+// for the rest of the run.
+func (fs *dryRunFs) ignoreOnDisk(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, statErr := fs.Fs.Stat(name); statErr == nil {
+		return nil
+	}
+
+	return err
+}
+
+func (fs *dryRunFs) Remove(name string) error {
+	if err := fs.ignoreOnDisk(name, fs.Fs.Remove(name)); err != nil {
+		return err
+	}
+
+	fs.report("remove", name)
+
+	return nil
+}
+
+func (fs *dryRunFs) RemoveAll(path string) error {
+	exists, _ := afero.Exists(fs.Fs, path)
+
+	if err := fs.ignoreOnDisk(path, fs.Fs.RemoveAll(path)); err != nil {
+		return err
+	}
+
+	if exists {
+		fs.report("remove", path)
+	}
+
+	return nil
+}
+
+func (fs *dryRunFs) Rename(oldname string, newname string) error {
+	if err := fs.ignoreOnDisk(oldname, fs.Fs.Rename(oldname, newname)); err != nil {
+		return err
+	}
+
+	fs.report("rename", fmt.Sprintf("%s -> %s", oldname, newname))
+
+	return nil
+}
+
+func (fs *dryRunFs) Chmod(name string, mode os.FileMode) error {
+	fs.report("chmod", name)
+
+	return fs.Fs.Chmod(name, mode)
+}
+
+func (fs *dryRunFs) Chown(name string, uid int, gid int) error {
+	fs.report("chown", name)
+
+	return fs.Fs.Chown(name, uid, gid)
+}
+
+func (fs *dryRunFs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	fs.report("touch", name)
+
+	return fs.Fs.Chtimes(name, atime, mtime)
+}
+
+func (fs *dryRunFs) Name() string {
+	return "DryRunFs"
+}
@@ -0,0 +1,51 @@
+package hostsys_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DryRunFs(t *testing.T) {
+	base := afero.NewMemMapFs()
+	require.Nil(t, afero.WriteFile(base, "/orca/orca.yaml", []byte("original"), 0644))
+	require.Nil(t, afero.WriteFile(base, "/orca/old.yaml", []byte("old"), 0644))
+
+	out := &bytes.Buffer{}
+	fs := hostsys.NewDryRunFs(base, out)
+
+	require.Nil(t, fs.MkdirAll("/orca", 0755))
+	require.Nil(t, fs.MkdirAll("/orca/overlays/test", 0755))
+	require.Nil(t, afero.WriteFile(fs, "/orca/overlays/test/api.yaml", []byte("overlay"), 0644))
+	require.Nil(t, afero.WriteFile(fs, "/orca/overlays/test/api.yaml", []byte("overlay"), 0644))
+	require.Nil(t, afero.WriteFile(fs, "/orca/orca.yaml", []byte("changed"), 0644))
+	require.Nil(t, fs.Remove("/orca/old.yaml"))
+
+	assert.Equal(t, `[dry-run] mkdir: /orca/overlays/test
+[dry-run] write: /orca/overlays/test/api.yaml
+[dry-run] write: /orca/orca.yaml
+[dry-run] remove: /orca/old.yaml
+`, out.String())
+
+	// What was written can be read back within the same run...
+	contents, err := afero.ReadFile(fs, "/orca/overlays/test/api.yaml")
+	require.Nil(t, err)
+	assert.Equal(t, "overlay", string(contents))
+
+	// ...but nothing actually changed
+	contents, err = afero.ReadFile(base, "/orca/orca.yaml")
+	require.Nil(t, err)
+	assert.Equal(t, "original", string(contents))
+
+	exists, err := afero.Exists(base, "/orca/overlays")
+	require.Nil(t, err)
+	assert.False(t, exists)
+
+	exists, err = afero.Exists(base, "/orca/old.yaml")
+	require.Nil(t, err)
+	assert.True(t, exists)
+}
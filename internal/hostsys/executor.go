@@ -2,11 +2,30 @@ package hostsys
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"io"
 	"os"
 	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+const (
+	// interruptGracePeriod is how long a command has to exit by itself, after
+	// Ctrl-C has been pressed, before it's asked to stop.
+	interruptGracePeriod = 10 * time.Second
+
+	// terminateGracePeriod is how long a command has to exit after it's been
+	// asked to stop, before it's killed.
+	terminateGracePeriod = 5 * time.Second
 )
 
 type Executor struct {
+	interruptGracePeriod time.Duration
+	terminateGracePeriod time.Duration
 }
 
 type ExecOpt func(*exec.Cmd) error
@@ -49,8 +68,77 @@ func WithStderr() (ExecOpt, *bytes.Buffer) {
 	}, ptr
 }
 
-func (e *Executor) Exec(cmdName string, args []string, opts ...ExecOpt) (err error) {
-	cmd := exec.Command(cmdName, args...)
+// WithStdoutTo sends stdout to w, e.g. to stream it into a file.
+func WithStdoutTo(w io.Writer) ExecOpt {
+	return func(cmd *exec.Cmd) error {
+		cmd.Stdout = w
+
+		return nil
+	}
+}
+
+// WithStdinFrom reads stdin from r, rather than the terminal.
+func WithStdinFrom(r io.Reader) ExecOpt {
+	return func(cmd *exec.Cmd) error {
+		cmd.Stdin = r
+
+		return nil
+	}
+}
+
+// WithOutputTo sends both stdout and stderr to w, e.g. so they can be streamed
+// somewhere other than the terminal.
+func WithOutputTo(w io.Writer) ExecOpt {
+	return func(cmd *exec.Cmd) error {
+		cmd.Stdout = w
+		cmd.Stderr = w
+
+		return nil
+	}
+}
+
+// interruptedByTerminal reports whether the context was cancelled because
+// Ctrl-C was pressed. The terminal sends SIGINT to every process in the
+// foreground, so the command will have been interrupted already.
+func interruptedByTerminal(ctx context.Context) bool {
+	var sig common.ErrReceivedSignal
+
+	return errors.As(context.Cause(ctx), &sig) && sig.Signal == os.Interrupt
+}
+
+// terminate asks the process to stop. Signals other than kill aren't supported
+// on windows, where the only option is to kill it.
+func terminate(p *os.Process) {
+	if err := p.Signal(syscall.SIGTERM); err != nil {
+		p.Kill()
+	}
+}
+
+// Exec runs the command until it finishes. If the context is cancelled first,
+// the command is sent SIGTERM so it can clean up, and killed if it still
+// hasn't exited once the grace periods are up. When Ctrl-C was pressed the
+// command has already been interrupted by the terminal, so it's left to stop
+// by itself for interruptGracePeriod first. It isn't interrupted again, as
+// compose treats a second interrupt as a request to kill everything.
+func (e *Executor) Exec(ctx context.Context, cmdName string, args []string, opts ...ExecOpt) (err error) {
+	var timer *time.Timer
+
+	cmd := exec.CommandContext(ctx, cmdName, args...)
+	cmd.Cancel = func() error {
+		delay := time.Duration(0)
+
+		if interruptedByTerminal(ctx) {
+			delay = e.interruptGracePeriod
+		}
+
+		process := cmd.Process
+		timer = time.AfterFunc(delay, func() {
+			terminate(process)
+		})
+
+		return nil
+	}
+	cmd.WaitDelay = e.interruptGracePeriod + e.terminateGracePeriod
 
 	for _, opt := range opts {
 		opt(cmd)
@@ -58,9 +146,33 @@ func (e *Executor) Exec(cmdName string, args []string, opts ...ExecOpt) (err err
 
 	err = cmd.Run()
 
+	if timer != nil {
+		timer.Stop()
+	}
+
 	return err
 }
 
+// StartDetached starts the command in the background, in its own session so
+// it keeps running after orca exits. It isn't waited for.
+func (e *Executor) StartDetached(cmdName string, args []string, opts ...ExecOpt) error {
+	cmd := exec.Command(cmdName, args...)
+	detach(cmd)
+
+	for _, opt := range opts {
+		opt(cmd)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	return cmd.Process.Release()
+}
+
 func NewExecutor() *Executor {
-	return &Executor{}
+	return &Executor{
+		interruptGracePeriod: interruptGracePeriod,
+		terminateGracePeriod: terminateGracePeriod,
+	}
 }
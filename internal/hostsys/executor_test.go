@@ -0,0 +1,83 @@
+package hostsys
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Executor_Exec_Cancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals aren't supported on windows")
+	}
+
+	tests := []struct {
+		name   string
+		cause  error
+		script string
+
+		expectOutput string
+		minElapsed   time.Duration
+		maxElapsed   time.Duration
+	}{
+		{
+			name:         "terminated straight away",
+			cause:        context.Canceled,
+			script:       `trap 'echo terminated; exit 0' TERM; while true; do sleep 0.05; done`,
+			expectOutput: "terminated\n",
+			maxElapsed:   400 * time.Millisecond,
+		},
+		{
+			name:         "left to stop after ctrl-c",
+			cause:        common.ErrReceivedSignal{Signal: os.Interrupt},
+			script:       `trap 'echo terminated; exit 0' TERM; while true; do sleep 0.05; done`,
+			expectOutput: "terminated\n",
+			minElapsed:   500 * time.Millisecond,
+			maxElapsed:   900 * time.Millisecond,
+		},
+		{
+			name:       "killed when it won't stop",
+			cause:      context.Canceled,
+			script:     `trap '' TERM; while true; do sleep 0.05; done`,
+			minElapsed: 900 * time.Millisecond,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			e := &Executor{
+				interruptGracePeriod: 500 * time.Millisecond,
+				terminateGracePeriod: 500 * time.Millisecond,
+			}
+
+			ctx, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+
+			// Gives the script time to set up its trap
+			time.AfterFunc(100*time.Millisecond, func() {
+				cancel(test.cause)
+			})
+
+			opt, stdout := WithStdout()
+			start := time.Now()
+
+			err := e.Exec(ctx, "sh", []string{"-c", test.script}, opt)
+			elapsed := time.Since(start) - 100*time.Millisecond
+
+			// Even when it exits cleanly, it didn't get to finish
+			assert.NotNil(tt, err)
+
+			assert.Equal(tt, test.expectOutput, stdout.String())
+			assert.GreaterOrEqual(tt, elapsed, test.minElapsed)
+
+			if test.maxElapsed > 0 {
+				assert.Less(tt, elapsed, test.maxElapsed)
+			}
+		})
+	}
+}
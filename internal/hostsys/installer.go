@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -81,8 +82,8 @@ func (self *HostSystem) extractExecutableFromArchive(archive []byte, path string
 	}
 }
 
-func (self *HostSystem) getExecutableFromArchiveUrl(url string, executableName string) ([]byte, error) {
-	archive, err := self.getFileFromUrl(url)
+func (self *HostSystem) getExecutableFromArchiveUrl(ctx context.Context, url string, executableName string) ([]byte, error) {
+	archive, err := self.getFileFromUrl(ctx, url)
 
 	executable, err := self.extractExecutableFromArchive(archive, executableName)
 
@@ -93,9 +94,15 @@ func (self *HostSystem) getExecutableFromArchiveUrl(url string, executableName s
 	return io.ReadAll(executable)
 }
 
-func (self *HostSystem) getFileFromUrl(url string) ([]byte, error) {
+func (self *HostSystem) getFileFromUrl(ctx context.Context, url string) ([]byte, error) {
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 
 	if err != nil {
 		return nil, err
@@ -142,7 +149,7 @@ func (self *HostSystem) getHostctlArchForDownload() (string, error) {
 	}
 }
 
-func (self *HostSystem) installHostCtl() error {
+func (self *HostSystem) installHostCtl(ctx context.Context) error {
 	os, err := self.getHostctlOSForDownload()
 
 	if err != nil {
@@ -163,7 +170,7 @@ func (self *HostSystem) installHostCtl() error {
 		arch,
 	)
 
-	contents, err := self.getExecutableFromArchiveUrl(url, "hostctl")
+	contents, err := self.getExecutableFromArchiveUrl(ctx, url, "hostctl")
 
 	if err != nil {
 		return err
@@ -216,7 +223,7 @@ func (self *HostSystem) getSopsArchForDownload() (string, error) {
 	}
 }
 
-func (self *HostSystem) installSops() error {
+func (self *HostSystem) installSops(ctx context.Context) error {
 	os, err := self.getSopsOSForDownload()
 
 	if err != nil {
@@ -237,7 +244,7 @@ func (self *HostSystem) installSops() error {
 		arch,
 	)
 
-	contents, err := self.getFileFromUrl(url)
+	contents, err := self.getFileFromUrl(ctx, url)
 
 	if err != nil {
 		return err
@@ -268,7 +275,7 @@ type InstallDTO struct {
 	Tool AvailableTool
 }
 
-func (self *HostSystem) Install(dto InstallDTO) error {
+func (self *HostSystem) Install(ctx context.Context, dto InstallDTO) error {
 	if !dto.Tool.IsKnown() {
 		return self.tui.RecordIfError(fmt.Sprintf("Unknown tool: %s", string(dto.Tool)), common.ErrUnknownTool{
 			Tool: string(dto.Tool),
@@ -278,9 +285,9 @@ func (self *HostSystem) Install(dto InstallDTO) error {
 	var err error
 	switch dto.Tool {
 	case ToolHostCtl:
-		err = self.installHostCtl()
+		err = self.installHostCtl(ctx)
 	case ToolSops:
-		err = self.installSops()
+		err = self.installSops(ctx)
 	default:
 		return self.tui.RecordIfError("This tool is not supported!", common.ErrNotImplemented{
 			Msg: "installation has not been implemented for this tool",
@@ -1,6 +1,8 @@
 package hostsys
 
 import (
+	"context"
+
 	"github.com/Masterminds/semver/v3"
 	"github.com/spf13/afero"
 )
@@ -18,8 +20,8 @@ type tui interface {
 }
 
 type githubClient interface {
-	VersionExists(owner string, repo string, v string) (bool, error)
-	LatestVersion(owner string, repo string) (*semver.Version, error)
+	VersionExists(ctx context.Context, owner string, repo string, v string) (bool, error)
+	LatestVersion(ctx context.Context, owner string, repo string) (*semver.Version, error)
 }
 
 type HostSystem struct {
@@ -27,13 +29,17 @@ type HostSystem struct {
 	fs       afero.Fs
 	toolsDir string
 	github   githubClient
+
+	// composeProvider is the one chosen in the user config, if any.
+	composeProvider string
 }
 
-func NewHostSystem(tui tui, fs afero.Fs, github githubClient, toolsDir string) *HostSystem {
+func NewHostSystem(tui tui, fs afero.Fs, github githubClient, toolsDir string, composeProvider string) *HostSystem {
 	return &HostSystem{
-		tui:      tui,
-		fs:       fs,
-		github:   github,
-		toolsDir: toolsDir,
+		tui:             tui,
+		fs:              fs,
+		github:          github,
+		toolsDir:        toolsDir,
+		composeProvider: composeProvider,
 	}
 }
@@ -0,0 +1,113 @@
+package hostsys
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// dryRunPrefix marks everything that would have happened, so it stands out
+// from orca's usual output.
+const dryRunPrefix = "[dry-run]"
+
+// RecordingExecutor is used in place of the Executor for --dry-run. Each
+// command is printed, along with the directory it would run in and any
+// environment it would be given, instead of being run.
+//
+// As nothing is run, any output that would normally be captured is empty,
+// e.g. when checking which containers are running.
+type RecordingExecutor struct {
+	out io.Writer
+}
+
+func (e *RecordingExecutor) record(cmdName string, args []string, opts []ExecOpt) error {
+	// Only used so the options can be applied, it's never started
+	cmd := exec.Command(cmdName, args...)
+
+	for _, opt := range opts {
+		if err := opt(cmd); err != nil {
+			return err
+		}
+	}
+
+	dir := cmd.Dir
+
+	if dir == "" {
+		wd, err := os.Getwd()
+
+		if err != nil {
+			return err
+		}
+
+		dir = wd
+	}
+
+	parts := []string{"cd", shellQuote(dir), "&&"}
+
+	// Unless it's been changed, the environment is inherited from orca, so
+	// only the additions are interesting.
+	if cmd.Env != nil {
+		inherited := os.Environ()
+
+		for _, env := range cmd.Env {
+			if !slices.Contains(inherited, env) {
+				parts = append(parts, shellQuote(env))
+			}
+		}
+	}
+
+	parts = append(parts, shellQuote(cmdName))
+
+	for _, arg := range args {
+		parts = append(parts, shellQuote(arg))
+	}
+
+	_, err := fmt.Fprintf(e.out, "%s run: %s\n", dryRunPrefix, strings.Join(parts, " "))
+
+	return err
+}
+
+// Exec prints the command instead of running it.
+func (e *RecordingExecutor) Exec(_ context.Context, cmdName string, args []string, opts ...ExecOpt) error {
+	return e.record(cmdName, args, opts)
+}
+
+// StartDetached prints the command instead of starting it.
+func (e *RecordingExecutor) StartDetached(cmdName string, args []string, opts ...ExecOpt) error {
+	return e.record(cmdName, args, opts)
+}
+
+// shellQuote quotes s if it contains anything the shell would interpret, so
+// the printed commands can be copied and run.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	safe := strings.IndexFunc(s, func(r rune) bool {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return false
+		case strings.ContainsRune("-_./:=,@%+", r):
+			return false
+		}
+
+		return true
+	}) == -1
+
+	if safe {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func NewRecordingExecutor(out io.Writer) *RecordingExecutor {
+	return &RecordingExecutor{
+		out: out,
+	}
+}
@@ -0,0 +1,61 @@
+package hostsys_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RecordingExecutor_Exec(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      string
+		args     []string
+		opts     []hostsys.ExecOpt
+		expected string
+	}{
+		{
+			name:     "in a directory",
+			cmd:      "docker",
+			args:     []string{"compose", "-p", "orca-test-api", "up", "-d"},
+			opts:     []hostsys.ExecOpt{hostsys.ChdirOpt("/src/api")},
+			expected: "[dry-run] run: cd /src/api && docker compose -p orca-test-api up -d\n",
+		},
+		{
+			name:     "quotes arguments for the shell",
+			cmd:      "sh",
+			args:     []string{"-c", "echo 'hi there'", ""},
+			opts:     []hostsys.ExecOpt{hostsys.ChdirOpt("/my projects/api")},
+			expected: "[dry-run] run: cd '/my projects/api' && sh -c 'echo '\\''hi there'\\''' ''\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			out := &bytes.Buffer{}
+			e := hostsys.NewRecordingExecutor(out)
+
+			err := e.Exec(context.Background(), test.cmd, test.args, test.opts...)
+			require.Nil(tt, err)
+
+			assert.Equal(tt, test.expected, out.String())
+		})
+	}
+}
+
+func Test_RecordingExecutor_DoesNotRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	e := hostsys.NewRecordingExecutor(out)
+
+	withStdout, stdout := hostsys.WithStdout()
+
+	err := e.Exec(context.Background(), "echo", []string{"hello"}, withStdout, hostsys.ChdirOpt("/"))
+	require.Nil(t, err)
+
+	assert.Empty(t, stdout.String())
+	assert.Equal(t, "[dry-run] run: cd / && echo hello\n", out.String())
+}
@@ -2,6 +2,7 @@ package hostsys
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/Masterminds/semver/v3"
@@ -14,7 +15,7 @@ type VersioningStrategy string
 const VersioningStrategyLatest VersioningStrategy = "latest"
 const VersioningStrategySpecific VersioningStrategy = "specific"
 
-func (self *HostSystem) replaceSelf(version string) error {
+func (self *HostSystem) replaceSelf(ctx context.Context, version string) error {
 	os, err := self.getOrcaOSForDownload()
 
 	if err != nil {
@@ -36,7 +37,7 @@ func (self *HostSystem) replaceSelf(version string) error {
 		arch,
 	)
 
-	contents, err := self.getExecutableFromArchiveUrl(url, "orca")
+	contents, err := self.getExecutableFromArchiveUrl(ctx, url, "orca")
 
 	if err != nil {
 		return err
@@ -47,8 +48,8 @@ func (self *HostSystem) replaceSelf(version string) error {
 	return selfupdate.Apply(reader, selfupdate.Options{})
 }
 
-func (s *HostSystem) ensureSelfVersionExists(v *semver.Version) error {
-	exists, err := s.github.VersionExists(orcaRepoOrg, orcaRepoName, fmt.Sprintf("v%s", v.String()))
+func (s *HostSystem) ensureSelfVersionExists(ctx context.Context, v *semver.Version) error {
+	exists, err := s.github.VersionExists(ctx, orcaRepoOrg, orcaRepoName, fmt.Sprintf("v%s", v.String()))
 
 	if err != nil {
 		return err
@@ -63,7 +64,7 @@ func (s *HostSystem) ensureSelfVersionExists(v *semver.Version) error {
 	return nil
 }
 
-func (s *HostSystem) getVersionForSelf(dto UpdateSelfDTO) (*semver.Version, error) {
+func (s *HostSystem) getVersionForSelf(ctx context.Context, dto UpdateSelfDTO) (*semver.Version, error) {
 	if dto.Strategy == VersioningStrategySpecific {
 		v, err := semver.NewVersion(dto.SpecifiedVersion)
 
@@ -71,14 +72,14 @@ func (s *HostSystem) getVersionForSelf(dto UpdateSelfDTO) (*semver.Version, erro
 			return nil, err
 		}
 
-		if err := s.ensureSelfVersionExists(v); err != nil {
+		if err := s.ensureSelfVersionExists(ctx, v); err != nil {
 			return nil, err
 		}
 
 		return v, nil
 	}
 
-	return s.github.LatestVersion(orcaRepoOrg, orcaRepoName)
+	return s.github.LatestVersion(ctx, orcaRepoOrg, orcaRepoName)
 }
 
 type UpdateSelfDTO struct {
@@ -86,8 +87,8 @@ type UpdateSelfDTO struct {
 	SpecifiedVersion string
 }
 
-func (self *HostSystem) UpdateSelf(dto UpdateSelfDTO) error {
-	v, err := self.getVersionForSelf(dto)
+func (self *HostSystem) UpdateSelf(ctx context.Context, dto UpdateSelfDTO) error {
+	v, err := self.getVersionForSelf(ctx, dto)
 
 	if err != nil {
 		return self.tui.RecordIfError("Failed to find version!", err)
@@ -95,7 +96,7 @@ func (self *HostSystem) UpdateSelf(dto UpdateSelfDTO) error {
 
 	self.tui.Info(fmt.Sprintf("Switching to version: %s", v.String()))
 
-	err = self.replaceSelf(v.String())
+	err = self.replaceSelf(ctx, v.String())
 
 	if err != nil {
 		return self.tui.RecordIfError(
@@ -0,0 +1,17 @@
+package hostsys
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// IsInteractive is true when orca is attached to a terminal for both its input
+// and output, rather than being piped to or from, or run in CI.
+func IsInteractive() bool {
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
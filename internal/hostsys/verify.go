@@ -3,6 +3,7 @@ package hostsys
 import (
 	"fmt"
 	"os/exec"
+	"slices"
 	"strings"
 
 	"github.com/panoptescloud/orca/internal/common"
@@ -13,10 +14,10 @@ type Tool struct {
 	SubCmds []Tool
 }
 
-func defaultRequirements() []Tool {
+func defaultRequirements(composeProvider string) []Tool {
 	return []Tool{
 		{
-			Cmd: "docker",
+			Cmd: composeProvider,
 			SubCmds: []Tool{
 				{
 					Cmd: "compose",
@@ -128,10 +129,51 @@ func (hs *HostSystem) ensureToolExists(tool Tool, parents []Tool) error {
 	return nil
 }
 
+// DetectComposeProvider returns the first supported compose provider that's
+// in the PATH. If there are none, docker is assumed so that it's reported as
+// missing.
+func DetectComposeProvider() string {
+	for _, p := range common.ComposeProviders {
+		if _, err := exec.LookPath(p); err == nil {
+			return p
+		}
+	}
+
+	return common.ComposeProviderDocker
+}
+
+// ComposeProvider returns the compose provider that should be used, and
+// whether it was detected rather than chosen in the config.
+func (hs *HostSystem) ComposeProvider() (string, bool) {
+	if hs.composeProvider != "" {
+		return hs.composeProvider, false
+	}
+
+	return DetectComposeProvider(), true
+}
+
 func (hs *HostSystem) VerifySetup() error {
 	errs := []common.ErrToolNotFoundOnSystem{}
 
-	for _, t := range defaultRequirements() {
+	provider, detected := hs.ComposeProvider()
+
+	if !slices.Contains(common.ComposeProviders, provider) {
+		err := common.ErrUnknownComposeProvider{
+			Name: provider,
+		}
+
+		hs.tui.Error(fmt.Sprintf("The compose provider in the config is not supported, %s", err.Error()))
+
+		return err
+	}
+
+	if detected {
+		hs.tui.Info(fmt.Sprintf("No compose provider is set in the config, so '%s' is used based on what's in the PATH. Set 'compose.provider' in the config to choose another.", provider))
+	} else {
+		hs.tui.Info(fmt.Sprintf("Using '%s' for compose, as set in the config.", provider))
+	}
+
+	for _, t := range defaultRequirements(provider) {
 		err := hs.ensureToolExists(t, []Tool{})
 
 		if err != nil {
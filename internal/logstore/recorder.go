@@ -0,0 +1,131 @@
+package logstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/panoptescloud/orca/pkg/logmux"
+	"github.com/spf13/afero"
+)
+
+type recordedFile struct {
+	file afero.File
+	size int64
+}
+
+// recorder is the sink for a run, writing each line to its service's file.
+type recorder struct {
+	store *Store
+	dir   string
+	files map[string]*recordedFile
+}
+
+func (r *recorder) open(path string) (*recordedFile, error) {
+	if f, ok := r.files[path]; ok {
+		return f, nil
+	}
+
+	if err := r.store.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := r.store.fs.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	f := &recordedFile{
+		file: file,
+		size: info.Size(),
+	}
+
+	r.files[path] = f
+
+	return f, nil
+}
+
+// rotate moves the current file to .1, .1 to .2 and so on, dropping the
+// oldest once there are too many.
+func (r *recorder) rotate(path string) error {
+	if err := r.files[path].file.Close(); err != nil {
+		return err
+	}
+
+	delete(r.files, path)
+
+	fs := r.store.fs
+	backup := func(i int) string {
+		return fmt.Sprintf("%s.%d", path, i)
+	}
+
+	if err := fs.Remove(backup(r.store.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for i := r.store.maxBackups - 1; i >= 1; i-- {
+		if err := fs.Rename(backup(i), backup(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if r.store.maxBackups == 0 {
+		return fs.Remove(path)
+	}
+
+	return fs.Rename(path, backup(1))
+}
+
+func (r *recorder) WriteLine(line logmux.Line) error {
+	line.Time = r.store.now()
+
+	contents, err := json.Marshal(line)
+
+	if err != nil {
+		return err
+	}
+
+	contents = append(contents, '\n')
+	path := r.logPath(line.Prefix)
+
+	f, err := r.open(path)
+
+	if err != nil {
+		return err
+	}
+
+	if f.size > 0 && f.size+int64(len(contents)) > r.store.maxFileSize {
+		if err := r.rotate(path); err != nil {
+			return err
+		}
+
+		if f, err = r.open(path); err != nil {
+			return err
+		}
+	}
+
+	n, err := f.file.Write(contents)
+	f.size += int64(n)
+
+	return err
+}
+
+func (r *recorder) close() error {
+	for path, f := range r.files {
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+
+		delete(r.files, path)
+	}
+
+	return nil
+}
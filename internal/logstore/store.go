@@ -0,0 +1,220 @@
+package logstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/pkg/logmux"
+	"github.com/spf13/afero"
+)
+
+const (
+	// DefaultMaxFileSize is how large a service's log can get before it is
+	// rotated.
+	DefaultMaxFileSize = 10 * 1024 * 1024
+
+	// DefaultMaxBackups is how many rotated logs are kept for each service.
+	DefaultMaxBackups = 3
+
+	// LatestRun can be used in place of the id of the most recent run.
+	LatestRun = "latest"
+
+	// runIDFormat includes milliseconds, so runs started in the same second
+	// are kept apart. It's fixed width, so the ids sort in the order the runs
+	// started.
+	runIDFormat = "20060102-150405.000"
+
+	logExt = ".log"
+
+	// composeLogName is where output that isn't from a service is kept, e.g.
+	// compose's own messages.
+	composeLogName = "_compose"
+)
+
+// Store keeps the logs of each run on disk, in <dir>/<ws>/<run>, with a file
+// per service. Each line is stored as json, along with when it was recorded,
+// so the services can be interleaved again when they're read back.
+type Store struct {
+	fs          afero.Fs
+	dir         string
+	now         func() time.Time
+	maxFileSize int64
+	maxBackups  int
+}
+
+// NewRunID names a run after when it started.
+func (s *Store) NewRunID() string {
+	return s.now().Format(runIDFormat)
+}
+
+func (s *Store) runDir(wsName string, run string) string {
+	return filepath.Join(s.dir, wsName, run)
+}
+
+// Runs lists the runs recorded for the workspace, oldest first.
+func (s *Store) Runs(wsName string) ([]string, error) {
+	entries, err := afero.ReadDir(s.fs, filepath.Join(s.dir, wsName))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+
+		return nil, err
+	}
+
+	runs := []string{}
+
+	for _, e := range entries {
+		if e.IsDir() {
+			runs = append(runs, e.Name())
+		}
+	}
+
+	slices.Sort(runs)
+
+	return runs, nil
+}
+
+func (s *Store) resolveRun(wsName string, run string) (string, error) {
+	runs, err := s.Runs(wsName)
+
+	if err != nil {
+		return "", err
+	}
+
+	if run == LatestRun && len(runs) > 0 {
+		return runs[len(runs)-1], nil
+	}
+
+	if !slices.Contains(runs, run) {
+		return "", common.ErrUnknownLogRun{
+			Workspace: wsName,
+			Run:       run,
+		}
+	}
+
+	return run, nil
+}
+
+func (s *Store) readFile(path string) ([]logmux.Line, error) {
+	f, err := s.fs.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	lines := []logmux.Line{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := logmux.Line{}
+
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("%s is not a recorded log: %w", path, err)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+// Read returns every line recorded during the run, in the order they were
+// recorded.
+func (s *Store) Read(wsName string, run string) ([]logmux.Line, error) {
+	run, err := s.resolveRun(wsName, run)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Only <project>/<service>.log files (and their rotations) are included
+	paths, err := afero.Glob(s.fs, filepath.Join(s.runDir(wsName, run), "*", "*"+logExt+"*"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []logmux.Line{}
+
+	for _, path := range paths {
+		fileLines, err := s.readFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, fileLines...)
+	}
+
+	sort.SliceStable(lines, func(i int, j int) bool {
+		return lines[i].Time.Before(lines[j].Time)
+	})
+
+	return lines, nil
+}
+
+// Record streams the sources into a new run, until they've all finished.
+func (s *Store) Record(wsName string, run string, sources []logmux.Source) error {
+	dir := s.runDir(wsName, run)
+
+	if err := s.fs.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+
+	// Created exclusively, so two runs never write into the same files
+	if err := s.fs.Mkdir(dir, 0755); err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("run '%s' has already been recorded for workspace '%s'", run, wsName)
+		}
+
+		return err
+	}
+
+	r := &recorder{
+		store: s,
+		dir:   dir,
+		files: map[string]*recordedFile{},
+	}
+
+	err := logmux.Stream(sources, nil, r)
+
+	if closeErr := r.close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// logPath is where the line is recorded, based on its project/service prefix.
+func (r *recorder) logPath(prefix string) string {
+	project, service, found := strings.Cut(prefix, "/")
+
+	if !found {
+		service = composeLogName
+	}
+
+	return filepath.Join(r.dir, project, service+logExt)
+}
+
+func NewStore(fs afero.Fs, dir string) *Store {
+	return &Store{
+		fs:          fs,
+		dir:         dir,
+		now:         time.Now,
+		maxFileSize: DefaultMaxFileSize,
+		maxBackups:  DefaultMaxBackups,
+	}
+}
@@ -0,0 +1,179 @@
+package logstore
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/pkg/logmux"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore ticks the clock forward a second every time it's read, so the
+// lines have a known order.
+func newTestStore() *Store {
+	s := NewStore(afero.NewMemMapFs(), "/logs")
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	s.now = func() time.Time {
+		now = now.Add(time.Second)
+
+		return now
+	}
+
+	return s
+}
+
+func source(project string, lines ...string) logmux.Source {
+	return logmux.Source{
+		Name: project,
+		Start: func(w io.Writer) error {
+			for _, l := range lines {
+				if _, err := fmt.Fprintln(w, l); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Parse: func(raw string) logmux.Line {
+			return logmux.Line{
+				Prefix: project + "/php",
+				Text:   raw,
+			}
+		},
+	}
+}
+
+func texts(lines []logmux.Line) []string {
+	out := make([]string, len(lines))
+
+	for i, l := range lines {
+		out[i] = l.Text
+	}
+
+	return out
+}
+
+func Test_Store_RecordAndRead(t *testing.T) {
+	s := newTestStore()
+
+	err := s.Record("ws", "run-1", []logmux.Source{
+		source("api", "one", "two"),
+		{
+			Name:  "admin",
+			Start: func(w io.Writer) error { _, err := fmt.Fprintln(w, "Container admin-php-1 Started"); return err },
+		},
+	})
+	require.Nil(t, err)
+
+	exists, err := afero.Exists(s.fs, "/logs/ws/run-1/api/php.log")
+	require.Nil(t, err)
+	assert.True(t, exists)
+
+	exists, err = afero.Exists(s.fs, "/logs/ws/run-1/admin/_compose.log")
+	require.Nil(t, err)
+	assert.True(t, exists)
+
+	lines, err := s.Read("ws", "run-1")
+	require.Nil(t, err)
+	assert.Len(t, lines, 3)
+	assert.Subset(t, texts(lines), []string{"one", "two", "Container admin-php-1 Started"})
+
+	for i := 1; i < len(lines); i++ {
+		assert.True(t, lines[i-1].Time.Before(lines[i].Time))
+	}
+}
+
+func Test_Store_Rotates(t *testing.T) {
+	s := newTestStore()
+	s.maxFileSize = 100
+	s.maxBackups = 2
+
+	lines := []string{}
+
+	for i := range 10 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+
+	err := s.Record("ws", "run-1", []logmux.Source{source("api", lines...)})
+	require.Nil(t, err)
+
+	for _, path := range []string{"php.log", "php.log.1", "php.log.2"} {
+		exists, err := afero.Exists(s.fs, "/logs/ws/run-1/api/"+path)
+		require.Nil(t, err)
+		assert.True(t, exists, path)
+	}
+
+	exists, err := afero.Exists(s.fs, "/logs/ws/run-1/api/php.log.3")
+	require.Nil(t, err)
+	assert.False(t, exists)
+
+	read, err := s.Read("ws", "run-1")
+	require.Nil(t, err)
+	assert.Equal(t, lines[10-len(read):], texts(read))
+}
+
+func Test_Store_Runs(t *testing.T) {
+	s := newTestStore()
+
+	runs, err := s.Runs("ws")
+	require.Nil(t, err)
+	assert.Empty(t, runs)
+
+	first := s.NewRunID()
+	second := s.NewRunID()
+
+	require.Nil(t, s.Record("ws", second, []logmux.Source{source("api", "second")}))
+	require.Nil(t, s.Record("ws", first, []logmux.Source{source("api", "first")}))
+
+	runs, err = s.Runs("ws")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"20260102-150406.000", "20260102-150407.000"}, runs)
+
+	lines, err := s.Read("ws", LatestRun)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"second"}, texts(lines))
+
+	_, err = s.Read("ws", "20250101-000000")
+	assert.Equal(t, common.ErrUnknownLogRun{Workspace: "ws", Run: "20250101-000000"}, err)
+
+	_, err = s.Read("other", LatestRun)
+	assert.Equal(t, common.ErrUnknownLogRun{Workspace: "other", Run: LatestRun}, err)
+}
+
+func Test_Store_RunsInTheSameSecond(t *testing.T) {
+	s := newTestStore()
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	s.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+
+		return now
+	}
+
+	first := s.NewRunID()
+	second := s.NewRunID()
+
+	assert.Equal(t, "20260102-150405.001", first)
+	assert.Equal(t, "20260102-150405.002", second)
+
+	require.Nil(t, s.Record("ws", first, []logmux.Source{source("api", "first")}))
+	require.Nil(t, s.Record("ws", second, []logmux.Source{source("api", "second")}))
+
+	lines, err := s.Read("ws", LatestRun)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"second"}, texts(lines))
+
+	// A run is never appended to, even if the id is reused
+	err = s.Record("ws", first, []logmux.Source{source("api", "again")})
+	assert.EqualError(t, err, "run '20260102-150405.001' has already been recorded for workspace 'ws'")
+
+	lines, err = s.Read("ws", first)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"first"}, texts(lines))
+}
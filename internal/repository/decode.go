@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"gopkg.in/yaml.v3"
+)
+
+var yamlTypeErrorLinePattern = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// yamlFieldName returns the key that yaml.v3 will use for the field, or an
+// empty string if the field is not decoded at all.
+func yamlFieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+
+	tag := f.Tag.Get("yaml")
+	name, opts, _ := strings.Cut(tag, ",")
+
+	if name == "-" {
+		return "", false
+	}
+
+	inline := strings.Contains(opts, "inline")
+
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+
+	return name, inline
+}
+
+// knownFields returns every yaml key that can be decoded into the struct type,
+// including those of inlined structs.
+func knownFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, inline := yamlFieldName(f)
+
+		if inline {
+			for k, v := range knownFields(indirectType(f.Type)) {
+				fields[k] = v
+			}
+
+			continue
+		}
+
+		if name == "" {
+			continue
+		}
+
+		fields[name] = f.Type
+	}
+
+	return fields
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func levenshtein(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// suggestField finds the closest known field to the unknown key, to catch the
+// most common typos (e.g. 'composeFile' instead of 'composeFiles').
+func suggestField(key string, fields map[string]reflect.Type) string {
+	best := ""
+	bestDistance := 3
+
+	for name := range fields {
+		d := levenshtein(strings.ToLower(key), strings.ToLower(name))
+
+		if d < bestDistance || (d == bestDistance && name < best) {
+			best = name
+			bestDistance = d
+		}
+	}
+
+	return best
+}
+
+// collectUnknownFields walks the parsed yaml alongside the type it will be
+// decoded into, recording any keys that would otherwise be silently ignored.
+func collectUnknownFields(node *yaml.Node, t reflect.Type, path string) []common.ConfigIssue {
+	if node == nil {
+		return nil
+	}
+
+	t = indirectType(t)
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+
+		return collectUnknownFields(node.Content[0], t, path)
+	case yaml.AliasNode:
+		return collectUnknownFields(node.Alias, t, path)
+	}
+
+	issues := []common.ConfigIssue{}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		fields := knownFields(t)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			fieldType, ok := fields[key.Value]
+
+			if !ok {
+				msg := fmt.Sprintf("unknown field '%s%s'", path, key.Value)
+
+				if suggestion := suggestField(key.Value, fields); suggestion != "" {
+					msg = fmt.Sprintf("%s, did you mean '%s'?", msg, suggestion)
+				}
+
+				issues = append(issues, common.ConfigIssue{
+					Line:    key.Line,
+					Column:  key.Column,
+					Message: msg,
+				})
+
+				continue
+			}
+
+			issues = append(issues, collectUnknownFields(node.Content[i+1], fieldType, path+key.Value+".")...)
+		}
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+
+		for i, item := range node.Content {
+			issues = append(issues, collectUnknownFields(item, t.Elem(), fmt.Sprintf("%s%d.", path, i))...)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			issues = append(issues, collectUnknownFields(node.Content[i+1], t.Elem(), path+node.Content[i].Value+".")...)
+		}
+	}
+
+	return issues
+}
+
+func typeErrorToIssues(err *yaml.TypeError) []common.ConfigIssue {
+	issues := make([]common.ConfigIssue, len(err.Errors))
+
+	for i, e := range err.Errors {
+		issues[i] = common.ConfigIssue{
+			Message: e,
+		}
+
+		if m := yamlTypeErrorLinePattern.FindStringSubmatch(e); m != nil {
+			line, _ := strconv.Atoi(m[1])
+
+			issues[i] = common.ConfigIssue{
+				Line:    line,
+				Message: m[2],
+			}
+		}
+	}
+
+	return issues
+}
+
+func parseDocument(path string, contents []byte) (*yaml.Node, error) {
+	root := &yaml.Node{}
+
+	if err := yaml.Unmarshal(contents, root); err != nil {
+		return nil, common.ErrConfigIsNotValidYAML{
+			Path:       path,
+			ParseError: err.Error(),
+		}
+	}
+
+	return root, nil
+}
+
+// decodeStrict decodes the document into out, and returns any unknown fields
+// or type mismatches as issues rather than ignoring them.
+func decodeStrict(path string, root *yaml.Node, out any) ([]common.ConfigIssue, error) {
+	// An empty file, leave everything at its zero value
+	if root.Kind == 0 {
+		return nil, nil
+	}
+
+	issues := collectUnknownFields(root, reflect.TypeOf(out), "")
+
+	if err := root.Decode(out); err != nil {
+		typeErr, ok := err.(*yaml.TypeError)
+
+		if !ok {
+			return nil, common.ErrConfigIsNotValidYAML{
+				Path:       path,
+				ParseError: err.Error(),
+			}
+		}
+
+		issues = append(issues, typeErrorToIssues(typeErr)...)
+	}
+
+	return issues, nil
+}
+
+// nodeAt navigates the document by mapping keys (string) and sequence
+// indexes (int). The deepest node that could be found is returned, so that
+// issues still point somewhere close to the problem.
+func nodeAt(root *yaml.Node, path ...any) *yaml.Node {
+	node := root
+
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, p := range path {
+		if node == nil {
+			return nil
+		}
+
+		var next *yaml.Node
+
+		switch v := p.(type) {
+		case string:
+			if node.Kind != yaml.MappingNode {
+				return node
+			}
+
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == v {
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && v < len(node.Content) {
+				next = node.Content[v]
+			}
+		}
+
+		if next == nil {
+			return node
+		}
+
+		node = next
+	}
+
+	return node
+}
+
+func issueAt(root *yaml.Node, msg string, path ...any) common.ConfigIssue {
+	issue := common.ConfigIssue{
+		Message: msg,
+	}
+
+	if n := nodeAt(root, path...); n != nil {
+		issue.Line = n.Line
+		issue.Column = n.Column
+	}
+
+	return issue
+}
@@ -35,15 +35,40 @@ type Extension struct {
 	DefaultArgs []string `yaml:"defaultArgs"`
 }
 
+type Hook struct {
+	Name    string
+	Command string
+	Service string
+	Project string
+	Chdir   string
+
+	// Timeout is a duration, e.g. 30s or 5m.
+	Timeout   string
+	OnFailure string `yaml:"onFailure"`
+}
+
+type Hooks struct {
+	PreUp      []Hook `yaml:"preUp"`
+	PostUp     []Hook `yaml:"postUp"`
+	PreDown    []Hook `yaml:"preDown"`
+	PostDown   []Hook `yaml:"postDown"`
+	PostClone  []Hook `yaml:"postClone"`
+	PostSwitch []Hook `yaml:"postSwitch"`
+}
+
 type EnvFile struct {
 	Path string
 }
 
 type ProjectConfig struct {
+	// Include lists files which are merged underneath this one, see the
+	// repository package for how the merge works.
+	Include         []string
 	ComposeFiles    ComposeFiles `yaml:"composeFiles"`
 	EnvFiles        []EnvFile    `yaml:"envFiles"`
 	Properties      []Property
 	Hosts           []string
 	TLSCertificates []string `yaml:"tlsCerts"`
 	Extensions      []Extension
+	Hooks           Hooks
 }
@@ -6,6 +6,9 @@ type WorkspaceProjectRepository struct {
 	// Self signifies that this project is actually the same repo as the workspace
 	// itself.
 	Self bool
+
+	// Branch is the branch the project is expected to be on, if any.
+	Branch string
 }
 
 type WorkspaceProjectConfig struct {
@@ -26,8 +29,19 @@ type Overlays struct {
 	Network NetworkOverlay
 }
 
+type Compose struct {
+	// Provider is one of docker, podman or nerdctl.
+	Provider string
+}
+
 type WorkspaceConfig struct {
+	// Include lists files which are merged underneath this one, see the
+	// repository package for how the merge works.
+	Include  []string
 	Name     string
 	Projects []WorkspaceProjectConfig
 	Overlays Overlays
+	Profiles map[string][]string
+	Compose  Compose
+	Hooks    Hooks
 }
@@ -0,0 +1,458 @@
+package repository
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/repository/internal/model"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	includeKey = "include"
+
+	// workspaceIncludePrefix marks an include path as relative to the directory
+	// containing the workspace config, rather than the including file. This
+	// allows projects to share defaults kept in the workspace repo.
+	workspaceIncludePrefix = "workspace:"
+
+	// localOverrideSuffix is added to a config file name to find the (optional)
+	// file of machine specific overrides, e.g. orca.workspace.local.yaml. These
+	// should not be committed.
+	localOverrideSuffix = ".local"
+)
+
+// variablePattern matches $$ (an escaped $), ${NAME} and ${NAME:-default}.
+var variablePattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_.\-]*)(?::-([^}]*))?\}`)
+
+// configLoader reads a config file along with everything it includes, merges
+// them into a single document, and interpolates any variables.
+//
+// Merging works on the yaml documents, with the including file taking
+// precedence over the files it includes (and later includes taking precedence
+// over earlier ones):
+//   - maps are merged key by key, recursively.
+//   - lists of maps with a 'name' are merged by name, so an entry can be
+//     overridden in place. Any other entries are appended.
+//   - lists of scalars are appended, skipping values that are already present.
+//   - anything else is replaced.
+//
+// The local override file is merged in the same way, on top of everything
+// else.
+type configLoader struct {
+	fs           afero.Fs
+	workspaceDir string
+	lookupEnv    func(string) (string, bool)
+
+	// sources records which file each node was read from, so the effective
+	// config can be explained.
+	sources map[*yaml.Node]string
+}
+
+func localOverridePath(path string) string {
+	ext := filepath.Ext(path)
+
+	return strings.TrimSuffix(path, ext) + localOverrideSuffix + ext
+}
+
+func (l *configLoader) recordSources(node *yaml.Node, path string) {
+	if node == nil {
+		return
+	}
+
+	l.sources[node] = path
+
+	for _, c := range node.Content {
+		l.recordSources(c, path)
+	}
+}
+
+func (l *configLoader) resolveInclude(from string, include string) string {
+	if rel, ok := strings.CutPrefix(include, workspaceIncludePrefix); ok {
+		return filepath.Join(l.workspaceDir, rel)
+	}
+
+	if filepath.IsAbs(include) {
+		return include
+	}
+
+	return filepath.Join(filepath.Dir(from), include)
+}
+
+// loadDocument reads and checks a single file, and then recursively merges in
+// anything it includes. Each file is checked on its own, so that any issues
+// are reported against the file and line they actually appear on. The issues
+// for path itself are returned, whereas those in included files are errors.
+func (l *configLoader) loadDocument(path string, t reflect.Type, stack []string) (*yaml.Node, []common.ConfigIssue, error) {
+	contents, err := afero.ReadFile(l.fs, path)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root, err := parseDocument(path, contents)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l.recordSources(root, path)
+
+	issues, err := decodeStrict(path, withoutVariables(root), reflect.New(t).Interface())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stack = append(stack, path)
+	var merged *yaml.Node
+
+	includes := nodeAt(root, includeKey)
+
+	if includes == nil || includes.Kind != yaml.SequenceNode {
+		return unwrapDocument(root), issues, nil
+	}
+
+	for i, include := range includes.Content {
+		includePath := l.resolveInclude(path, include.Value)
+
+		if slices.Contains(stack, includePath) {
+			return nil, nil, common.ErrInvalidConfig{
+				Path: path,
+				Issues: []common.ConfigIssue{
+					issueAt(root, fmt.Sprintf("'%s' is already being included, includes cannot be circular", include.Value), includeKey, i),
+				},
+			}
+		}
+
+		found, err := afero.Exists(l.fs, includePath)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !found {
+			return nil, nil, common.ErrInvalidConfig{
+				Path: path,
+				Issues: []common.ConfigIssue{
+					issueAt(root, fmt.Sprintf("included file '%s' does not exist", includePath), includeKey, i),
+				},
+			}
+		}
+
+		doc, includeIssues, err := l.loadDocument(includePath, t, stack)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(includeIssues) > 0 {
+			return nil, nil, common.ErrInvalidConfig{
+				Path:   includePath,
+				Issues: includeIssues,
+			}
+		}
+
+		merged = mergeNodes(merged, doc)
+	}
+
+	return mergeNodes(merged, withoutKey(unwrapDocument(root), includeKey)), issues, nil
+}
+
+// load builds the complete config from path (including the local overrides)
+// into out. The returned node is the merged document, which can be used to
+// locate any further issues.
+func (l *configLoader) load(path string, out any) (*yaml.Node, []common.ConfigIssue, error) {
+	t := reflect.TypeOf(out).Elem()
+	root, issues, err := l.loadDocument(path, t, nil)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	localPath := localOverridePath(path)
+	found, err := afero.Exists(l.fs, localPath)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if found {
+		local, localIssues, err := l.loadDocument(localPath, t, []string{path})
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(localIssues) > 0 {
+			return nil, nil, common.ErrInvalidConfig{
+				Path:   localPath,
+				Issues: localIssues,
+			}
+		}
+
+		root = mergeNodes(root, local)
+	}
+
+	if root == nil {
+		return &yaml.Node{}, issues, nil
+	}
+
+	// Properties are decoded before interpolation, so that their defaults can
+	// be used as variables.
+	props := struct {
+		Properties []model.Property
+	}{}
+	_ = root.Decode(&props)
+
+	variables := map[string]string{}
+
+	for _, p := range props.Properties {
+		if p.Name != "" && p.Default != nil {
+			variables[p.Name] = fmt.Sprint(p.Default)
+		}
+	}
+
+	l.interpolate(root, variables)
+
+	// Unknown fields have already been reported per file, so only the values
+	// which came from variables can cause new problems here.
+	if err := root.Decode(out); err != nil {
+		typeErr, ok := err.(*yaml.TypeError)
+
+		if !ok {
+			return nil, nil, common.ErrConfigIsNotValidYAML{
+				Path:       path,
+				ParseError: err.Error(),
+			}
+		}
+
+		if len(issues) == 0 {
+			issues = typeErrorToIssues(typeErr)
+		}
+	}
+
+	return root, issues, nil
+}
+
+func (l *configLoader) lookupVariable(name string, variables map[string]string) string {
+	if v, ok := l.lookupEnv(name); ok && v != "" {
+		return v
+	}
+
+	return variables[name]
+}
+
+// interpolate replaces variables within every scalar in the document. The
+// environment takes precedence over property defaults, and unset (or empty)
+// variables without a default become empty, the same as in compose files.
+func (l *configLoader) interpolate(node *yaml.Node, variables map[string]string) {
+	if node == nil {
+		return
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		for _, c := range node.Content {
+			l.interpolate(c, variables)
+		}
+
+		return
+	}
+
+	if !strings.Contains(node.Value, "$") {
+		return
+	}
+
+	node.Value = variablePattern.ReplaceAllStringFunc(node.Value, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+
+		m := variablePattern.FindStringSubmatch(match)
+
+		if v := l.lookupVariable(m[1], variables); v != "" {
+			return v
+		}
+
+		return m[2]
+	})
+
+	// Unquoted values are resolved again, so that a variable can provide a
+	// bool or a number.
+	if node.Style == 0 {
+		node.Tag = ""
+	}
+}
+
+// withoutVariables copies the document, replacing any value containing a
+// variable with null. This allows each file to be type checked before the
+// variables can be resolved.
+func withoutVariables(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	out := *node
+
+	if node.Kind == yaml.ScalarNode && variablePattern.MatchString(node.Value) {
+		out.Tag = "!!null"
+		out.Value = ""
+		out.Style = 0
+
+		return &out
+	}
+
+	out.Content = make([]*yaml.Node, len(node.Content))
+
+	for i, c := range node.Content {
+		out.Content[i] = withoutVariables(c)
+	}
+
+	return &out
+}
+
+func unwrapDocument(node *yaml.Node) *yaml.Node {
+	// An empty file
+	if node == nil || node.Kind == 0 {
+		return nil
+	}
+
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+
+		return node.Content[0]
+	}
+
+	return node
+}
+
+func withoutKey(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return node
+	}
+
+	out := *node
+	out.Content = []*yaml.Node{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			out.Content = append(out.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+
+	return &out
+}
+
+func mappingValue(node *yaml.Node, key string) (*yaml.Node, int) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1], i + 1
+		}
+	}
+
+	return nil, -1
+}
+
+func itemName(node *yaml.Node) string {
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+
+	if name, _ := mappingValue(node, "name"); name != nil && name.Kind == yaml.ScalarNode {
+		return name.Value
+	}
+
+	return ""
+}
+
+// mergeNodes merges override on top of base, without modifying either.
+func mergeNodes(base *yaml.Node, override *yaml.Node) *yaml.Node {
+	if base == nil {
+		return override
+	}
+
+	if override == nil {
+		return base
+	}
+
+	if base.Kind != override.Kind || (override.Kind != yaml.MappingNode && override.Kind != yaml.SequenceNode) {
+		return override
+	}
+
+	out := *override
+
+	switch override.Kind {
+	case yaml.MappingNode:
+		out.Content = slices.Clone(base.Content)
+
+		for i := 0; i+1 < len(override.Content); i += 2 {
+			key := override.Content[i]
+			existing, at := mappingValue(&out, key.Value)
+
+			if existing == nil {
+				out.Content = append(out.Content, key, override.Content[i+1])
+				continue
+			}
+
+			out.Content[at] = mergeNodes(existing, override.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		out.Content = slices.Clone(base.Content)
+
+	items:
+		for _, item := range override.Content {
+			name := itemName(item)
+
+			for j, existing := range out.Content {
+				if name != "" && itemName(existing) == name {
+					out.Content[j] = mergeNodes(existing, item)
+					continue items
+				}
+
+				if item.Kind == yaml.ScalarNode && existing.Kind == yaml.ScalarNode && item.Value == existing.Value {
+					continue items
+				}
+			}
+
+			out.Content = append(out.Content, item)
+		}
+	}
+
+	return &out
+}
+
+// annotateSources adds a comment to every value in the document, saying which
+// file and line it came from.
+func (l *configLoader) annotateSources(node *yaml.Node) {
+	if node == nil {
+		return
+	}
+
+	if node.Kind == yaml.MappingNode {
+		for i := 1; i < len(node.Content); i += 2 {
+			l.annotateSources(node.Content[i])
+		}
+
+		return
+	}
+
+	if node.Kind == yaml.SequenceNode && len(node.Content) > 0 {
+		for _, c := range node.Content {
+			l.annotateSources(c)
+		}
+
+		return
+	}
+
+	if source, ok := l.sources[node]; ok {
+		node.LineComment = fmt.Sprintf("%s:%d", source, node.Line)
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/panoptescloud/orca/internal/repository/internal/model"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// orVariable allows a non-string value to be given as a variable instead,
+// which is only resolved to the real type when the config is loaded.
+func orVariable(schema map[string]any) map[string]any {
+	return map[string]any{
+		"anyOf": []any{
+			schema,
+			map[string]any{"type": "string", "pattern": `\$\{`},
+		},
+	}
+}
+
+// schemaFor builds a JSON schema from the same type information used when
+// decoding, so the published schema can't drift from what is accepted.
+func schemaFor(t reflect.Type) map[string]any {
+	t = indirectType(t)
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return orVariable(map[string]any{"type": "boolean"})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return orVariable(map[string]any{"type": "integer"})
+	case reflect.Float32, reflect.Float64:
+		return orVariable(map[string]any{"type": "number"})
+	case reflect.Slice, reflect.Array:
+		return map[string]any{
+			"type":  "array",
+			"items": schemaFor(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": schemaFor(t.Elem()),
+		}
+	case reflect.Struct:
+		props := map[string]any{}
+
+		for name, fieldType := range knownFields(t) {
+			props[name] = schemaFor(fieldType)
+		}
+
+		return map[string]any{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+	default:
+		// Interfaces (any) accept whatever value is given
+		return map[string]any{}
+	}
+}
+
+func buildSchema(title string, v any) ([]byte, error) {
+	schema := schemaFor(reflect.TypeOf(v))
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = title
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// WorkspaceConfigSchema returns the JSON schema for orca.workspace.yaml files.
+func WorkspaceConfigSchema() ([]byte, error) {
+	return buildSchema("orca workspace config", model.WorkspaceConfig{})
+}
+
+// ProjectConfigSchema returns the JSON schema for orca.project.yaml files.
+func ProjectConfigSchema() ([]byte, error) {
+	return buildSchema("orca project config", model.ProjectConfig{})
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/repository/internal/model"
+	"github.com/panoptescloud/orca/pkg/dag"
+	"gopkg.in/yaml.v3"
+)
+
+// requiresGraphable adapts the workspace project config so the dependencies
+// can be checked for cycles before the config is converted.
+type requiresGraphable model.WorkspaceProjectConfig
+
+func (r requiresGraphable) GetKey() string {
+	return r.Name
+}
+
+func (r requiresGraphable) GetParents() []string {
+	return r.Requires
+}
+
+func (r requiresGraphable) GetChildren() []string {
+	return []string{}
+}
+
+// hookList is the hooks for an event, along with the key they're found at.
+type hookList struct {
+	key   string
+	hooks []model.Hook
+}
+
+func hookLists(hooks model.Hooks) []hookList {
+	return []hookList{
+		{string(common.HookPreUp), hooks.PreUp},
+		{string(common.HookPostUp), hooks.PostUp},
+		{string(common.HookPreDown), hooks.PreDown},
+		{string(common.HookPostDown), hooks.PostDown},
+		{string(common.HookPostClone), hooks.PostClone},
+		{string(common.HookPostSwitch), hooks.PostSwitch},
+	}
+}
+
+// validateHooks checks the hooks of a workspace, or of a project when
+// projectNames is nil.
+func validateHooks(hooks model.Hooks, root *yaml.Node, projectNames []string) []common.ConfigIssue {
+	issues := []common.ConfigIssue{}
+	isWorkspace := projectNames != nil
+
+	for _, list := range hookLists(hooks) {
+		for i, h := range list.hooks {
+			if h.Command == "" {
+				issues = append(issues, issueAt(root, "hook 'command' is required", "hooks", list.key, i))
+			}
+
+			if h.Timeout != "" {
+				if d, err := time.ParseDuration(h.Timeout); err != nil || d <= 0 {
+					issues = append(issues, issueAt(
+						root,
+						fmt.Sprintf("invalid hook timeout '%s', must be a duration such as 30s or 5m", h.Timeout),
+						"hooks", list.key, i, "timeout",
+					))
+				}
+			}
+
+			if h.OnFailure != "" && !slices.Contains(common.HookFailurePolicies, h.OnFailure) {
+				issues = append(issues, issueAt(
+					root,
+					fmt.Sprintf("unknown hook 'onFailure' value '%s', must be one of: %s", h.OnFailure, strings.Join(common.HookFailurePolicies, ", ")),
+					"hooks", list.key, i, "onFailure",
+				))
+			}
+
+			if !isWorkspace {
+				if h.Project != "" {
+					issues = append(issues, issueAt(root, "hook 'project' can only be set in the workspace config", "hooks", list.key, i, "project"))
+				}
+
+				continue
+			}
+
+			if h.Project != "" && !slices.Contains(projectNames, h.Project) {
+				issues = append(issues, issueAt(
+					root,
+					fmt.Sprintf("hook refers to unknown project '%s'", h.Project),
+					"hooks", list.key, i, "project",
+				))
+			}
+
+			if h.Service != "" && h.Project == "" {
+				issues = append(issues, issueAt(root, "hook 'project' is required to run a workspace hook in a service", "hooks", list.key, i))
+			}
+		}
+	}
+
+	return issues
+}
+
+func validateWorkspaceConfig(cfg *model.WorkspaceConfig, root *yaml.Node) []common.ConfigIssue {
+	issues := []common.ConfigIssue{}
+
+	if cfg.Name == "" {
+		issues = append(issues, issueAt(root, "'name' is required"))
+	}
+
+	names := []string{}
+	firstSeenAt := map[string]int{}
+
+	for i, p := range cfg.Projects {
+		if p.Name == "" {
+			issues = append(issues, issueAt(root, "project 'name' is required", "projects", i))
+			continue
+		}
+
+		if first, ok := firstSeenAt[p.Name]; ok {
+			issues = append(issues, issueAt(
+				root,
+				fmt.Sprintf("duplicate project name '%s', first defined at line %d", p.Name, nodeAt(root, "projects", first).Line),
+				"projects", i, "name",
+			))
+			continue
+		}
+
+		firstSeenAt[p.Name] = i
+		names = append(names, p.Name)
+	}
+
+	canCheckCycles := len(names) == len(cfg.Projects)
+
+	for i, p := range cfg.Projects {
+		for j, r := range p.Requires {
+			if !slices.Contains(names, r) {
+				issues = append(issues, issueAt(
+					root,
+					fmt.Sprintf("project '%s' requires unknown project '%s'", p.Name, r),
+					"projects", i, "requires", j,
+				))
+				canCheckCycles = false
+			}
+		}
+	}
+
+	if canCheckCycles {
+		graphables := make([]requiresGraphable, len(cfg.Projects))
+		for i, p := range cfg.Projects {
+			graphables[i] = requiresGraphable(p)
+		}
+
+		g, err := dag.NewGraph(graphables)
+
+		if err == nil {
+			_, err = g.TopologicalKeysFromRoots()
+		}
+
+		if err != nil {
+			issues = append(issues, issueAt(root, fmt.Sprintf("invalid 'requires': %s", err.Error()), "projects"))
+		}
+	}
+
+	profileNames := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		profileNames = append(profileNames, name)
+	}
+	slices.Sort(profileNames)
+
+	for _, name := range profileNames {
+		for i, p := range cfg.Profiles[name] {
+			if !slices.Contains(names, p) {
+				issues = append(issues, issueAt(
+					root,
+					fmt.Sprintf("profile '%s' refers to unknown project '%s'", name, p),
+					"profiles", name, i,
+				))
+			}
+		}
+	}
+
+	network := cfg.Overlays.Network
+
+	if network.Enabled && network.CreateIn == "" {
+		issues = append(issues, issueAt(root, "'createIn' must be set when the network overlay is enabled", "overlays", "network"))
+	}
+
+	if network.CreateIn != "" && !slices.Contains(names, network.CreateIn) {
+		issues = append(issues, issueAt(
+			root,
+			fmt.Sprintf("'createIn' refers to unknown project '%s'", network.CreateIn),
+			"overlays", "network", "createIn",
+		))
+	}
+
+	if cfg.Compose.Provider != "" && !slices.Contains(common.ComposeProviders, cfg.Compose.Provider) {
+		issues = append(issues, issueAt(
+			root,
+			fmt.Sprintf("unknown compose provider '%s', must be one of: %s", cfg.Compose.Provider, strings.Join(common.ComposeProviders, ", ")),
+			"compose", "provider",
+		))
+	}
+
+	issues = append(issues, validateHooks(cfg.Hooks, root, names)...)
+
+	return issues
+}
+
+func validateProjectConfig(cfg *model.ProjectConfig, root *yaml.Node) []common.ConfigIssue {
+	issues := []common.ConfigIssue{}
+
+	if cfg.ComposeFiles.Primary == "" {
+		issues = append(issues, issueAt(root, "'composeFiles.primary' is required", "composeFiles"))
+	}
+
+	for i, e := range cfg.EnvFiles {
+		if e.Path == "" {
+			issues = append(issues, issueAt(root, "env file 'path' is required", "envFiles", i))
+		}
+	}
+
+	extNames := []string{}
+
+	for i, ext := range cfg.Extensions {
+		if ext.Name == "" {
+			issues = append(issues, issueAt(root, "extension 'name' is required", "extensions", i))
+		} else if slices.Contains(extNames, ext.Name) {
+			issues = append(issues, issueAt(root, fmt.Sprintf("duplicate extension name '%s'", ext.Name), "extensions", i, "name"))
+		} else {
+			extNames = append(extNames, ext.Name)
+		}
+
+		if ext.Command == "" {
+			issues = append(issues, issueAt(root, "extension 'command' is required", "extensions", i))
+		}
+	}
+
+	issues = append(issues, validateHooks(cfg.Hooks, root, nil)...)
+
+	return issues
+}
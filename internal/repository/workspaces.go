@@ -2,7 +2,10 @@ package repository
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/panoptescloud/orca/internal/common"
 	"github.com/panoptescloud/orca/internal/repository/internal/model"
@@ -97,6 +100,53 @@ func convertExtensions(cfgExts []model.Extension) []common.Extension {
 	return exts
 }
 
+// convertHook applies the defaults for anything that isn't set. The config has
+// already been validated, so the timeout is known to parse.
+func convertHook(h model.Hook) common.Hook {
+	timeout := common.DefaultHookTimeout
+
+	if h.Timeout != "" {
+		timeout, _ = time.ParseDuration(h.Timeout)
+	}
+
+	onFailure := common.HookFailurePolicy(h.OnFailure)
+
+	if onFailure == "" {
+		onFailure = common.HookAbort
+	}
+
+	return common.Hook{
+		Name:      h.Name,
+		Command:   h.Command,
+		Service:   h.Service,
+		Project:   h.Project,
+		Chdir:     h.Chdir,
+		Timeout:   timeout,
+		OnFailure: onFailure,
+	}
+}
+
+func convertHookList(cfgHooks []model.Hook) []common.Hook {
+	hooks := make([]common.Hook, len(cfgHooks))
+
+	for i, h := range cfgHooks {
+		hooks[i] = convertHook(h)
+	}
+
+	return hooks
+}
+
+func convertHooks(cfg model.Hooks) common.Hooks {
+	return common.Hooks{
+		PreUp:      convertHookList(cfg.PreUp),
+		PostUp:     convertHookList(cfg.PostUp),
+		PreDown:    convertHookList(cfg.PreDown),
+		PostDown:   convertHookList(cfg.PostDown),
+		PostClone:  convertHookList(cfg.PostClone),
+		PostSwitch: convertHookList(cfg.PostSwitch),
+	}
+}
+
 func convertEnvFile(e model.EnvFile) common.EnvFile {
 	return common.EnvFile{
 		Path: e.Path,
@@ -117,8 +167,9 @@ func buildProject(wsPCfg model.WorkspaceProjectConfig, meta common.ProjectMeta,
 	return common.Project{
 		Name: wsPCfg.Name,
 		RepositoryConfig: common.ProjectRepositoryConfig{
-			SSH:  meta.Repository.SSH,
-			Self: meta.Repository.Self,
+			SSH:    wsPCfg.Repository.SSH,
+			Self:   wsPCfg.Repository.Self,
+			Branch: wsPCfg.Repository.Branch,
 		},
 		IsRegistered: true,
 		ProjectDir:   meta.Path,
@@ -130,6 +181,7 @@ func buildProject(wsPCfg model.WorkspaceProjectConfig, meta common.ProjectMeta,
 			TLSCertificates: pCfg.TLSCertificates,
 			Extensions:      convertExtensions(pCfg.Extensions),
 			EnvFiles:        convertEnvFiles(pCfg.EnvFiles),
+			Hooks:           convertHooks(pCfg.Hooks),
 		},
 	}
 }
@@ -137,10 +189,24 @@ func buildProject(wsPCfg model.WorkspaceProjectConfig, meta common.ProjectMeta,
 type WorkspaceRepository struct {
 	fs         afero.Fs
 	userConfig userConfig
+	lookupEnv  func(string) (string, bool)
 }
 
-func (wcr *WorkspaceRepository) loadProject(projectMeta common.ProjectMeta) (*model.ProjectConfig, error) {
-	path := fmt.Sprintf("%s/%s", strings.TrimSuffix(projectMeta.Path, "/"), common.DefaultProjectFileName)
+func (wcr *WorkspaceRepository) newLoader(wsConfigPath string) *configLoader {
+	return &configLoader{
+		fs:           wcr.fs,
+		workspaceDir: filepath.Dir(wsConfigPath),
+		lookupEnv:    wcr.lookupEnv,
+		sources:      map[*yaml.Node]string{},
+	}
+}
+
+func projectConfigPath(projectMeta common.ProjectMeta) string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(projectMeta.Path, "/"), common.DefaultProjectFileName)
+}
+
+func (wcr *WorkspaceRepository) loadProject(projectMeta common.ProjectMeta, wsConfigPath string) (*model.ProjectConfig, error) {
+	path := projectConfigPath(projectMeta)
 
 	found, err := afero.Exists(wcr.fs, path)
 
@@ -154,18 +220,20 @@ func (wcr *WorkspaceRepository) loadProject(projectMeta common.ProjectMeta) (*mo
 		}
 	}
 
-	contents, err := afero.ReadFile(wcr.fs, path)
+	cfg := &model.ProjectConfig{}
+
+	root, issues, err := wcr.newLoader(wsConfigPath).load(path, cfg)
 
 	if err != nil {
 		return nil, err
 	}
 
-	cfg := &model.ProjectConfig{}
+	issues = append(issues, validateProjectConfig(cfg, root)...)
 
-	if err := yaml.Unmarshal(contents, cfg); err != nil {
-		return nil, common.ErrConfigIsNotValidYAML{
-			Path:       path,
-			ParseError: err.Error(),
+	if len(issues) > 0 {
+		return nil, common.ErrInvalidConfig{
+			Path:   path,
+			Issues: issues,
 		}
 	}
 
@@ -185,18 +253,20 @@ func (wcr *WorkspaceRepository) loadConfigFromPath(path string) (*model.Workspac
 		}
 	}
 
-	contents, err := afero.ReadFile(wcr.fs, path)
+	cfg := &model.WorkspaceConfig{}
+
+	root, issues, err := wcr.newLoader(path).load(path, cfg)
 
 	if err != nil {
 		return nil, err
 	}
 
-	cfg := &model.WorkspaceConfig{}
+	issues = append(issues, validateWorkspaceConfig(cfg, root)...)
 
-	if err := yaml.Unmarshal(contents, cfg); err != nil {
-		return nil, common.ErrConfigIsNotValidYAML{
-			Path:       path,
-			ParseError: err.Error(),
+	if len(issues) > 0 {
+		return nil, common.ErrInvalidConfig{
+			Path:   path,
+			Issues: issues,
 		}
 	}
 
@@ -230,19 +300,35 @@ func (wcr *WorkspaceRepository) LoadUnconfiguredWorkspace(path string) (*common.
 }
 
 func (wcr *WorkspaceRepository) Load(name string) (*common.Workspace, error) {
+	ws, _, err := wcr.load(name, false)
+
+	return ws, err
+}
+
+// LoadWithProblems loads the workspace like Load, except a registered project
+// whose config is missing or invalid doesn't stop the rest from loading. The
+// project is still included, with an empty config, and the problem is returned
+// keyed by the project's name.
+func (wcr *WorkspaceRepository) LoadWithProblems(name string) (*common.Workspace, map[string]error, error) {
+	return wcr.load(name, true)
+}
+
+func (wcr *WorkspaceRepository) load(name string, tolerateProjects bool) (*common.Workspace, map[string]error, error) {
 	wsMeta, err := wcr.userConfig.GetWorkspaceMeta(name)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	cfg, err := wcr.loadConfigFromPath(wsMeta.Path)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	// The name it's registered under is used, rather than the one in the
+	// config, as the workspace may have been renamed.
 	ws := &common.Workspace{
-		Name:       cfg.Name,
+		Name:       wsMeta.Name,
 		ConfigPath: wsMeta.Path,
 		Projects:   make([]common.Project, len(cfg.Projects)),
 		OverlayConfig: common.OverlayConfig{
@@ -253,8 +339,13 @@ func (wcr *WorkspaceRepository) Load(name string) (*common.Workspace, error) {
 				AliasPattern:   cfg.Overlays.Network.AliasPattern,
 			},
 		},
+		Profiles:        cfg.Profiles,
+		ComposeProvider: cfg.Compose.Provider,
+		Hooks:           convertHooks(cfg.Hooks),
 	}
 
+	problems := map[string]error{}
+
 	for i, pCfg := range cfg.Projects {
 		projectMeta, err := wcr.userConfig.GetProjectMeta(wsMeta.Name, pCfg.Name)
 
@@ -263,8 +354,9 @@ func (wcr *WorkspaceRepository) Load(name string) (*common.Workspace, error) {
 				ws.Projects[i] = common.Project{
 					Name: pCfg.Name,
 					RepositoryConfig: common.ProjectRepositoryConfig{
-						SSH:  pCfg.Repository.SSH,
-						Self: pCfg.Repository.Self,
+						SSH:    pCfg.Repository.SSH,
+						Self:   pCfg.Repository.Self,
+						Branch: pCfg.Repository.Branch,
 					},
 					IsRegistered: false,
 					ProjectDir:   "",
@@ -274,24 +366,147 @@ func (wcr *WorkspaceRepository) Load(name string) (*common.Workspace, error) {
 				continue
 			}
 
+			return nil, nil, err
+		}
+
+		p, err := wcr.loadProject(projectMeta, wsMeta.Path)
+
+		if err != nil {
+			if !tolerateProjects || !isConfigProblem(err) {
+				return nil, nil, err
+			}
+
+			problems[pCfg.Name] = err
+			p = &model.ProjectConfig{}
+		}
+
+		ws.Projects[i] = buildProject(pCfg, projectMeta, *p)
+	}
+
+	return ws, problems, nil
+}
+
+// isConfigProblem reports whether the error was caused by the contents of a
+// config file, rather than something unexpected like a permissions issue.
+func isConfigProblem(err error) bool {
+	switch err.(type) {
+	case common.ErrInvalidConfig,
+		common.ErrConfigIsNotValidYAML,
+		common.ErrWorkspaceConfigNotFound,
+		common.ErrProjectConfigNotFound:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate checks the workspace config, and the config of every registered
+// project within it. Rather than stopping at the first problem, all problems
+// are returned. The error is only set if validation itself could not run.
+func (wcr *WorkspaceRepository) Validate(name string) ([]error, error) {
+	wsMeta, err := wcr.userConfig.GetWorkspaceMeta(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := wcr.loadConfigFromPath(wsMeta.Path)
+
+	if err != nil {
+		if isConfigProblem(err) {
+			return []error{err}, nil
+		}
+
+		return nil, err
+	}
+
+	problems := []error{}
+
+	for _, pCfg := range cfg.Projects {
+		projectMeta, err := wcr.userConfig.GetProjectMeta(wsMeta.Name, pCfg.Name)
+
+		if err != nil {
+			// Unregistered projects have nothing on disk to validate yet
+			if _, ok := err.(common.ErrUnknownProject); ok {
+				continue
+			}
+
 			return nil, err
 		}
 
-		p, err := wcr.loadProject(projectMeta)
+		if _, err := wcr.loadProject(projectMeta, wsMeta.Path); err != nil {
+			if !isConfigProblem(err) {
+				return nil, err
+			}
+
+			problems = append(problems, err)
+		}
+	}
+
+	return problems, nil
+}
+
+// DescribeConfig returns the effective config of the workspace, or of the
+// project if one is given, after all includes and local overrides have been
+// merged. Each value is commented with the file and line it came from.
+//
+// The config is still returned when it's invalid, along with an
+// ErrInvalidConfig describing the issues, as that's when it's most useful.
+func (wcr *WorkspaceRepository) DescribeConfig(wsName string, projectName string) ([]byte, error) {
+	wsMeta, err := wcr.userConfig.GetWorkspaceMeta(wsName)
+	if err != nil {
+		return nil, err
+	}
+
+	path := wsMeta.Path
+	var cfg any = &model.WorkspaceConfig{}
+
+	if projectName != "" {
+		projectMeta, err := wcr.userConfig.GetProjectMeta(wsMeta.Name, projectName)
 
 		if err != nil {
 			return nil, err
 		}
 
-		ws.Projects[i] = buildProject(pCfg, projectMeta, *p)
+		path = projectConfigPath(projectMeta)
+		cfg = &model.ProjectConfig{}
+	}
+
+	loader := wcr.newLoader(wsMeta.Path)
+	root, issues, err := loader.load(path, cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg := cfg.(type) {
+	case *model.WorkspaceConfig:
+		issues = append(issues, validateWorkspaceConfig(cfg, root)...)
+	case *model.ProjectConfig:
+		issues = append(issues, validateProjectConfig(cfg, root)...)
+	}
+
+	loader.annotateSources(root)
+
+	contents, err := yaml.Marshal(root)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(issues) > 0 {
+		return contents, common.ErrInvalidConfig{
+			Path:   path,
+			Issues: issues,
+		}
 	}
 
-	return ws, nil
+	return contents, nil
 }
 
 func NewWorkspaceRepository(fs afero.Fs, userConfig userConfig) *WorkspaceRepository {
 	return &WorkspaceRepository{
 		fs:         fs,
 		userConfig: userConfig,
+		lookupEnv:  os.LookupEnv,
 	}
 }
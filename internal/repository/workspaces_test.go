@@ -0,0 +1,654 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wsConfigPath = "/ws/orca.workspace.yaml"
+
+type stubUserConfig struct {
+	projects map[string]string
+}
+
+func (s stubUserConfig) GetWorkspaceMeta(name string) (common.WorkspaceMeta, error) {
+	if name != "test" {
+		return common.WorkspaceMeta{}, common.ErrUnknownWorkspace{Name: name}
+	}
+
+	return common.WorkspaceMeta{
+		Name: name,
+		Path: wsConfigPath,
+	}, nil
+}
+
+func (s stubUserConfig) GetProjectMeta(wsName string, projectName string) (common.ProjectMeta, error) {
+	path, ok := s.projects[projectName]
+
+	if !ok {
+		return common.ProjectMeta{}, common.ErrUnknownProject{Name: projectName}
+	}
+
+	return common.ProjectMeta{
+		Name:          projectName,
+		WorkspaceName: wsName,
+		Path:          path,
+	}, nil
+}
+
+func newTestRepository(t *testing.T, wsConfig string, projects map[string]string) *WorkspaceRepository {
+	fs := afero.NewMemMapFs()
+	require.Nil(t, afero.WriteFile(fs, wsConfigPath, []byte(wsConfig), 0644))
+
+	paths := map[string]string{}
+
+	for name, contents := range projects {
+		paths[name] = "/projects/" + name
+		require.Nil(t, afero.WriteFile(fs, paths[name]+"/"+common.DefaultProjectFileName, []byte(contents), 0644))
+	}
+
+	return NewWorkspaceRepository(fs, stubUserConfig{projects: paths})
+}
+
+const validProjectConfig = `composeFiles:
+  primary: docker-compose.yaml
+`
+
+func Test_Load_ValidatesWorkspaceConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		wsConfig  string
+		expectErr error
+	}{
+		{
+			name: "valid",
+			wsConfig: `name: test
+projects:
+  - name: api
+    requires: [db]
+  - name: db
+overlays:
+  network:
+    enabled: true
+    createIn: db
+`,
+		},
+		{
+			name: "unknown fields are reported with a suggestion",
+			wsConfig: `name: test
+projects:
+  - name: api
+    require: [db]
+  - name: db
+overlay:
+  network:
+    enabled: true
+`,
+			expectErr: common.ErrInvalidConfig{
+				Path: wsConfigPath,
+				Issues: []common.ConfigIssue{
+					{Line: 4, Column: 5, Message: "unknown field 'projects.0.require', did you mean 'requires'?"},
+					{Line: 6, Column: 1, Message: "unknown field 'overlay', did you mean 'overlays'?"},
+				},
+			},
+		},
+		{
+			name: "type errors",
+			wsConfig: `name: test
+projects:
+  - name: api
+overlays:
+  network:
+    enabled: maybe
+`,
+			expectErr: common.ErrInvalidConfig{
+				Path: wsConfigPath,
+				Issues: []common.ConfigIssue{
+					{Line: 6, Message: "cannot unmarshal !!str `maybe` into bool"},
+				},
+			},
+		},
+		{
+			name: "semantic problems",
+			wsConfig: `projects:
+  - name: api
+    requires: [missing]
+  - name: api
+overlays:
+  network:
+    enabled: true
+    createIn: nope
+`,
+			expectErr: common.ErrInvalidConfig{
+				Path: wsConfigPath,
+				Issues: []common.ConfigIssue{
+					{Line: 1, Column: 1, Message: "'name' is required"},
+					{Line: 4, Column: 11, Message: "duplicate project name 'api', first defined at line 2"},
+					{Line: 3, Column: 16, Message: "project 'api' requires unknown project 'missing'"},
+					{Line: 8, Column: 15, Message: "'createIn' refers to unknown project 'nope'"},
+				},
+			},
+		},
+		{
+			name: "profile with unknown project",
+			wsConfig: `name: test
+projects:
+  - name: api
+profiles:
+  backend: [api, db]
+`,
+			expectErr: common.ErrInvalidConfig{
+				Path: wsConfigPath,
+				Issues: []common.ConfigIssue{
+					{Line: 5, Column: 18, Message: "profile 'backend' refers to unknown project 'db'"},
+				},
+			},
+		},
+		{
+			name: "unknown compose provider",
+			wsConfig: `name: test
+projects:
+  - name: api
+compose:
+  provider: finch
+`,
+			expectErr: common.ErrInvalidConfig{
+				Path: wsConfigPath,
+				Issues: []common.ConfigIssue{
+					{Line: 5, Column: 13, Message: "unknown compose provider 'finch', must be one of: docker, podman, nerdctl"},
+				},
+			},
+		},
+		{
+			name: "invalid hooks",
+			wsConfig: `name: test
+projects:
+  - name: api
+hooks:
+  preUp:
+    - command: make certs
+      timeout: soon
+      onFailure: ignore
+  postUp:
+    - service: php
+    - command: php artisan migrate
+      service: php
+      project: web
+`,
+			expectErr: common.ErrInvalidConfig{
+				Path: wsConfigPath,
+				Issues: []common.ConfigIssue{
+					{Line: 7, Column: 16, Message: "invalid hook timeout 'soon', must be a duration such as 30s or 5m"},
+					{Line: 8, Column: 18, Message: "unknown hook 'onFailure' value 'ignore', must be one of: abort, warn"},
+					{Line: 10, Column: 7, Message: "hook 'command' is required"},
+					{Line: 10, Column: 7, Message: "hook 'project' is required to run a workspace hook in a service"},
+					{Line: 13, Column: 16, Message: "hook refers to unknown project 'web'"},
+				},
+			},
+		},
+		{
+			name: "dependency cycle",
+			wsConfig: `name: test
+projects:
+  - name: a
+    requires: [b]
+  - name: b
+    requires: [a]
+`,
+			expectErr: common.ErrInvalidConfig{
+				Path: wsConfigPath,
+				Issues: []common.ConfigIssue{
+					{Line: 3, Column: 3, Message: "invalid 'requires': cycle detected between: a, b"},
+				},
+			},
+		},
+		{
+			name: "not yaml",
+			wsConfig: `name: [test
+`,
+			expectErr: common.ErrConfigIsNotValidYAML{
+				Path:       wsConfigPath,
+				ParseError: "yaml: line 1: did not find expected ',' or ']'",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			repo := newTestRepository(tt, test.wsConfig, nil)
+
+			_, err := repo.Load("test")
+
+			assert.Equal(tt, test.expectErr, err)
+		})
+	}
+}
+
+func Test_Load_ValidatesProjectConfig(t *testing.T) {
+	repo := newTestRepository(t, `name: test
+projects:
+  - name: api
+`, map[string]string{
+		"api": `composeFile:
+  primary: docker-compose.yaml
+tlsCert:
+  - "*.example.com"
+extensions:
+  - name: test
+`,
+	})
+
+	_, err := repo.Load("test")
+
+	assert.Equal(t, common.ErrInvalidConfig{
+		Path: "/projects/api/orca.project.yaml",
+		Issues: []common.ConfigIssue{
+			{Line: 1, Column: 1, Message: "unknown field 'composeFile', did you mean 'composeFiles'?"},
+			{Line: 3, Column: 1, Message: "unknown field 'tlsCert', did you mean 'tlsCerts'?"},
+			{Line: 1, Column: 1, Message: "'composeFiles.primary' is required"},
+			{Line: 6, Column: 5, Message: "extension 'command' is required"},
+		},
+	}, err)
+}
+
+func Test_Load_Hooks(t *testing.T) {
+	repo := newTestRepository(t, `name: test
+projects:
+  - name: api
+hooks:
+  postSwitch:
+    - command: make env
+`, map[string]string{
+		"api": `composeFiles:
+  primary: docker-compose.yaml
+hooks:
+  postUp:
+    - name: migrate
+      command: php artisan migrate
+      service: php
+      timeout: 30s
+      onFailure: warn
+`,
+	})
+
+	ws, err := repo.Load("test")
+	require.Nil(t, err)
+
+	assert.Equal(t, []common.Hook{
+		{Command: "make env", Timeout: common.DefaultHookTimeout, OnFailure: common.HookAbort},
+	}, ws.Hooks.For(common.HookPostSwitch))
+
+	assert.Equal(t, []common.Hook{
+		{Name: "migrate", Command: "php artisan migrate", Service: "php", Timeout: 30 * time.Second, OnFailure: common.HookWarn},
+	}, ws.Projects[0].Config.Hooks.For(common.HookPostUp))
+
+	assert.Empty(t, ws.Projects[0].Config.Hooks.For(common.HookPreUp))
+}
+
+func Test_Load_HookProjectOnlyInWorkspace(t *testing.T) {
+	repo := newTestRepository(t, `name: test
+projects:
+  - name: api
+`, map[string]string{
+		"api": `composeFiles:
+  primary: docker-compose.yaml
+hooks:
+  preDown:
+    - command: make backup
+      project: api
+`,
+	})
+
+	_, err := repo.Load("test")
+
+	assert.Equal(t, common.ErrInvalidConfig{
+		Path: "/projects/api/orca.project.yaml",
+		Issues: []common.ConfigIssue{
+			{Line: 6, Column: 16, Message: "hook 'project' can only be set in the workspace config"},
+		},
+	}, err)
+}
+
+func Test_Validate_ReportsEveryProject(t *testing.T) {
+	repo := newTestRepository(t, `name: test
+projects:
+  - name: api
+  - name: web
+  - name: unregistered
+`, map[string]string{
+		"api": validProjectConfig,
+		"web": `composeFiles:
+  primary: docker-compose.yaml
+envFile:
+  - path: .env
+`,
+	})
+
+	problems, err := repo.Validate("test")
+
+	require.Nil(t, err)
+	assert.Equal(t, []error{
+		common.ErrInvalidConfig{
+			Path: "/projects/web/orca.project.yaml",
+			Issues: []common.ConfigIssue{
+				{Line: 3, Column: 1, Message: "unknown field 'envFile', did you mean 'envFiles'?"},
+			},
+		},
+	}, problems)
+
+	_, err = repo.Validate("unknown")
+	assert.Equal(t, common.ErrUnknownWorkspace{Name: "unknown"}, err)
+}
+
+func Test_LoadWithProblems(t *testing.T) {
+	repo := newTestRepository(t, `name: test
+projects:
+  - name: api
+  - name: web
+  - name: unregistered
+`, map[string]string{
+		"api": validProjectConfig,
+	})
+
+	// Registered, but the checkout has gone
+	repo.userConfig.(stubUserConfig).projects["web"] = "/moved/web"
+
+	_, err := repo.Load("test")
+	assert.Equal(t, common.ErrProjectConfigNotFound{Path: "/moved/web/orca.project.yaml"}, err)
+
+	ws, problems, err := repo.LoadWithProblems("test")
+
+	require.Nil(t, err)
+	assert.Equal(t, map[string]error{
+		"web": common.ErrProjectConfigNotFound{Path: "/moved/web/orca.project.yaml"},
+	}, problems)
+
+	require.Len(t, ws.Projects, 3)
+	assert.Equal(t, "docker-compose.yaml", ws.Projects[0].Config.ComposeFiles.Primary)
+	assert.True(t, ws.Projects[1].IsRegistered)
+	assert.Equal(t, "/moved/web", ws.Projects[1].ProjectDir)
+	assert.Empty(t, ws.Projects[1].Config.ComposeFiles.Primary)
+	assert.False(t, ws.Projects[2].IsRegistered)
+}
+
+func writeTestFile(t *testing.T, repo *WorkspaceRepository, path string, contents string) {
+	require.Nil(t, afero.WriteFile(repo.fs, path, []byte(contents), 0644))
+}
+
+func Test_Load_MergesIncludes(t *testing.T) {
+	repo := newTestRepository(t, `name: test
+include:
+  - shared/base.yaml
+projects:
+  - name: api
+    requires: [db]
+  - name: db
+    requires: []
+profiles:
+  backend: [api]
+`, map[string]string{
+		"api": `include:
+  - workspace:shared/project.yaml
+composeFiles:
+  primary: docker-compose.yaml
+hosts:
+  - api.test
+extensions:
+  - name: test
+    command: make test-api
+`,
+		"db": validProjectConfig,
+	})
+
+	writeTestFile(t, repo, "/ws/shared/base.yaml", `projects:
+  - name: db
+    repository:
+      ssh: git@example.com:db.git
+overlays:
+  network:
+    enabled: true
+    createIn: db
+profiles:
+  backend: [db]
+`)
+
+	writeTestFile(t, repo, "/ws/shared/project.yaml", `hosts:
+  - shared.test
+  - api.test
+extensions:
+  - name: test
+    command: make test
+    service: app
+  - name: lint
+    command: make lint
+`)
+
+	ws, err := repo.Load("test")
+	require.Nil(t, err)
+
+	require.Len(t, ws.Projects, 2)
+	assert.Equal(t, "db", ws.Projects[0].Name)
+	assert.Equal(t, "api", ws.Projects[1].Name)
+	assert.Equal(t, []string{"db"}, ws.Projects[1].Requires)
+	assert.True(t, ws.OverlayConfig.Network.Enabled)
+	assert.Equal(t, "db", ws.OverlayConfig.Network.CreateIn)
+	assert.Equal(t, map[string][]string{"backend": {"db", "api"}}, ws.Profiles)
+
+	api := ws.Projects[1].Config
+	assert.Equal(t, []string{"shared.test", "api.test"}, api.Hosts)
+	assert.Equal(t, []common.Extension{
+		{Name: "test", Command: "make test-api", Service: "app"},
+		{Name: "lint", Command: "make lint"},
+	}, api.Extensions)
+}
+
+func Test_Load_IncludeProblems(t *testing.T) {
+	tests := []struct {
+		name      string
+		wsConfig  string
+		files     map[string]string
+		expectErr error
+	}{
+		{
+			name: "missing include",
+			wsConfig: `name: test
+include:
+  - missing.yaml
+`,
+			expectErr: common.ErrInvalidConfig{
+				Path: wsConfigPath,
+				Issues: []common.ConfigIssue{
+					{Line: 3, Column: 5, Message: "included file '/ws/missing.yaml' does not exist"},
+				},
+			},
+		},
+		{
+			name: "circular include",
+			wsConfig: `name: test
+include:
+  - a.yaml
+`,
+			files: map[string]string{
+				"/ws/a.yaml": `include:
+  - orca.workspace.yaml
+`,
+			},
+			expectErr: common.ErrInvalidConfig{
+				Path: "/ws/a.yaml",
+				Issues: []common.ConfigIssue{
+					{Line: 2, Column: 5, Message: "'orca.workspace.yaml' is already being included, includes cannot be circular"},
+				},
+			},
+		},
+		{
+			name: "issues are reported against the included file",
+			wsConfig: `name: test
+include:
+  - a.yaml
+`,
+			files: map[string]string{
+				"/ws/a.yaml": `project:
+  - name: api
+`,
+			},
+			expectErr: common.ErrInvalidConfig{
+				Path: "/ws/a.yaml",
+				Issues: []common.ConfigIssue{
+					{Line: 1, Column: 1, Message: "unknown field 'project', did you mean 'projects'?"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			repo := newTestRepository(tt, test.wsConfig, nil)
+
+			for path, contents := range test.files {
+				writeTestFile(tt, repo, path, contents)
+			}
+
+			_, err := repo.Load("test")
+
+			assert.Equal(tt, test.expectErr, err)
+		})
+	}
+}
+
+func Test_Load_InterpolatesVariables(t *testing.T) {
+	repo := newTestRepository(t, `name: ${WS_NAME:-fallback}
+projects:
+  - name: api
+overlays:
+  network:
+    enabled: ${NETWORK_ENABLED:-false}
+    createIn: api
+    aliasPattern: "$${service}.${DOMAIN}"
+`, map[string]string{
+		"api": `properties:
+  - name: domain
+    default: api.test
+composeFiles:
+  primary: ${COMPOSE_FILE:-docker-compose.yaml}
+hosts:
+  - ${domain}
+  - admin.${domain}
+`,
+	})
+
+	env := map[string]string{
+		"WS_NAME":         "test",
+		"NETWORK_ENABLED": "true",
+		"DOMAIN":          "example.com",
+		"domain":          "",
+	}
+
+	repo.lookupEnv = func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	ws, err := repo.Load("test")
+	require.Nil(t, err)
+
+	assert.Equal(t, "test", ws.Name)
+	assert.True(t, ws.OverlayConfig.Network.Enabled)
+	assert.Equal(t, "${service}.example.com", ws.OverlayConfig.Network.AliasPattern)
+
+	api := ws.Projects[0].Config
+	assert.Equal(t, "docker-compose.yaml", api.ComposeFiles.Primary)
+	assert.Equal(t, []string{"api.test", "admin.api.test"}, api.Hosts)
+}
+
+func Test_Load_MergesLocalOverrides(t *testing.T) {
+	repo := newTestRepository(t, `name: test
+projects:
+  - name: api
+overlays:
+  network:
+    enabled: true
+    createIn: api
+`, map[string]string{
+		"api": `composeFiles:
+  primary: docker-compose.yaml
+hosts:
+  - api.test
+`,
+	})
+
+	writeTestFile(t, repo, "/ws/orca.workspace.local.yaml", `overlays:
+  network:
+    aliasPattern: "{{ .Service }}.localhost"
+`)
+	writeTestFile(t, repo, "/projects/api/orca.project.local.yaml", `composeFiles:
+  primary: docker-compose.mac.yaml
+hosts:
+  - api.localhost
+`)
+
+	ws, err := repo.Load("test")
+	require.Nil(t, err)
+
+	assert.True(t, ws.OverlayConfig.Network.Enabled)
+	assert.Equal(t, "{{ .Service }}.localhost", ws.OverlayConfig.Network.AliasPattern)
+	assert.Equal(t, "docker-compose.mac.yaml", ws.Projects[0].Config.ComposeFiles.Primary)
+	assert.Equal(t, []string{"api.test", "api.localhost"}, ws.Projects[0].Config.Hosts)
+
+	writeTestFile(t, repo, "/ws/orca.workspace.local.yaml", `overlay:
+  network:
+    enabled: false
+`)
+
+	_, err = repo.Load("test")
+	assert.Equal(t, common.ErrInvalidConfig{
+		Path: "/ws/orca.workspace.local.yaml",
+		Issues: []common.ConfigIssue{
+			{Line: 1, Column: 1, Message: "unknown field 'overlay', did you mean 'overlays'?"},
+		},
+	}, err)
+}
+
+func Test_DescribeConfig(t *testing.T) {
+	repo := newTestRepository(t, `name: test
+include:
+  - base.yaml
+projects:
+  - name: api
+`, map[string]string{
+		"api": validProjectConfig,
+	})
+
+	writeTestFile(t, repo, "/ws/base.yaml", `overlays:
+  network:
+    enabled: true
+    createIn: api
+`)
+	writeTestFile(t, repo, "/ws/orca.workspace.local.yaml", `overlays:
+  network:
+    enabled: false
+`)
+
+	contents, err := repo.DescribeConfig("test", "")
+	require.Nil(t, err)
+
+	assert.Equal(t, `overlays:
+    network:
+        enabled: false # /ws/orca.workspace.local.yaml:3
+        createIn: api # /ws/base.yaml:4
+name: test # /ws/orca.workspace.yaml:1
+projects:
+    - name: api # /ws/orca.workspace.yaml:5
+`, string(contents))
+
+	contents, err = repo.DescribeConfig("test", "api")
+	require.Nil(t, err)
+
+	assert.Equal(t, `composeFiles:
+    primary: docker-compose.yaml # /projects/api/orca.project.yaml:2
+`, string(contents))
+}
@@ -0,0 +1,287 @@
+package snapshots
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/spf13/afero"
+)
+
+const (
+	nameFormat = "20060102-150405"
+
+	manifestFile = "manifest.json"
+
+	archiveExt = ".tar.gz"
+
+	// replacedSuffix is given to a snapshot while it's being replaced. The
+	// name is hidden, so it's never listed.
+	replacedSuffix = ".replaced"
+)
+
+// validName keeps names to a single directory, that's easy to type.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Manifest describes what's in a snapshot. It's written once every volume has
+// been saved, so a snapshot without one is incomplete.
+type Manifest struct {
+	Created time.Time `json:"created"`
+
+	// Projects maps each project to the keys of the volumes saved from it.
+	Projects map[string][]string `json:"projects"`
+}
+
+type Snapshot struct {
+	Name string
+	Manifest
+
+	// Size is the total size of the archives, in bytes.
+	Size int64
+}
+
+// Store keeps snapshots of volumes on disk, in <dir>/<ws>/<name>, with a
+// gzipped tar for each volume in a directory per project.
+type Store struct {
+	fs  afero.Fs
+	dir string
+	now func() time.Time
+}
+
+// NewName names a snapshot after when it was taken.
+func (s *Store) NewName() string {
+	return s.now().Format(nameFormat)
+}
+
+// TempName names a snapshot that's taken to replace an existing one, so the
+// existing one is kept until the new one is complete.
+func (s *Store) TempName(name string) string {
+	return fmt.Sprintf("%s.partial-%d", name, s.now().UnixNano())
+}
+
+func (s *Store) snapshotDir(wsName string, name string) string {
+	return filepath.Join(s.dir, wsName, name)
+}
+
+func (s *Store) archivePath(wsName string, name string, project string, key string) string {
+	return filepath.Join(s.snapshotDir(wsName, name), project, key+archiveExt)
+}
+
+func checkName(name string) error {
+	if !validName.MatchString(name) {
+		return common.ErrInvalidInput{
+			To:  "data.snapshot.name",
+			Msg: fmt.Sprintf("'%s' is not a valid snapshot name, only letters, numbers, '.', '_' and '-' can be used", name),
+		}
+	}
+
+	return nil
+}
+
+// Exists reports whether anything has been saved under the name, even if the
+// snapshot wasn't completed.
+func (s *Store) Exists(wsName string, name string) (bool, error) {
+	if err := checkName(name); err != nil {
+		return false, err
+	}
+
+	return afero.Exists(s.fs, s.snapshotDir(wsName, name))
+}
+
+// Create opens the archive for a volume, to be written to.
+func (s *Store) Create(wsName string, name string, project string, key string) (io.WriteCloser, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+
+	path := s.archivePath(wsName, name, project, key)
+
+	if err := s.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	return s.fs.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+}
+
+// Save completes the snapshot, once the volumes of each project have been
+// written.
+func (s *Store) Save(wsName string, name string, projects map[string][]string) error {
+	// The directory won't exist yet if there weren't any volumes to save
+	if err := s.fs.MkdirAll(s.snapshotDir(wsName, name), 0755); err != nil {
+		return err
+	}
+
+	contents, err := json.MarshalIndent(Manifest{
+		Created:  s.now(),
+		Projects: projects,
+	}, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(s.fs, filepath.Join(s.snapshotDir(wsName, name), manifestFile), contents, 0644)
+}
+
+func (s *Store) size(wsName string, name string) (int64, error) {
+	var size int64
+
+	err := afero.Walk(s.fs, s.snapshotDir(wsName, name), func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
+
+// Load reads a completed snapshot.
+func (s *Store) Load(wsName string, name string) (Snapshot, error) {
+	if err := checkName(name); err != nil {
+		return Snapshot{}, err
+	}
+
+	contents, err := afero.ReadFile(s.fs, filepath.Join(s.snapshotDir(wsName, name), manifestFile))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Snapshot{}, common.ErrUnknownSnapshot{
+				Workspace: wsName,
+				Name:      name,
+			}
+		}
+
+		return Snapshot{}, err
+	}
+
+	snapshot := Snapshot{
+		Name: name,
+	}
+
+	if err := json.Unmarshal(contents, &snapshot.Manifest); err != nil {
+		return Snapshot{}, fmt.Errorf("snapshot '%s' has an invalid manifest: %w", name, err)
+	}
+
+	if snapshot.Size, err = s.size(wsName, name); err != nil {
+		return Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+// Open opens the archive of a volume in the snapshot, to be read from.
+func (s *Store) Open(wsName string, name string, project string, key string) (io.ReadCloser, error) {
+	return s.fs.Open(s.archivePath(wsName, name, project, key))
+}
+
+// List returns the completed snapshots of the workspace, oldest first.
+func (s *Store) List(wsName string) ([]Snapshot, error) {
+	entries, err := afero.ReadDir(s.fs, filepath.Join(s.dir, wsName))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Snapshot{}, nil
+		}
+
+		return nil, err
+	}
+
+	snapshots := []Snapshot{}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		snapshot, err := s.Load(wsName, e.Name())
+
+		if err != nil {
+			// Incomplete, or not a snapshot at all
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	slices.SortStableFunc(snapshots, func(a Snapshot, b Snapshot) int {
+		return a.Created.Compare(b.Created)
+	})
+
+	return snapshots, nil
+}
+
+// Replace moves the completed snapshot from over the one named to. The old
+// snapshot is moved aside rather than removed, until the new one is in its
+// place, so it isn't lost if that fails.
+func (s *Store) Replace(wsName string, from string, to string) error {
+	if err := checkName(from); err != nil {
+		return err
+	}
+
+	if err := checkName(to); err != nil {
+		return err
+	}
+
+	dir := s.snapshotDir(wsName, to)
+	aside := filepath.Join(s.dir, wsName, "."+to+replacedSuffix)
+
+	// Only left behind if an earlier replace was interrupted, after the new
+	// snapshot had been moved into place
+	if err := s.fs.RemoveAll(aside); err != nil {
+		return err
+	}
+
+	exists, err := afero.Exists(s.fs, dir)
+
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		if err := s.fs.Rename(dir, aside); err != nil {
+			return err
+		}
+	}
+
+	if err := s.fs.Rename(s.snapshotDir(wsName, from), dir); err != nil {
+		if exists {
+			if restoreErr := s.fs.Rename(aside, dir); restoreErr != nil {
+				return errors.Join(err, restoreErr)
+			}
+		}
+
+		return err
+	}
+
+	return s.fs.RemoveAll(aside)
+}
+
+// Remove deletes the snapshot, whether or not it was completed.
+func (s *Store) Remove(wsName string, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+
+	return s.fs.RemoveAll(s.snapshotDir(wsName, name))
+}
+
+func NewStore(fs afero.Fs, dir string) *Store {
+	return &Store{
+		fs:  fs,
+		dir: dir,
+		now: time.Now,
+	}
+}
@@ -0,0 +1,160 @@
+package snapshots
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore ticks the clock forward a minute every time it's read, so the
+// snapshots have a known order.
+func newTestStore() *Store {
+	s := NewStore(afero.NewMemMapFs(), "/snapshots")
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	s.now = func() time.Time {
+		now = now.Add(time.Minute)
+
+		return now
+	}
+
+	return s
+}
+
+func writeArchive(t *testing.T, s *Store, name string, project string, key string, contents string) {
+	w, err := s.Create("test", name, project, key)
+	require.Nil(t, err)
+
+	_, err = io.WriteString(w, contents)
+	require.Nil(t, err)
+	require.Nil(t, w.Close())
+}
+
+func Test_Store_SaveAndLoad(t *testing.T) {
+	s := newTestStore()
+
+	writeArchive(t, s, "seeded", "api", "pgdata", "12345")
+	writeArchive(t, s, "seeded", "api", "cache", "678")
+	require.Nil(t, s.Save("test", "seeded", map[string][]string{"api": {"cache", "pgdata"}}))
+
+	snapshot, err := s.Load("test", "seeded")
+
+	require.Nil(t, err)
+	assert.Equal(t, "seeded", snapshot.Name)
+	assert.Equal(t, map[string][]string{"api": {"cache", "pgdata"}}, snapshot.Projects)
+	assert.Equal(t, time.Date(2026, 1, 2, 15, 5, 5, 0, time.UTC), snapshot.Created.UTC())
+	assert.Greater(t, snapshot.Size, int64(8))
+
+	r, err := s.Open("test", "seeded", "api", "pgdata")
+	require.Nil(t, err)
+	defer r.Close()
+
+	contents, err := io.ReadAll(r)
+	require.Nil(t, err)
+	assert.Equal(t, "12345", string(contents))
+}
+
+func Test_Store_List(t *testing.T) {
+	s := newTestStore()
+
+	require.Nil(t, s.Save("test", "zzz-first", map[string][]string{"api": {}}))
+	require.Nil(t, s.Save("test", "aaa-second", map[string][]string{"api": {}}))
+
+	// Never completed, so it can't be restored
+	writeArchive(t, s, "incomplete", "api", "pgdata", "12345")
+
+	snapshots, err := s.List("test")
+
+	require.Nil(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "zzz-first", snapshots[0].Name)
+	assert.Equal(t, "aaa-second", snapshots[1].Name)
+
+	exists, err := s.Exists("test", "incomplete")
+	require.Nil(t, err)
+	assert.True(t, exists)
+
+	require.Nil(t, s.Remove("test", "incomplete"))
+
+	exists, err = s.Exists("test", "incomplete")
+	require.Nil(t, err)
+	assert.False(t, exists)
+
+	none, err := s.List("other")
+	require.Nil(t, err)
+	assert.Empty(t, none)
+}
+
+func Test_Store_Unknown(t *testing.T) {
+	s := newTestStore()
+
+	writeArchive(t, s, "incomplete", "api", "pgdata", "12345")
+
+	_, err := s.Load("test", "incomplete")
+
+	assert.Equal(t, common.ErrUnknownSnapshot{Workspace: "test", Name: "incomplete"}, err)
+}
+
+func Test_Store_InvalidName(t *testing.T) {
+	s := newTestStore()
+
+	for _, name := range []string{"", "../other", "a/b", ".hidden"} {
+		t.Run(name, func(tt *testing.T) {
+			_, err := s.Exists("test", name)
+
+			assert.IsType(tt, common.ErrInvalidInput{}, err)
+		})
+	}
+}
+
+func Test_Store_Replace(t *testing.T) {
+	s := newTestStore()
+
+	writeArchive(t, s, "seeded", "api", "pgdata", "old")
+	require.Nil(t, s.Save("test", "seeded", map[string][]string{"api": {"pgdata"}}))
+
+	temp := s.TempName("seeded")
+	writeArchive(t, s, temp, "api", "pgdata", "new")
+	require.Nil(t, s.Save("test", temp, map[string][]string{"api": {"pgdata"}}))
+
+	require.Nil(t, s.Replace("test", temp, "seeded"))
+
+	r, err := s.Open("test", "seeded", "api", "pgdata")
+	require.Nil(t, err)
+	defer r.Close()
+
+	contents, err := io.ReadAll(r)
+	require.Nil(t, err)
+	assert.Equal(t, "new", string(contents))
+
+	snapshots, err := s.List("test")
+	require.Nil(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "seeded", snapshots[0].Name)
+
+	entries, err := afero.ReadDir(s.fs, "/snapshots/test")
+	require.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	// Nothing to replace, so it's just moved
+	require.Nil(t, s.Save("test", "other.partial-1", map[string][]string{}))
+	require.Nil(t, s.Replace("test", "other.partial-1", "other"))
+
+	_, err = s.Load("test", "other")
+	assert.Nil(t, err)
+
+	// The new snapshot has gone missing, so the old one is kept
+	assert.NotNil(t, s.Replace("test", "missing", "seeded"))
+
+	_, err = s.Load("test", "seeded")
+	assert.Nil(t, err)
+}
+
+func Test_Store_NewName(t *testing.T) {
+	assert.Equal(t, "20260102-150505", newTestStore().NewName())
+}
@@ -0,0 +1,1013 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A minimal ACME v2 (RFC 8555) server, issuing certificates from the orca root
+// CA. It is only intended to be run locally, so every authorization for a name
+// the workspace knows about is approved automatically; there are no challenges
+// to solve.
+
+const acmeDirectoryPath = "/directory"
+const acmeProblemPrefix = "urn:ietf:params:acme:error:"
+
+const (
+	acmeStatusValid      = "valid"
+	acmeStatusReady      = "ready"
+	acmeStatusProcessing = "processing"
+)
+
+type acmeProblem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+	Status int    `json:"status"`
+}
+
+func (p acmeProblem) Error() string {
+	return fmt.Sprintf("%s: %s", p.Type, p.Detail)
+}
+
+func newAcmeProblem(status int, kind string, detail string) acmeProblem {
+	return acmeProblem{
+		Type:   acmeProblemPrefix + kind,
+		Detail: detail,
+		Status: status,
+	}
+}
+
+type acmeIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type acmeAccount struct {
+	id  string
+	key crypto.PublicKey
+}
+
+type acmeOrder struct {
+	id          string
+	accountID   string
+	status      string
+	expires     time.Time
+	identifiers []acmeIdentifier
+	authzIDs    []string
+	certID      string
+}
+
+type acmeAuthorization struct {
+	id         string
+	accountID  string
+	identifier acmeIdentifier
+	expires    time.Time
+}
+
+type acmeCertificate struct {
+	accountID string
+	chain     []byte
+}
+
+type acmeJWS struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+type acmeProtectedHeader struct {
+	Alg   string          `json:"alg"`
+	Nonce string          `json:"nonce"`
+	URL   string          `json:"url"`
+	Kid   string          `json:"kid"`
+	JWK   json.RawMessage `json:"jwk"`
+}
+
+type acmeJWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// acmeRequest is a verified request, the payload has already been decoded
+// from the JWS. An empty payload signals a POST-as-GET request.
+type acmeRequest struct {
+	account *acmeAccount
+	key     crypto.PublicKey
+	payload []byte
+}
+
+// AcmeServer implements http.Handler for the ACME protocol. It should be
+// created through CertificateManager.BuildAcmeServer.
+type AcmeServer struct {
+	issuer  *issuer
+	allowed []string
+	mux     *http.ServeMux
+
+	mu             sync.Mutex
+	nonces         map[string]struct{}
+	accounts       map[string]*acmeAccount
+	accountsByKey  map[string]string
+	orders         map[string]*acmeOrder
+	authorizations map[string]*acmeAuthorization
+	certs          map[string]*acmeCertificate
+}
+
+func randomAcmeID() string {
+	b := make([]byte, 16)
+	// crypto/rand.Read never returns an error on supported platforms
+	_, _ = rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func acmeBaseURL(r *http.Request) string {
+	scheme := "http"
+
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s", scheme, r.Host)
+}
+
+// isAllowedName reports whether the name may be issued. Names are approved if
+// they are an exact match of an allowed name, or are covered by an allowed
+// wildcard (e.g. "*.example.com" covers "api.example.com").
+func (s *AcmeServer) isAllowedName(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+	for _, allowed := range s.allowed {
+		allowed = strings.ToLower(allowed)
+
+		if allowed == name {
+			return true
+		}
+
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			label, found := strings.CutSuffix(name, suffix)
+
+			if found && label != "" && !strings.Contains(label, ".") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *AcmeServer) newNonce() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := randomAcmeID()
+	s.nonces[n] = struct{}{}
+
+	return n
+}
+
+func (s *AcmeServer) consumeNonce(n string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.nonces[n]; !ok {
+		return false
+	}
+
+	delete(s.nonces, n)
+
+	return true
+}
+
+func (s *AcmeServer) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Replay-Nonce", s.newNonce())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *AcmeServer) writeProblem(w http.ResponseWriter, err error) {
+	problem, ok := err.(acmeProblem)
+
+	if !ok {
+		problem = newAcmeProblem(http.StatusInternalServerError, "serverInternal", err.Error())
+	}
+
+	w.Header().Set("Replay-Nonce", s.newNonce())
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+func decodeJWKInt(v string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+func parseAcmeJWK(raw json.RawMessage) (crypto.PublicKey, error) {
+	jwk := acmeJWK{}
+
+	if err := json.Unmarshal(raw, &jwk); err != nil {
+		return nil, err
+	}
+
+	switch jwk.Kty {
+	case "EC":
+		var curve elliptic.Curve
+
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+
+		x, err := decodeJWKInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeJWKInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "RSA":
+		n, err := decodeJWKInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeJWKInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
+	}
+}
+
+// acmeKeyFingerprint returns a stable identifier for the key, so that
+// re-registering with the same key returns the existing account.
+func acmeKeyFingerprint(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(der)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func verifyAcmeSignature(alg string, key crypto.PublicKey, signed []byte, sig []byte) error {
+	var hash crypto.Hash
+	var digest []byte
+
+	switch alg {
+	case "RS256", "ES256":
+		sum := sha256.Sum256(signed)
+		hash, digest = crypto.SHA256, sum[:]
+	case "ES384":
+		sum := sha512.Sum384(signed)
+		hash, digest = crypto.SHA384, sum[:]
+	case "ES512":
+		sum := sha512.Sum512(signed)
+		hash, digest = crypto.SHA512, sum[:]
+	default:
+		return fmt.Errorf("unsupported algorithm: %s", alg)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			return fmt.Errorf("algorithm %s does not match RSA key", alg)
+		}
+
+		return rsa.VerifyPKCS1v15(k, hash, digest, sig)
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+
+		if len(sig) != size*2 {
+			return errors.New("invalid signature length")
+		}
+
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+
+		if !ecdsa.Verify(k, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	default:
+		return errors.New("unsupported key")
+	}
+}
+
+// verify decodes and validates the JWS that every ACME POST request is
+// wrapped in. When requireAccount is false the key must be embedded in the
+// request (only valid for newAccount), otherwise it must reference an account.
+func (s *AcmeServer) verify(r *http.Request, requireAccount bool) (*acmeRequest, error) {
+	body := acmeJWS{}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, newAcmeProblem(http.StatusBadRequest, "malformed", "request body is not a valid JWS")
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(body.Protected)
+
+	if err != nil {
+		return nil, newAcmeProblem(http.StatusBadRequest, "malformed", "invalid protected header encoding")
+	}
+
+	header := acmeProtectedHeader{}
+
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return nil, newAcmeProblem(http.StatusBadRequest, "malformed", "invalid protected header")
+	}
+
+	if !s.consumeNonce(header.Nonce) {
+		return nil, newAcmeProblem(http.StatusBadRequest, "badNonce", "nonce is invalid or has already been used")
+	}
+
+	if header.URL != acmeBaseURL(r)+r.URL.Path {
+		return nil, newAcmeProblem(http.StatusUnauthorized, "unauthorized", "url in protected header does not match request")
+	}
+
+	req := &acmeRequest{}
+
+	switch {
+	case header.Kid != "" && len(header.JWK) > 0:
+		return nil, newAcmeProblem(http.StatusBadRequest, "malformed", "only one of 'kid' and 'jwk' may be supplied")
+	case header.Kid != "":
+		id := header.Kid[strings.LastIndex(header.Kid, "/")+1:]
+
+		s.mu.Lock()
+		acct, ok := s.accounts[id]
+		s.mu.Unlock()
+
+		if !ok {
+			return nil, newAcmeProblem(http.StatusBadRequest, "accountDoesNotExist", "unknown account")
+		}
+
+		req.account = acct
+		req.key = acct.key
+	case len(header.JWK) > 0:
+		if requireAccount {
+			return nil, newAcmeProblem(http.StatusBadRequest, "malformed", "request must be signed with an account 'kid'")
+		}
+
+		key, err := parseAcmeJWK(header.JWK)
+
+		if err != nil {
+			return nil, newAcmeProblem(http.StatusBadRequest, "badPublicKey", err.Error())
+		}
+
+		req.key = key
+	default:
+		return nil, newAcmeProblem(http.StatusBadRequest, "malformed", "one of 'kid' or 'jwk' must be supplied")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(body.Signature)
+
+	if err != nil {
+		return nil, newAcmeProblem(http.StatusBadRequest, "malformed", "invalid signature encoding")
+	}
+
+	if err := verifyAcmeSignature(header.Alg, req.key, []byte(body.Protected+"."+body.Payload), sig); err != nil {
+		return nil, newAcmeProblem(http.StatusBadRequest, "badSignatureAlgorithm", err.Error())
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(body.Payload)
+
+	if err != nil {
+		return nil, newAcmeProblem(http.StatusBadRequest, "malformed", "invalid payload encoding")
+	}
+
+	req.payload = payload
+
+	return req, nil
+}
+
+func (s *AcmeServer) handleDirectory(w http.ResponseWriter, r *http.Request) {
+	base := acmeBaseURL(r)
+
+	s.writeJSON(w, http.StatusOK, map[string]string{
+		"newNonce":   base + "/acme/new-nonce",
+		"newAccount": base + "/acme/new-account",
+		"newOrder":   base + "/acme/new-order",
+	})
+}
+
+func (s *AcmeServer) handleNewNonce(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Replay-Nonce", s.newNonce())
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.Method == http.MethodGet {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *AcmeServer) accountBody(acct *acmeAccount, r *http.Request) map[string]any {
+	return map[string]any{
+		"status": acmeStatusValid,
+		"orders": fmt.Sprintf("%s/acme/account/%s/orders", acmeBaseURL(r), acct.id),
+	}
+}
+
+func (s *AcmeServer) handleNewAccount(w http.ResponseWriter, r *http.Request) {
+	req, err := s.verify(r, false)
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	payload := struct {
+		OnlyReturnExisting bool `json:"onlyReturnExisting"`
+	}{}
+
+	if len(req.payload) > 0 {
+		if err := json.Unmarshal(req.payload, &payload); err != nil {
+			s.writeProblem(w, newAcmeProblem(http.StatusBadRequest, "malformed", "invalid account payload"))
+			return
+		}
+	}
+
+	fingerprint, err := acmeKeyFingerprint(req.key)
+
+	if err != nil {
+		s.writeProblem(w, newAcmeProblem(http.StatusBadRequest, "badPublicKey", err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	id, exists := s.accountsByKey[fingerprint]
+
+	if !exists && !payload.OnlyReturnExisting {
+		id = randomAcmeID()
+		s.accounts[id] = &acmeAccount{
+			id:  id,
+			key: req.key,
+		}
+		s.accountsByKey[fingerprint] = id
+	}
+	acct := s.accounts[id]
+	s.mu.Unlock()
+
+	if acct == nil {
+		s.writeProblem(w, newAcmeProblem(http.StatusBadRequest, "accountDoesNotExist", "no account exists for this key"))
+		return
+	}
+
+	status := http.StatusCreated
+
+	if exists {
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/acme/account/%s", acmeBaseURL(r), acct.id))
+	s.writeJSON(w, status, s.accountBody(acct, r))
+}
+
+func (s *AcmeServer) handleAccount(w http.ResponseWriter, r *http.Request) {
+	req, err := s.verify(r, true)
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	if req.account.id != r.PathValue("id") {
+		s.writeProblem(w, newAcmeProblem(http.StatusUnauthorized, "unauthorized", "account does not belong to the requester"))
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, s.accountBody(req.account, r))
+}
+
+func (s *AcmeServer) orderBody(o *acmeOrder, r *http.Request) map[string]any {
+	base := acmeBaseURL(r)
+
+	authzs := make([]string, len(o.authzIDs))
+	for i, id := range o.authzIDs {
+		authzs[i] = fmt.Sprintf("%s/acme/authz/%s", base, id)
+	}
+
+	body := map[string]any{
+		"status":         o.status,
+		"expires":        o.expires.Format(time.RFC3339),
+		"identifiers":    o.identifiers,
+		"authorizations": authzs,
+		"finalize":       fmt.Sprintf("%s/acme/finalize/%s", base, o.id),
+	}
+
+	if o.certID != "" {
+		body["certificate"] = fmt.Sprintf("%s/acme/cert/%s", base, o.certID)
+	}
+
+	return body
+}
+
+func (s *AcmeServer) handleNewOrder(w http.ResponseWriter, r *http.Request) {
+	req, err := s.verify(r, true)
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	payload := struct {
+		Identifiers []acmeIdentifier `json:"identifiers"`
+	}{}
+
+	if err := json.Unmarshal(req.payload, &payload); err != nil || len(payload.Identifiers) == 0 {
+		s.writeProblem(w, newAcmeProblem(http.StatusBadRequest, "malformed", "order must contain identifiers"))
+		return
+	}
+
+	for _, id := range payload.Identifiers {
+		if id.Type != "dns" {
+			s.writeProblem(w, newAcmeProblem(http.StatusBadRequest, "unsupportedIdentifier", fmt.Sprintf("identifier type '%s' is not supported", id.Type)))
+			return
+		}
+
+		if !s.isAllowedName(id.Value) {
+			s.writeProblem(w, newAcmeProblem(http.StatusForbidden, "rejectedIdentifier", fmt.Sprintf("'%s' is not a host or certificate in the workspace", id.Value)))
+			return
+		}
+	}
+
+	expires := time.Now().Add(24 * time.Hour)
+	order := &acmeOrder{
+		id:          randomAcmeID(),
+		accountID:   req.account.id,
+		status:      acmeStatusReady,
+		expires:     expires,
+		identifiers: payload.Identifiers,
+	}
+
+	s.mu.Lock()
+	for _, id := range payload.Identifiers {
+		authz := &acmeAuthorization{
+			id:         randomAcmeID(),
+			accountID:  req.account.id,
+			identifier: id,
+			expires:    expires,
+		}
+
+		s.authorizations[authz.id] = authz
+		order.authzIDs = append(order.authzIDs, authz.id)
+	}
+	s.orders[order.id] = order
+	s.mu.Unlock()
+
+	w.Header().Set("Location", fmt.Sprintf("%s/acme/order/%s", acmeBaseURL(r), order.id))
+	s.writeJSON(w, http.StatusCreated, s.orderBody(order, r))
+}
+
+func (s *AcmeServer) findOrder(req *acmeRequest, id string) (*acmeOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[id]
+
+	if !ok || order.accountID != req.account.id {
+		return nil, newAcmeProblem(http.StatusNotFound, "malformed", "order not found")
+	}
+
+	return order, nil
+}
+
+func (s *AcmeServer) handleOrder(w http.ResponseWriter, r *http.Request) {
+	req, err := s.verify(r, true)
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	order, err := s.findOrder(req, r.PathValue("id"))
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/acme/order/%s", acmeBaseURL(r), order.id))
+	s.writeJSON(w, http.StatusOK, s.orderBody(order, r))
+}
+
+func (s *AcmeServer) findAuthorization(req *acmeRequest, id string) (*acmeAuthorization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authz, ok := s.authorizations[id]
+
+	if !ok || authz.accountID != req.account.id {
+		return nil, newAcmeProblem(http.StatusNotFound, "malformed", "authorization not found")
+	}
+
+	return authz, nil
+}
+
+func (s *AcmeServer) handleAuthorization(w http.ResponseWriter, r *http.Request) {
+	req, err := s.verify(r, true)
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	authz, err := s.findAuthorization(req, r.PathValue("id"))
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	// Authorizations are pre-approved, but clients still expect to see at least
+	// one challenge, so report a challenge that has already been validated.
+	s.writeJSON(w, http.StatusOK, map[string]any{
+		"status":     acmeStatusValid,
+		"expires":    authz.expires.Format(time.RFC3339),
+		"identifier": authz.identifier,
+		"wildcard":   strings.HasPrefix(authz.identifier.Value, "*."),
+		"challenges": []map[string]string{
+			{
+				"type":   "http-01",
+				"url":    fmt.Sprintf("%s/acme/authz/%s", acmeBaseURL(r), authz.id),
+				"token":  authz.id,
+				"status": acmeStatusValid,
+			},
+		},
+	})
+}
+
+// startFinalizing moves a ready order on to processing, so that it can only be
+// finalized once. Expired orders can't be finalized at all.
+func (s *AcmeServer) startFinalizing(order *acmeOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if order.status != acmeStatusReady {
+		return newAcmeProblem(http.StatusForbidden, "orderNotReady", fmt.Sprintf("order is %s, not %s", order.status, acmeStatusReady))
+	}
+
+	if time.Now().After(order.expires) {
+		return newAcmeProblem(http.StatusForbidden, "orderNotReady", "order has expired")
+	}
+
+	order.status = acmeStatusProcessing
+
+	return nil
+}
+
+func (s *AcmeServer) handleFinalize(w http.ResponseWriter, r *http.Request) {
+	req, err := s.verify(r, true)
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	order, err := s.findOrder(req, r.PathValue("id"))
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	payload := struct {
+		CSR string `json:"csr"`
+	}{}
+
+	if err := json.Unmarshal(req.payload, &payload); err != nil {
+		s.writeProblem(w, newAcmeProblem(http.StatusBadRequest, "malformed", "invalid finalize payload"))
+		return
+	}
+
+	der, err := base64.RawURLEncoding.DecodeString(payload.CSR)
+
+	if err != nil {
+		s.writeProblem(w, newAcmeProblem(http.StatusBadRequest, "badCSR", "invalid csr encoding"))
+		return
+	}
+
+	csr, err := x509.ParseCertificateRequest(der)
+
+	if err == nil {
+		err = csr.CheckSignature()
+	}
+
+	if err != nil {
+		s.writeProblem(w, newAcmeProblem(http.StatusBadRequest, "badCSR", err.Error()))
+		return
+	}
+
+	ordered := make([]string, len(order.identifiers))
+	for i, id := range order.identifiers {
+		ordered[i] = id.Value
+	}
+
+	names := slices.Clone(csr.DNSNames)
+	if csr.Subject.CommonName != "" && !slices.Contains(names, csr.Subject.CommonName) {
+		names = append(names, csr.Subject.CommonName)
+	}
+
+	for _, n := range names {
+		if !slices.Contains(ordered, n) {
+			s.writeProblem(w, newAcmeProblem(http.StatusForbidden, "badCSR", fmt.Sprintf("'%s' was not part of the order", n)))
+			return
+		}
+	}
+
+	cn := csr.Subject.CommonName
+	if cn == "" {
+		cn = ordered[0]
+	}
+
+	if err := s.startFinalizing(order); err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	cert, err := s.issue(csr.PublicKey, cn, ordered)
+
+	if err != nil {
+		s.mu.Lock()
+		order.status = acmeStatusReady
+		s.mu.Unlock()
+
+		s.writeProblem(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	certID := randomAcmeID()
+	s.certs[certID] = &acmeCertificate{
+		accountID: req.account.id,
+		chain:     cert,
+	}
+	order.certID = certID
+	order.status = acmeStatusValid
+	s.mu.Unlock()
+
+	w.Header().Set("Location", fmt.Sprintf("%s/acme/order/%s", acmeBaseURL(r), order.id))
+	s.writeJSON(w, http.StatusOK, s.orderBody(order, r))
+}
+
+func (s *AcmeServer) findCertificate(req *acmeRequest, id string) (*acmeCertificate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cert, ok := s.certs[id]
+
+	if !ok || cert.accountID != req.account.id {
+		return nil, newAcmeProblem(http.StatusNotFound, "malformed", "certificate not found")
+	}
+
+	return cert, nil
+}
+
+func (s *AcmeServer) handleCertificate(w http.ResponseWriter, r *http.Request) {
+	req, err := s.verify(r, true)
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	cert, err := s.findCertificate(req, r.PathValue("id"))
+
+	if err != nil {
+		s.writeProblem(w, err)
+		return
+	}
+
+	w.Header().Set("Replay-Nonce", s.newNonce())
+	w.Header().Set("Content-Type", "application/pem-certificate-chain")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(cert.chain)
+}
+
+// issue signs a certificate for the given names, returning the PEM encoded
+// chain of the leaf followed by the orca root.
+func (s *AcmeServer) issue(pub crypto.PublicKey, cn string, names []string) ([]byte, error) {
+	der, err := s.issuer.signLeaf(pub, cn, names)
+
+	if err != nil {
+		return nil, err
+	}
+
+	chain := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: der,
+	})
+
+	return append(chain, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: s.issuer.cert.Raw,
+	})...), nil
+}
+
+// ServingCertificate creates an in-memory certificate for the ACME server
+// itself, so that it can be served over HTTPS to clients that trust the orca
+// root.
+func (s *AcmeServer) ServingCertificate(hosts []string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	dnsNames := []string{}
+	ips := []net.IP{}
+
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			ips = append(ips, ip)
+			continue
+		}
+
+		dnsNames = append(dnsNames, h)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		DNSNames:              dnsNames,
+		IPAddresses:           ips,
+		SerialNumber:          serial,
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(0, 0, 30),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, s.issuer.cert, key.Public(), s.issuer.key)
+
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der, s.issuer.cert.Raw},
+		PrivateKey:  key,
+	}, nil
+}
+
+func (s *AcmeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func newAcmeServer(issuer *issuer, allowed []string) *AcmeServer {
+	s := &AcmeServer{
+		issuer:         issuer,
+		allowed:        allowed,
+		mux:            http.NewServeMux(),
+		nonces:         map[string]struct{}{},
+		accounts:       map[string]*acmeAccount{},
+		accountsByKey:  map[string]string{},
+		orders:         map[string]*acmeOrder{},
+		authorizations: map[string]*acmeAuthorization{},
+		certs:          map[string]*acmeCertificate{},
+	}
+
+	s.mux.HandleFunc("GET "+acmeDirectoryPath, s.handleDirectory)
+	s.mux.HandleFunc("HEAD /acme/new-nonce", s.handleNewNonce)
+	s.mux.HandleFunc("GET /acme/new-nonce", s.handleNewNonce)
+	s.mux.HandleFunc("POST /acme/new-account", s.handleNewAccount)
+	s.mux.HandleFunc("POST /acme/account/{id}", s.handleAccount)
+	s.mux.HandleFunc("POST /acme/new-order", s.handleNewOrder)
+	s.mux.HandleFunc("POST /acme/order/{id}", s.handleOrder)
+	s.mux.HandleFunc("POST /acme/authz/{id}", s.handleAuthorization)
+	s.mux.HandleFunc("POST /acme/finalize/{id}", s.handleFinalize)
+	s.mux.HandleFunc("POST /acme/cert/{id}", s.handleCertificate)
+
+	return s
+}
+
+// BuildAcmeServer creates an ACME server for the given workspace, that will
+// approve any of the hosts or TLS certificates configured within it.
+func (cm *CertificateManager) BuildAcmeServer(wsName string) (*AcmeServer, error) {
+	if err := cm.createTLSDirsIfMissing(); err != nil {
+		return nil, cm.tui.RecordIfError(fmt.Sprintf("Failed to create certificates directory at: %s", cm.GetCertsDir()), err)
+	}
+
+	issuer, err := cm.getRootIssuer()
+
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := cm.workspaceRepo.Load(wsName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := ws.GetUniqueHosts()
+
+	for _, c := range ws.GetUniqueTLSCertificates() {
+		if !slices.Contains(allowed, c) {
+			allowed = append(allowed, c)
+		}
+	}
+
+	return newAcmeServer(issuer, allowed), nil
+}
+
+type ServeAcmeDTO struct {
+	WorkspaceName string
+	Address       string
+}
+
+// ServeAcme runs the ACME server over HTTPS until it is stopped. The server
+// certificate is signed by the orca root, and is valid for localhost as well
+// as host.docker.internal so that containers can reach it too.
+func (cm *CertificateManager) ServeAcme(dto ServeAcmeDTO) error {
+	srv, err := cm.BuildAcmeServer(dto.WorkspaceName)
+
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(dto.Address)
+
+	if err != nil {
+		return cm.tui.RecordIfError(fmt.Sprintf("Invalid address: %s", dto.Address), err)
+	}
+
+	hosts := []string{"localhost", "127.0.0.1", "::1", "host.docker.internal"}
+	if host != "" && !slices.Contains(hosts, host) {
+		hosts = append(hosts, host)
+	}
+
+	cert, err := srv.ServingCertificate(hosts)
+
+	if err != nil {
+		return cm.tui.RecordIfError("Failed to create certificate for the ACME server", err)
+	}
+
+	listener, err := tls.Listen("tcp", dto.Address, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	})
+
+	if err != nil {
+		return cm.tui.RecordIfError(fmt.Sprintf("Failed to listen on %s", dto.Address), err)
+	}
+
+	cm.tui.NewLine()
+	cm.tui.Success(fmt.Sprintf("ACME directory available at: https://%s%s", listener.Addr().String(), acmeDirectoryPath))
+	cm.tui.Info(fmt.Sprintf("Clients must trust the orca root certificate: %s", cm.GetRootCertPath()))
+
+	return cm.tui.RecordIfError("ACME server stopped unexpectedly", http.Serve(listener, srv))
+}
@@ -0,0 +1,228 @@
+package tls_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/tls"
+	tls_mocks "github.com/panoptescloud/orca/tests/mocks/tls"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme"
+)
+
+func buildAcmeTestServer(t *testing.T) (*httptest.Server, *x509.Certificate) {
+	tui := tls_mocks.NewMockTui(t)
+	tui.EXPECT().Info(mock.Anything).Maybe()
+	tui.EXPECT().Success(mock.Anything).Maybe()
+
+	wsRepo := tls_mocks.NewMockWorkspaceRepo(t)
+	wsRepo.EXPECT().Load("test").Return(&common.Workspace{
+		Projects: []common.Project{
+			{
+				Config: common.ProjectConfig{
+					Hosts: []string{
+						"api.test",
+					},
+					TLSCertificates: []string{
+						"*.example.com",
+					},
+				},
+			},
+		},
+	}, nil)
+
+	fs := afero.NewMemMapFs()
+	cm := tls.NewCertificateManager(fs, wsRepo, tui, "/some/path")
+
+	handler, err := cm.BuildAcmeServer("test")
+	require.Nil(t, err)
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	contents, err := afero.ReadFile(fs, cm.GetRootCertPath())
+	require.Nil(t, err)
+
+	block, _ := pem.Decode(contents)
+	root, err := x509.ParseCertificate(block.Bytes)
+	require.Nil(t, err)
+
+	return srv, root
+}
+
+func newAcmeTestClient(t *testing.T, srv *httptest.Server) *acme.Client {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	client := &acme.Client{
+		Key:          key,
+		DirectoryURL: srv.URL + "/directory",
+	}
+
+	_, err = client.Register(context.Background(), &acme.Account{}, acme.AcceptTOS)
+	require.Nil(t, err)
+
+	return client
+}
+
+func Test_AcmeServer_IssuesCertificate(t *testing.T) {
+	tests := []struct {
+		name    string
+		domains []string
+	}{
+		{
+			name:    "exact host",
+			domains: []string{"api.test"},
+		},
+		{
+			name:    "covered by wildcard",
+			domains: []string{"web.example.com"},
+		},
+		{
+			name:    "multiple names",
+			domains: []string{"api.test", "*.example.com"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			srv, root := buildAcmeTestServer(tt)
+			client := newAcmeTestClient(tt, srv)
+			ctx := context.Background()
+
+			order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(test.domains...))
+			require.Nil(tt, err)
+			assert.Equal(tt, acme.StatusReady, order.Status)
+
+			for _, u := range order.AuthzURLs {
+				authz, err := client.GetAuthorization(ctx, u)
+				require.Nil(tt, err)
+				assert.Equal(tt, acme.StatusValid, authz.Status)
+			}
+
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.Nil(tt, err)
+
+			csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+				Subject:  pkix.Name{CommonName: test.domains[0]},
+				DNSNames: test.domains,
+			}, key)
+			require.Nil(tt, err)
+
+			chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+			require.Nil(tt, err)
+			require.Len(tt, chain, 2)
+
+			leaf, err := x509.ParseCertificate(chain[0])
+			require.Nil(tt, err)
+			assert.ElementsMatch(tt, test.domains, leaf.DNSNames)
+
+			roots := x509.NewCertPool()
+			roots.AddCert(root)
+
+			_, err = leaf.Verify(x509.VerifyOptions{
+				Roots:   roots,
+				DNSName: test.domains[0],
+			})
+			assert.Nil(tt, err)
+
+			// The order is valid now, so can't be used for another certificate
+			_, _, err = client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+
+			acmeErr, ok := err.(*acme.Error)
+			require.True(tt, ok, "expected an acme error, got: %v", err)
+			assert.Equal(tt, "urn:ietf:params:acme:error:orderNotReady", acmeErr.ProblemType)
+		})
+	}
+}
+
+func Test_AcmeServer_RejectsUnknownNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		domains []string
+	}{
+		{
+			name:    "unknown host",
+			domains: []string{"evil.test"},
+		},
+		{
+			name:    "nested below wildcard",
+			domains: []string{"a.b.example.com"},
+		},
+		{
+			name:    "one of many unknown",
+			domains: []string{"api.test", "evil.test"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			srv, _ := buildAcmeTestServer(tt)
+			client := newAcmeTestClient(tt, srv)
+
+			_, err := client.AuthorizeOrder(context.Background(), acme.DomainIDs(test.domains...))
+
+			acmeErr, ok := err.(*acme.Error)
+			require.True(tt, ok, "expected an acme error, got: %v", err)
+			assert.Equal(tt, "urn:ietf:params:acme:error:rejectedIdentifier", acmeErr.ProblemType)
+		})
+	}
+}
+
+func Test_AcmeServer_ExistingAccount(t *testing.T) {
+	srv, _ := buildAcmeTestServer(t)
+	client := newAcmeTestClient(t, srv)
+
+	_, err := client.Register(context.Background(), &acme.Account{}, acme.AcceptTOS)
+	assert.Equal(t, acme.ErrAccountAlreadyExists, err)
+
+	acct, err := client.GetReg(context.Background(), "")
+	require.Nil(t, err)
+	assert.Equal(t, acme.StatusValid, acct.Status)
+}
+
+func Test_AcmeServer_OtherAccounts(t *testing.T) {
+	srv, _ := buildAcmeTestServer(t)
+	owner := newAcmeTestClient(t, srv)
+	other := newAcmeTestClient(t, srv)
+	ctx := context.Background()
+
+	order, err := owner.AuthorizeOrder(ctx, acme.DomainIDs("api.test"))
+	require.Nil(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		DNSNames: []string{"api.test"},
+	}, key)
+	require.Nil(t, err)
+
+	_, certURL, err := owner.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	require.Nil(t, err)
+
+	_, err = other.GetOrder(ctx, order.URI)
+	assert.NotNil(t, err)
+
+	for _, u := range order.AuthzURLs {
+		_, err := other.GetAuthorization(ctx, u)
+		assert.NotNil(t, err)
+	}
+
+	_, err = other.FetchCert(ctx, certURL, true)
+	assert.NotNil(t, err)
+
+	_, err = owner.FetchCert(ctx, certURL, true)
+	assert.Nil(t, err)
+}
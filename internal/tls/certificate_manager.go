@@ -22,6 +22,7 @@ import (
 	"math"
 	"math/big"
 	"net"
+	"os"
 	"strings"
 	"time"
 
@@ -282,12 +283,48 @@ func (cm *CertificateManager) createTLSDirsIfMissing() error {
 	return cm.fs.MkdirAll(cm.GetCertsDir(), 0700)
 }
 
-func (cm *CertificateManager) generateCertificateIfNotPresent(issuer *issuer, domain string) error {
+// signLeaf issues a server certificate for the given DNS names, returning the
+// DER encoded certificate.
+func (i *issuer) signLeaf(pub crypto.PublicKey, cn string, dnsNames []string) ([]byte, error) {
+	serial, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		DNSNames:    dnsNames,
+		IPAddresses: []net.IP{},
+		Subject: pkix.Name{
+			CommonName: cn,
+		},
+		SerialNumber: serial,
+		NotBefore:    time.Now(),
+		// Set the validity period to 2 years and 30 days, to satisfy the iOS and
+		// macOS requirements that all server certificates must have validity
+		// shorter than 825 days:
+		// https://derflounder.wordpress.com/2019/06/06/new-tls-security-requirements-for-ios-13-and-macos-catalina-10-15/
+		NotAfter: time.Now().AddDate(2, 0, 30),
 
-	cn := domain
-	var certName = strings.Replace(cn, "*", "_", -1)
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+	}
 
-	keyPath := fmt.Sprintf("%s/%s.key", cm.GetCertsDir(), certName)
+	return x509.CreateCertificate(rand.Reader, template, i.cert, pub, i.key)
+}
+
+// getCertPaths returns the paths of the key and certificate for the domain.
+func (cm *CertificateManager) getCertPaths(domain string) (string, string) {
+	var certName = strings.Replace(domain, "*", "_", -1)
+
+	return fmt.Sprintf("%s/%s.key", cm.GetCertsDir(), certName),
+		fmt.Sprintf("%s/%s.cert", cm.GetCertsDir(), certName)
+}
+
+func (cm *CertificateManager) generateCertificateIfNotPresent(issuer *issuer, domain string) error {
+	keyPath, certPath := cm.getCertPaths(domain)
 
 	key, err := cm.getOrCreateKey(keyPath)
 
@@ -295,8 +332,6 @@ func (cm *CertificateManager) generateCertificateIfNotPresent(issuer *issuer, do
 		return err
 	}
 
-	certPath := fmt.Sprintf("%s/%s.cert", cm.GetCertsDir(), certName)
-
 	returnErrMessage := func(err error) error {
 		return cm.tui.RecordIfError(fmt.Sprintf("Failed to create certificate %s", certPath), err)
 	}
@@ -313,33 +348,8 @@ func (cm *CertificateManager) generateCertificateIfNotPresent(issuer *issuer, do
 	}
 
 	cm.tui.Info(fmt.Sprintf("Creating certificate: %s", certPath))
-	serial, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
 
-	if err != nil {
-		return returnErrMessage(err)
-	}
-
-	template := &x509.Certificate{
-		DNSNames:    []string{domain},
-		IPAddresses: []net.IP{},
-		Subject: pkix.Name{
-			CommonName: domain,
-		},
-		SerialNumber: serial,
-		NotBefore:    time.Now(),
-		// Set the validity period to 2 years and 30 days, to satisfy the iOS and
-		// macOS requirements that all server certificates must have validity
-		// shorter than 825 days:
-		// https://derflounder.wordpress.com/2019/06/06/new-tls-security-requirements-for-ios-13-and-macos-catalina-10-15/
-		NotAfter: time.Now().AddDate(2, 0, 30),
-
-		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
-		BasicConstraintsValid: true,
-		IsCA:                  false,
-	}
-
-	der, err := x509.CreateCertificate(rand.Reader, template, issuer.cert, key.Public(), issuer.key)
+	der, err := issuer.signLeaf(key.Public(), domain, []string{domain})
 
 	if err != nil {
 		return returnErrMessage(err)
@@ -392,6 +402,95 @@ func (cm *CertificateManager) Generate(dto GenerateDTO) error {
 	return nil
 }
 
+func (cm *CertificateManager) getExpiry(path string) (time.Time, error) {
+	exists, err := afero.Exists(cm.fs, path)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !exists {
+		return time.Time{}, common.ErrFileNotFound{
+			Path: path,
+		}
+	}
+
+	cert, err := cm.readCert(path)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return cert.NotAfter, nil
+}
+
+// GetRootCertificateExpiry returns when the root certificate expires, or
+// common.ErrFileNotFound if it hasn't been created yet.
+func (cm *CertificateManager) GetRootCertificateExpiry() (time.Time, error) {
+	return cm.getExpiry(cm.GetRootCertPath())
+}
+
+// GetCertificateExpiry returns when the certificate for the domain expires, or
+// common.ErrFileNotFound if it hasn't been created yet.
+func (cm *CertificateManager) GetCertificateExpiry(domain string) (time.Time, error) {
+	_, certPath := cm.getCertPaths(domain)
+
+	return cm.getExpiry(certPath)
+}
+
+type RemoveUnusedDTO struct {
+	WorkspaceName string
+
+	// OtherWorkspaces are checked, so that any certificates they also use are
+	// kept.
+	OtherWorkspaces []string
+}
+
+// RemoveUnused removes the certificates for the workspace, except for those
+// that another workspace also needs. The root certificate is always kept, as
+// it will have been trusted on the system.
+func (cm *CertificateManager) RemoveUnused(dto RemoveUnusedDTO) error {
+	ws, err := cm.workspaceRepo.Load(dto.WorkspaceName)
+
+	if err != nil {
+		return err
+	}
+
+	inUse := map[string]bool{}
+
+	for _, name := range dto.OtherWorkspaces {
+		other, err := cm.workspaceRepo.Load(name)
+
+		if err != nil {
+			cm.tui.Info(fmt.Sprintf("Could not load workspace '%s', leaving certificates in place.", name))
+			return nil
+		}
+
+		for _, c := range other.GetUniqueTLSCertificates() {
+			inUse[c] = true
+		}
+	}
+
+	for _, c := range ws.GetUniqueTLSCertificates() {
+		if inUse[c] {
+			cm.tui.Info(fmt.Sprintf("Certificate for '%s' is used by another workspace, skipping.", c))
+			continue
+		}
+
+		keyPath, certPath := cm.getCertPaths(c)
+
+		for _, path := range []string{keyPath, certPath} {
+			if err := cm.fs.Remove(path); err != nil && !os.IsNotExist(err) {
+				return cm.tui.RecordIfError(fmt.Sprintf("Failed to remove %s", path), err)
+			}
+		}
+
+		cm.tui.Success(fmt.Sprintf("Removed certificate for '%s'", c))
+	}
+
+	return nil
+}
+
 func NewCertificateManager(fs afero.Fs, workspaceRepo workspaceRepo, tui tui, tlsDir string) *CertificateManager {
 	return &CertificateManager{
 		fs:            fs,
@@ -8,6 +8,8 @@ import (
 	tls_mocks "github.com/panoptescloud/orca/tests/mocks/tls"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_CertificateManager_Generate(t *testing.T) {
@@ -198,3 +200,52 @@ func Test_CertificateManager_Generate(t *testing.T) {
 		})
 	}
 }
+
+func Test_CertificateManager_RemoveUnused(t *testing.T) {
+	withCertificates := func(certs ...string) *common.Workspace {
+		return &common.Workspace{
+			Projects: []common.Project{
+				{
+					Config: common.ProjectConfig{
+						TLSCertificates: certs,
+					},
+				},
+			},
+		}
+	}
+
+	tui := tls_mocks.NewMockTui(t)
+	tui.EXPECT().Info(mock.Anything).Maybe()
+	tui.EXPECT().Success(mock.Anything).Maybe()
+	tui.EXPECT().NewLine().Maybe()
+	tui.EXPECT().RecordIfError(mock.Anything, nil).Return(nil).Maybe()
+
+	wsRepo := tls_mocks.NewMockWorkspaceRepo(t)
+	wsRepo.EXPECT().Load("test").Return(withCertificates("*.example.com", "blah.test"), nil)
+	wsRepo.EXPECT().Load("other").Return(withCertificates("blah.test"), nil)
+
+	fs := afero.NewMemMapFs()
+	cm := tls.NewCertificateManager(fs, wsRepo, tui, "/some/path")
+
+	require.Nil(t, cm.Generate(tls.GenerateDTO{
+		WorkspaceName: "test",
+	}))
+
+	err := cm.RemoveUnused(tls.RemoveUnusedDTO{
+		WorkspaceName:   "test",
+		OtherWorkspaces: []string{"other"},
+	})
+	require.Nil(t, err)
+
+	for path, expect := range map[string]bool{
+		"/some/path/certs/_.example.com.key":  false,
+		"/some/path/certs/_.example.com.cert": false,
+		"/some/path/certs/blah.test.key":      true,
+		"/some/path/certs/blah.test.cert":     true,
+		"/some/path/cert.pem":                 true,
+	} {
+		exists, err := afero.Exists(fs, path)
+		require.Nil(t, err)
+		assert.Equal(t, expect, exists, path)
+	}
+}
@@ -0,0 +1,20 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// Table prints the rows aligned into columns, beneath the headers.
+func (t *Tui) Table(headers []string, rows [][]string) {
+	w := tabwriter.NewWriter(t.std, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	w.Flush()
+}
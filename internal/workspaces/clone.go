@@ -1,76 +1,256 @@
 package workspaces
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 
 	"github.com/panoptescloud/orca/internal/common"
 	"github.com/spf13/afero"
 )
 
+// maxParallelClones limits how many repositories are cloned at once.
+const maxParallelClones = 4
+
 type CloneDTO struct {
 	WorkspaceName string
 	Project       string
 	To            string
+
+	// Options are passed to git when cloning each project.
+	Options common.CloneOptions
+
+	// RetryFailed is the number of times to retry projects that failed to
+	// clone, before giving up on them.
+	RetryFailed int
+}
+
+type cloneOutcome string
+
+const (
+	cloneOutcomeCloned     cloneOutcome = "cloned"
+	cloneOutcomeRegistered cloneOutcome = "registered"
+	cloneOutcomeSkipped    cloneOutcome = "skipped"
+	cloneOutcomeFailed     cloneOutcome = "failed"
+
+	// cloneOutcomeNotStarted is for projects that were still waiting to be
+	// cloned when orca was interrupted.
+	cloneOutcomeNotStarted cloneOutcome = "not started"
+)
+
+type cloneResult struct {
+	project string
+	outcome cloneOutcome
+	err     error
+}
+
+func (m *Manager) cloneInParallel(ctx context.Context, ws *common.Workspace, projects []common.Project, into string, opts common.CloneOptions) []cloneResult {
+	results := make([]cloneResult, len(projects))
+	sem := make(chan struct{}, maxParallelClones)
+	wg := sync.WaitGroup{}
+	done := atomic.Int32{}
+
+	for i, project := range projects {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				results[i] = cloneResult{
+					project: project.Name,
+					outcome: cloneOutcomeNotStarted,
+				}
+
+				return
+			}
+
+			dir := fmt.Sprintf("%s/%s", into, project.Name)
+			outcome, err := m.cloneSingleProject(ctx, ws, project, dir, opts)
+
+			results[i] = cloneResult{
+				project: project.Name,
+				outcome: outcome,
+				err:     err,
+			}
+
+			progress := fmt.Sprintf("[%d/%d] %s: %s", done.Add(1), len(projects), project.Name, outcome)
+
+			if err != nil {
+				m.tui.Error(fmt.Sprintf("%s (%s)", progress, err.Error()))
+				return
+			}
+
+			m.tui.Info(progress)
+		}()
+	}
+
+	wg.Wait()
+
+	return results
 }
 
-func (m *Manager) setupAllProjects(ws *common.Workspace, into string) error {
+func (m *Manager) setupAllProjects(ctx context.Context, ws *common.Workspace, into string, opts common.CloneOptions, retries int) error {
+	final := map[string]cloneResult{}
+	pending := ws.Projects
+
+	for _, project := range ws.Projects {
+		final[project.Name] = cloneResult{
+			project: project.Name,
+			outcome: cloneOutcomeNotStarted,
+		}
+	}
+
+	for attempt := 0; attempt <= retries && len(pending) > 0 && ctx.Err() == nil; attempt++ {
+		if attempt > 0 {
+			m.tui.Info(fmt.Sprintf("Retrying %d failed project(s), attempt %d of %d...", len(pending), attempt, retries))
+		}
+
+		failed := []common.Project{}
+
+		for i, r := range m.cloneInParallel(ctx, ws, pending, into, opts) {
+			final[r.project] = r
+
+			if r.outcome == cloneOutcomeFailed {
+				failed = append(failed, pending[i])
+			}
+		}
+
+		pending = failed
+	}
+
+	counts := map[cloneOutcome]int{}
+	rows := [][]string{}
+	cloned := []string{}
+
 	for _, project := range ws.Projects {
-		dir := fmt.Sprintf("%s/%s", into, project.Name)
+		r := final[project.Name]
+		counts[r.outcome]++
 
-		if err := m.cloneSingleProject(ws, project, dir); err != nil {
-			return err
+		if r.outcome == cloneOutcomeCloned {
+			cloned = append(cloned, r.project)
+		}
+
+		detail := ""
+		if r.err != nil {
+			detail = r.err.Error()
+		}
+
+		rows = append(rows, []string{r.project, string(r.outcome), detail})
+	}
+
+	m.tui.Table([]string{"PROJECT", "RESULT", "DETAIL"}, rows)
+
+	summary := fmt.Sprintf(
+		"%d cloned, %d registered, %d skipped, %d failed",
+		counts[cloneOutcomeCloned],
+		counts[cloneOutcomeRegistered],
+		counts[cloneOutcomeSkipped],
+		counts[cloneOutcomeFailed],
+	)
+
+	if ctx.Err() != nil {
+		m.tui.Error(fmt.Sprintf("Interrupted! %s, %d not started", summary, counts[cloneOutcomeNotStarted]))
+
+		return ctx.Err()
+	}
+
+	if counts[cloneOutcomeFailed] > 0 {
+		m.tui.Error(summary)
+	} else {
+		m.tui.Success(summary)
+	}
+
+	// The hooks still run for whatever was cloned, as they won't be cloned
+	// again when retrying the rest
+	if err := m.runPostCloneHooks(ctx, ws.Name, cloned); err != nil {
+		return err
+	}
+
+	if counts[cloneOutcomeFailed] > 0 {
+		return common.ErrCommandExecutionFailed{
+			Msg: fmt.Sprintf("%d project(s) failed to clone", counts[cloneOutcomeFailed]),
 		}
 	}
 
 	return nil
 }
 
-func (m *Manager) registerWorkspaceProject(ws *common.Workspace, project common.Project) error {
+// runPostCloneHooks runs the hooks for the projects which have just been
+// cloned, and then the workspace's own. Nothing is run if nothing was cloned.
+func (m *Manager) runPostCloneHooks(ctx context.Context, wsName string, cloned []string) error {
+	if len(cloned) == 0 {
+		return nil
+	}
+
+	return m.hooks.RunHooks(ctx, common.HookPostClone, wsName, cloned)
+}
+
+func (m *Manager) registerWorkspaceProject(ctx context.Context, ws *common.Workspace, project common.Project) error {
 	wsLocation, err := m.configManager.GetWorkspaceMeta(ws.Name)
 
 	if err != nil {
 		return err
 	}
 
-	root, err := m.git.GetRepositoryRootFromPath(wsLocation.Path)
+	root, err := m.git.GetRepositoryRootFromPath(ctx, wsLocation.Path)
 
 	if err != nil {
 		return err
 	}
 
-	return m.configManager.SetProjectPath(ws.Name, project.Name, root)
+	return m.setProjectPath(ws.Name, project.Name, root)
+}
+
+// setProjectPath saves the location of the project, it's safe to call while
+// cloning in parallel.
+func (m *Manager) setProjectPath(wsName string, projectName string, path string) error {
+	m.configMu.Lock()
+	defer m.configMu.Unlock()
+
+	return m.configManager.SetProjectPath(wsName, projectName, path)
+}
+
+func (m *Manager) projectExists(wsName string, projectName string) (bool, error) {
+	m.configMu.Lock()
+	defer m.configMu.Unlock()
+
+	return m.configManager.ProjectExists(wsName, projectName)
 }
 
-func (m *Manager) cloneSingleProject(ws *common.Workspace, project common.Project, into string) error {
-	projectExists, err := m.configManager.ProjectExists(ws.Name, project.Name)
+func (m *Manager) cloneSingleProject(ctx context.Context, ws *common.Workspace, project common.Project, into string, opts common.CloneOptions) (cloneOutcome, error) {
+	projectExists, err := m.projectExists(ws.Name, project.Name)
 
 	if err != nil {
-		return err
+		return cloneOutcomeFailed, err
 	}
 
 	if project.RepositoryConfig.Self {
-		m.tui.Info(fmt.Sprintf("Registering workspace as project: %s", project.Name))
+		if err := m.registerWorkspaceProject(ctx, ws, project); err != nil {
+			return cloneOutcomeFailed, err
+		}
 
-		return m.tui.RecordIfError(
-			"Failed to register workspace as a project!",
-			m.registerWorkspaceProject(ws, project),
-		)
+		return cloneOutcomeRegistered, nil
 	}
 
 	exists, err := afero.Exists(m.fs, into)
 
 	if err != nil {
-		return err
+		return cloneOutcomeFailed, err
 	}
 
 	if exists {
 		if projectExists {
-			m.tui.Info(fmt.Sprintf("Skipping '%s' as it already exists!", project.Name))
-			return nil
+			return cloneOutcomeSkipped, nil
 		}
 
-		return common.ErrDirectoryAlreadyExists{
+		return cloneOutcomeFailed, common.ErrDirectoryAlreadyExists{
 			Path: into,
 		}
 	}
@@ -78,29 +258,32 @@ func (m *Manager) cloneSingleProject(ws *common.Workspace, project common.Projec
 	parentDir := filepath.Dir(into)
 
 	if err := m.fs.MkdirAll(parentDir, 0755); err != nil {
-		return err
+		return cloneOutcomeFailed, err
 	}
 
-	m.tui.Info(fmt.Sprintf("Cloning '%s' into %s...", project.RepositoryConfig.SSH, into))
+	if err := m.git.Clone(ctx, project.RepositoryConfig.SSH, into, opts); err != nil {
+		// The directory didn't exist before, so anything left in it is a
+		// partial clone, which would stop it being cloned again
+		if removeErr := m.fs.RemoveAll(into); removeErr != nil {
+			return cloneOutcomeFailed, errors.Join(err, fmt.Errorf("failed to remove the partial clone: %w", removeErr))
+		}
 
-	err = m.git.Clone(project.RepositoryConfig.SSH, into)
+		return cloneOutcomeFailed, err
+	}
 
-	if err != nil {
-		return m.tui.RecordIfError("Clone failed!", err)
+	if err := m.setProjectPath(ws.Name, project.Name, into); err != nil {
+		return cloneOutcomeFailed, fmt.Errorf("cloned, but failed to save the path into your orca config: %w", err)
 	}
 
-	return m.tui.RecordIfError(
-		"Failed to save config, you will have to edit this manually but adding the path into your orca config!",
-		m.configManager.SetProjectPath(ws.Name, project.Name, into),
-	)
+	return cloneOutcomeCloned, nil
 }
 
-func (m *Manager) getCloneTargetDir(wsConfigPath string, target string) (string, error) {
+func (m *Manager) getCloneTargetDir(ctx context.Context, wsConfigPath string, target string) (string, error) {
 	if target != "" {
 		return filepath.Abs(target)
 	}
 
-	wsRoot, err := m.git.GetRepositoryRootFromPath(wsConfigPath)
+	wsRoot, err := m.git.GetRepositoryRootFromPath(ctx, wsConfigPath)
 
 	if err != nil {
 		return "", err
@@ -109,7 +292,7 @@ func (m *Manager) getCloneTargetDir(wsConfigPath string, target string) (string,
 	return filepath.Abs(fmt.Sprintf("%s/..", wsRoot))
 }
 
-func (m *Manager) Clone(dto CloneDTO) error {
+func (m *Manager) Clone(ctx context.Context, dto CloneDTO) error {
 	wsMeta, err := m.configManager.GetWorkspaceMeta(dto.WorkspaceName)
 
 	if err != nil {
@@ -123,42 +306,47 @@ func (m *Manager) Clone(dto CloneDTO) error {
 	cfg, err := m.workspaceRepo.Load(wsMeta.Name)
 
 	if err != nil {
+		m.reportConfigProblem(err)
 		return err
 	}
 
-	targetDir, err := m.getCloneTargetDir(wsMeta.Path, dto.To)
+	targetDir, err := m.getCloneTargetDir(ctx, wsMeta.Path, dto.To)
 
 	if err != nil {
 		return m.tui.RecordIfError("Failed to determine target directory!", err)
 	}
 
-	var cloneErr error = nil
+	if dto.Project == "" {
+		return m.setupAllProjects(ctx, cfg, targetDir, dto.Options, dto.RetryFailed)
+	}
 
-	if dto.Project != "" {
-		project, err := cfg.GetProject(dto.Project)
+	project, err := cfg.GetProject(dto.Project)
 
-		if err != nil {
-			return m.tui.RecordIfError("Failed to clone specified project!", err)
-		}
+	if err != nil {
+		return m.tui.RecordIfError("Failed to clone specified project!", err)
+	}
+
+	if dto.To == "" {
+		targetDir = fmt.Sprintf("%s/%s", targetDir, project.Name)
+	}
+
+	m.tui.Info(fmt.Sprintf("Cloning '%s' into %s...", project.RepositoryConfig.SSH, targetDir))
 
-		targetDir := targetDir
+	outcome, err := m.cloneSingleProject(ctx, cfg, *project, targetDir, dto.Options)
 
-		if dto.To == "" {
-			targetDir = fmt.Sprintf("%s/%s", targetDir, project.Name)
+	if err != nil {
+		if _, ok := err.(common.ErrDirectoryAlreadyExists); ok {
+			return m.tui.RecordIfError("Cannot clone into the given directory, it already exists!", err)
 		}
 
-		cloneErr = m.cloneSingleProject(cfg, *project, targetDir)
-	} else {
-		cloneErr = m.setupAllProjects(cfg, targetDir)
+		return m.tui.RecordIfError("Clone failed!", err)
 	}
 
-	if cloneErr != nil {
-		if _, ok := cloneErr.(common.ErrDirectoryAlreadyExists); ok {
-			return m.tui.RecordIfError("Cannot clone into the given directory, it already exists!", cloneErr)
-		}
+	m.tui.Success(fmt.Sprintf("%s: %s", project.Name, outcome))
 
-		return cloneErr
+	if outcome != cloneOutcomeCloned {
+		return nil
 	}
 
-	return nil
+	return m.runPostCloneHooks(ctx, cfg.Name, []string{project.Name})
 }
@@ -0,0 +1,392 @@
+package workspaces_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/config"
+	"github.com/panoptescloud/orca/internal/controller"
+	"github.com/panoptescloud/orca/internal/git"
+	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/panoptescloud/orca/internal/repository"
+	"github.com/panoptescloud/orca/internal/tls"
+	"github.com/panoptescloud/orca/internal/workspaces"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTui keeps everything written, so it can be asserted on.
+type recordingTui struct {
+	mu     sync.Mutex
+	lines  []string
+	tables [][][]string
+
+	// choice is returned when asked to choose, otherwise the user aborts.
+	choice string
+}
+
+func (r *recordingTui) record(msgs ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lines = append(r.lines, msgs...)
+}
+
+func (r *recordingTui) Info(msg ...string)    { r.record(msg...) }
+func (r *recordingTui) Error(msg ...string)   { r.record(msg...) }
+func (r *recordingTui) Success(msg ...string) { r.record(msg...) }
+func (r *recordingTui) NewLine()              {}
+
+func (r *recordingTui) RecordIfError(msg string, err error) error {
+	if err != nil {
+		r.record(msg)
+	}
+
+	return err
+}
+
+func (r *recordingTui) PresentChoices(opts []string, title string) (string, error) {
+	if r.choice == "" {
+		return "", common.ErrUserAbortedExecution{}
+	}
+
+	return r.choice, nil
+}
+
+func (r *recordingTui) Table(headers []string, rows [][]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tables = append(r.tables, rows)
+}
+
+// hookRun is a call to run the hooks for an event.
+type hookRun struct {
+	event     common.HookEvent
+	workspace string
+	projects  []string
+}
+
+type recordingHooks struct {
+	runs []hookRun
+}
+
+func (r *recordingHooks) RunHooks(ctx context.Context, event common.HookEvent, wsName string, projects []string) error {
+	r.runs = append(r.runs, hookRun{
+		event:     event,
+		workspace: wsName,
+		projects:  projects,
+	})
+
+	return nil
+}
+
+// fakeDocker stands in for everything that works with the container engine,
+// recording what it's asked to do.
+type fakeDocker struct {
+	calls   []string
+	running []string
+	volumes map[string][]string
+
+	// unavailable fails every query, as if the engine isn't running.
+	unavailable bool
+}
+
+func (f *fakeDocker) IsRunning(ctx context.Context, ws *common.Workspace, p *common.Project) (bool, error) {
+	if f.unavailable {
+		return false, errors.New("cannot connect to the docker daemon")
+	}
+
+	return slices.Contains(f.running, p.Name), nil
+}
+
+func (f *fakeDocker) Created(ctx context.Context, ws *common.Workspace, p *common.Project) ([]string, error) {
+	return f.volumes[p.Name], nil
+}
+
+func (f *fakeDocker) Down(ctx context.Context, dto controller.DownDTO) error {
+	f.calls = append(f.calls, "down "+dto.Workspace)
+
+	return nil
+}
+
+func (f *fakeDocker) RemoveUnused(dto tls.RemoveUnusedDTO) error {
+	f.calls = append(f.calls, fmt.Sprintf("remove certificates %s, keeping %s", dto.WorkspaceName, strings.Join(dto.OtherWorkspaces, ", ")))
+
+	return nil
+}
+
+func (f *fakeDocker) RemoveOverlays(wsName string) error {
+	f.calls = append(f.calls, "remove overlays "+wsName)
+
+	return nil
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(
+		os.Environ(),
+		"GIT_AUTHOR_NAME=orca",
+		"GIT_AUTHOR_EMAIL=orca@example.com",
+		"GIT_COMMITTER_NAME=orca",
+		"GIT_COMMITTER_EMAIL=orca@example.com",
+	)
+
+	out, err := cmd.CombinedOutput()
+	require.Nil(t, err, string(out))
+
+	return strings.TrimSpace(string(out))
+}
+
+// createSourceRepo creates a repository with a couple of commits, and returns
+// a url that can be cloned from.
+func createSourceRepo(t *testing.T, dir string) string {
+	require.Nil(t, os.MkdirAll(dir, 0755))
+	runGit(t, dir, "init", "-q")
+
+	projectConfig := []byte("composeFiles:\n  primary: docker-compose.yaml\n")
+	require.Nil(t, os.WriteFile(filepath.Join(dir, common.DefaultProjectFileName), projectConfig, 0644))
+	runGit(t, dir, "add", common.DefaultProjectFileName)
+
+	for i := range 2 {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, "file"), []byte(fmt.Sprint(i)), 0644))
+		runGit(t, dir, "add", "file")
+		runGit(t, dir, "commit", "-q", "-m", fmt.Sprintf("commit %d", i))
+	}
+
+	return "file://" + dir
+}
+
+type testEnvironment struct {
+	manager *workspaces.Manager
+	tui     *recordingTui
+	hooks   *recordingHooks
+	docker  *fakeDocker
+	cfg     *config.Config
+	root    string
+}
+
+func newTestEnvironment(t *testing.T, wsConfig string) testEnvironment {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	root := t.TempDir()
+	fs := afero.NewOsFs()
+
+	wsConfigPath := filepath.Join(root, "ws", common.DefaultWorkspaceFileName)
+	require.Nil(t, fs.MkdirAll(filepath.Dir(wsConfigPath), 0755))
+	require.Nil(t, afero.WriteFile(fs, wsConfigPath, []byte(strings.ReplaceAll(wsConfig, "{root}", root)), 0644))
+
+	cfg := config.NewDefaultConfig(fs, filepath.Join(root, "orca.yaml"))
+	require.Nil(t, cfg.LoadOrCreate())
+	require.Nil(t, cfg.AddWorkspace(wsConfigPath, "test"))
+
+	tui := &recordingTui{}
+	hooks := &recordingHooks{}
+	docker := &fakeDocker{}
+	manager := workspaces.NewManager(
+		fs,
+		tui,
+		cfg,
+		git.NewGit(hostsys.NewExecutor(), tui),
+		repository.NewWorkspaceRepository(fs, cfg),
+		docker,
+		hooks,
+		docker,
+		docker,
+		docker,
+		docker,
+	)
+
+	return testEnvironment{
+		manager: manager,
+		tui:     tui,
+		hooks:   hooks,
+		docker:  docker,
+		cfg:     cfg,
+		root:    root,
+	}
+}
+
+func Test_Clone_AllProjects(t *testing.T) {
+	env := newTestEnvironment(t, `name: test
+projects:
+  - name: api
+    repository:
+      ssh: file://{root}/sources/api
+  - name: db
+    repository:
+      ssh: file://{root}/sources/db
+  - name: broken
+    repository:
+      ssh: file://{root}/sources/missing
+`)
+
+	createSourceRepo(t, filepath.Join(env.root, "sources", "api"))
+	createSourceRepo(t, filepath.Join(env.root, "sources", "db"))
+
+	target := filepath.Join(env.root, "projects")
+
+	err := env.manager.Clone(context.Background(), workspaces.CloneDTO{
+		WorkspaceName: "test",
+		To:            target,
+		Options: common.CloneOptions{
+			Depth: 1,
+		},
+		RetryFailed: 1,
+	})
+
+	assert.Equal(t, common.ErrCommandExecutionFailed{Msg: "1 project(s) failed to clone"}, err)
+	assert.Contains(t, env.tui.lines, "Retrying 1 failed project(s), attempt 1 of 1...")
+	assert.Contains(t, env.tui.lines, "2 cloned, 0 registered, 0 skipped, 1 failed")
+
+	for _, name := range []string{"api", "db"} {
+		meta, err := env.cfg.GetProjectMeta("test", name)
+		require.Nil(t, err)
+		assert.Equal(t, filepath.Join(target, name), meta.Path)
+
+		// Only a single commit should've been fetched
+		assert.Equal(t, "1", runGit(t, meta.Path, "rev-list", "--count", "HEAD"))
+	}
+
+	exists, err := env.cfg.ProjectExists("test", "broken")
+	require.Nil(t, err)
+	assert.False(t, exists)
+
+	// The hooks still run for what was cloned, despite the failure
+	assert.Equal(t, []hookRun{
+		{event: common.HookPostClone, workspace: "test", projects: []string{"api", "db"}},
+	}, env.hooks.runs)
+
+	// Once the missing repository exists, running again only clones that one
+	createSourceRepo(t, filepath.Join(env.root, "sources", "missing"))
+	env.tui.lines = nil
+	env.hooks.runs = nil
+
+	err = env.manager.Clone(context.Background(), workspaces.CloneDTO{
+		WorkspaceName: "test",
+		To:            target,
+	})
+
+	require.Nil(t, err)
+	assert.Contains(t, env.tui.lines, "1 cloned, 0 registered, 2 skipped, 0 failed")
+	assert.Equal(t, [][]string{
+		{"api", "skipped", ""},
+		{"db", "skipped", ""},
+		{"broken", "cloned", ""},
+	}, env.tui.tables[len(env.tui.tables)-1])
+	assert.Equal(t, []hookRun{
+		{event: common.HookPostClone, workspace: "test", projects: []string{"broken"}},
+	}, env.hooks.runs)
+}
+
+func Test_Clone_Interrupted(t *testing.T) {
+	env := newTestEnvironment(t, `name: test
+projects:
+  - name: api
+    repository:
+      ssh: file://{root}/sources/api
+`)
+
+	createSourceRepo(t, filepath.Join(env.root, "sources", "api"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := env.manager.Clone(ctx, workspaces.CloneDTO{
+		WorkspaceName: "test",
+		To:            filepath.Join(env.root, "projects"),
+		RetryFailed:   1,
+	})
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Contains(t, env.tui.lines, "Interrupted! 0 cloned, 0 registered, 0 skipped, 0 failed, 1 not started")
+	assert.Equal(t, [][]string{
+		{"api", "not started", ""},
+	}, env.tui.tables[len(env.tui.tables)-1])
+
+	exists, err := env.cfg.ProjectExists("test", "api")
+	require.Nil(t, err)
+	assert.False(t, exists)
+}
+
+// partialCloneGit leaves a partial clone behind when it fails, as git does
+// when it's killed part way through.
+type partialCloneGit struct {
+	*git.Git
+
+	failures int
+}
+
+func (g *partialCloneGit) Clone(ctx context.Context, repoUrl string, target string, opts common.CloneOptions) error {
+	if g.failures == 0 {
+		return g.Git.Clone(ctx, repoUrl, target, opts)
+	}
+
+	g.failures--
+
+	if err := os.MkdirAll(filepath.Join(target, ".git"), 0755); err != nil {
+		return err
+	}
+
+	return errors.New("fatal: early EOF")
+}
+
+func Test_Clone_RemovesPartialClone(t *testing.T) {
+	env := newTestEnvironment(t, `name: test
+projects:
+  - name: api
+    repository:
+      ssh: file://{root}/sources/api
+`)
+
+	createSourceRepo(t, filepath.Join(env.root, "sources", "api"))
+
+	fs := afero.NewOsFs()
+	manager := workspaces.NewManager(
+		fs,
+		env.tui,
+		env.cfg,
+		&partialCloneGit{Git: git.NewGit(hostsys.NewExecutor(), env.tui), failures: 2},
+		repository.NewWorkspaceRepository(fs, env.cfg),
+		env.docker,
+		env.hooks,
+		env.docker,
+		env.docker,
+		env.docker,
+		env.docker,
+	)
+
+	target := filepath.Join(env.root, "projects")
+
+	err := manager.Clone(context.Background(), workspaces.CloneDTO{
+		WorkspaceName: "test",
+		To:            target,
+	})
+
+	assert.Equal(t, common.ErrCommandExecutionFailed{Msg: "1 project(s) failed to clone"}, err)
+	assert.NoDirExists(t, filepath.Join(target, "api"))
+
+	// Retrying doesn't trip over what the failed attempts left behind
+	err = manager.Clone(context.Background(), workspaces.CloneDTO{
+		WorkspaceName: "test",
+		To:            target,
+		RetryFailed:   1,
+	})
+
+	require.Nil(t, err)
+	assert.Contains(t, env.tui.lines, "1 cloned, 0 registered, 0 skipped, 0 failed")
+	assert.FileExists(t, filepath.Join(target, "api", common.DefaultProjectFileName))
+}
@@ -0,0 +1,252 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+// maxParallelGitOperations limits how many repositories are worked on at
+// once, to avoid hitting rate limits on the remote.
+const maxParallelGitOperations = 8
+
+type GitAllDTO struct {
+	WorkspaceName string
+}
+
+type GitCheckoutAllDTO struct {
+	WorkspaceName string
+	Branch        string
+
+	// Pull the branch after it's checked out, if it has an upstream.
+	Pull bool
+}
+
+// gitOperation performs some action on the repository in dir, returning a
+// short description of what happened.
+type gitOperation func(dir string, status common.RepoStatus) (string, error)
+
+type gitResult struct {
+	project string
+	status  common.RepoStatus
+	result  string
+	err     error
+}
+
+func (r gitResult) row() []string {
+	aheadBehind := "-"
+	if r.status.HasUpstream {
+		aheadBehind = fmt.Sprintf("+%d/-%d", r.status.Ahead, r.status.Behind)
+	}
+
+	dirty := "no"
+	if r.status.Dirty {
+		dirty = "yes"
+	}
+
+	result := r.result
+	if r.err != nil {
+		msg, _, _ := strings.Cut(r.err.Error(), "\n")
+		result = fmt.Sprintf("failed: %s", msg)
+	}
+
+	return []string{r.project, r.status.Branch, aheadBehind, dirty, result}
+}
+
+// registeredProjects returns the projects of the workspace that exist locally.
+// A project which is the workspace repo itself shares its path with the
+// workspace, so each path is only returned once.
+func (m *Manager) registeredProjects(wsName string) ([]common.ProjectMeta, error) {
+	if _, err := m.configManager.GetWorkspaceMeta(wsName); err != nil {
+		return nil, err
+	}
+
+	projects := []common.ProjectMeta{}
+	paths := []string{}
+
+	for _, p := range m.configManager.GetAllProjectMeta() {
+		if p.WorkspaceName != wsName || slices.Contains(paths, p.Path) {
+			continue
+		}
+
+		projects = append(projects, p)
+		paths = append(paths, p.Path)
+	}
+
+	return projects, nil
+}
+
+func (m *Manager) runGitInProject(ctx context.Context, p common.ProjectMeta, op gitOperation) gitResult {
+	res := gitResult{
+		project: p.Name,
+	}
+
+	res.status, res.err = m.git.StatusIn(ctx, p.Path)
+
+	if res.err != nil {
+		return res
+	}
+
+	res.result, res.err = op(p.Path, res.status)
+
+	// Refresh the status, so the summary shows the state after the operation
+	if after, err := m.git.StatusIn(ctx, p.Path); err == nil {
+		res.status = after
+	}
+
+	return res
+}
+
+func (m *Manager) runGitAcrossProjects(ctx context.Context, wsName string, op gitOperation) error {
+	if wsName == "" {
+		wsName = m.configManager.GetCurrentWorkspace()
+	}
+
+	projects, err := m.registeredProjects(wsName)
+
+	if err != nil {
+		if _, ok := err.(common.ErrUnknownWorkspace); ok {
+			return m.tui.RecordIfError(fmt.Sprintf("Unknown workspace: %s", wsName), err)
+		}
+
+		return err
+	}
+
+	if len(projects) == 0 {
+		m.tui.Error(fmt.Sprintf("No projects have been cloned for workspace '%s'!", wsName))
+		return nil
+	}
+
+	results := make([]gitResult, len(projects))
+	sem := make(chan struct{}, maxParallelGitOperations)
+	wg := sync.WaitGroup{}
+
+	for i, p := range projects {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				results[i] = gitResult{
+					project: p.Name,
+					result:  "not started",
+				}
+
+				return
+			}
+
+			results[i] = m.runGitInProject(ctx, p, op)
+		}()
+	}
+
+	wg.Wait()
+
+	rows := make([][]string, len(results))
+	failed := 0
+
+	for i, r := range results {
+		rows[i] = r.row()
+
+		if r.err != nil {
+			failed++
+		}
+	}
+
+	m.tui.Table([]string{"PROJECT", "BRANCH", "AHEAD/BEHIND", "DIRTY", "RESULT"}, rows)
+
+	if ctx.Err() != nil {
+		return m.tui.RecordIfError("Interrupted!", ctx.Err())
+	}
+
+	if failed > 0 {
+		return common.ErrCommandExecutionFailed{
+			Msg: fmt.Sprintf("%d of %d projects failed", failed, len(results)),
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) StatusAll(ctx context.Context, dto GitAllDTO) error {
+	return m.runGitAcrossProjects(ctx, dto.WorkspaceName, func(dir string, status common.RepoStatus) (string, error) {
+		return "ok", nil
+	})
+}
+
+func (m *Manager) PullAll(ctx context.Context, dto GitAllDTO) error {
+	return m.runGitAcrossProjects(ctx, dto.WorkspaceName, func(dir string, status common.RepoStatus) (string, error) {
+		if status.Dirty {
+			return "skipped, uncommitted changes", nil
+		}
+
+		if !status.HasUpstream {
+			return "skipped, no upstream branch", nil
+		}
+
+		if err := m.git.PullIn(ctx, dir); err != nil {
+			return "", err
+		}
+
+		return "pulled", nil
+	})
+}
+
+func (m *Manager) CheckoutAll(ctx context.Context, dto GitCheckoutAllDTO) error {
+	if dto.Branch == "" {
+		return m.tui.RecordIfError("A branch name is required!", common.ErrInvalidInput{
+			To:  "workspaces.checkout",
+			Msg: "'branch' cannot be empty",
+		})
+	}
+
+	return m.runGitAcrossProjects(ctx, dto.WorkspaceName, func(dir string, status common.RepoStatus) (string, error) {
+		if status.Dirty {
+			return "skipped, uncommitted changes", nil
+		}
+
+		result := "already checked out"
+
+		if status.Branch != dto.Branch {
+			found, err := m.git.CheckoutIn(ctx, dir, dto.Branch)
+
+			if err != nil {
+				return "", err
+			}
+
+			if !found {
+				return "skipped, branch not found", nil
+			}
+
+			result = "checked out"
+		}
+
+		if !dto.Pull {
+			return result, nil
+		}
+
+		// The status was from before the checkout, so check the upstream again
+		after, err := m.git.StatusIn(ctx, dir)
+
+		if err != nil {
+			return "", err
+		}
+
+		if !after.HasUpstream {
+			return result + ", no upstream to pull", nil
+		}
+
+		if err := m.git.PullIn(ctx, dir); err != nil {
+			return "", err
+		}
+
+		return result + " and pulled", nil
+	})
+}
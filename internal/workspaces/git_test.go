@@ -0,0 +1,199 @@
+package workspaces_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/workspaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gitTestWorkspace = `name: test
+projects:
+  - name: api
+  - name: db
+  - name: web
+  - name: local
+  - name: broken
+`
+
+// setUpGitProjects registers a project for each case the operations handle:
+//   - api is a clean clone, with a 'feature' branch on origin
+//   - db is a clone with uncommitted changes
+//   - web is a clean clone, without the 'feature' branch
+//   - local has no upstream
+//   - broken isn't a git repository at all
+//
+// The sources are returned, keyed by project, so more can be committed.
+func setUpGitProjects(t *testing.T, env testEnvironment) map[string]string {
+	sources := map[string]string{}
+
+	for _, name := range []string{"api", "db", "web"} {
+		sources[name] = filepath.Join(env.root, "sources", name)
+		url := createSourceRepo(t, sources[name])
+
+		if name == "api" {
+			base := runGit(t, sources[name], "rev-parse", "--abbrev-ref", "HEAD")
+			runGit(t, sources[name], "branch", "feature")
+			runGit(t, sources[name], "checkout", "-q", "feature")
+			require.Nil(t, os.WriteFile(filepath.Join(sources[name], "feature"), []byte("feature"), 0644))
+			runGit(t, sources[name], "add", "feature")
+			runGit(t, sources[name], "commit", "-q", "-m", "feature")
+			runGit(t, sources[name], "checkout", "-q", base)
+		}
+
+		dir := filepath.Join(env.root, "projects", name)
+		runGit(t, env.root, "clone", "-q", url, dir)
+		require.Nil(t, env.cfg.SetProjectPath("test", name, dir))
+	}
+
+	require.Nil(t, os.WriteFile(filepath.Join(env.root, "projects", "db", "file"), []byte("changed"), 0644))
+
+	local := filepath.Join(env.root, "projects", "local")
+	require.Nil(t, os.MkdirAll(local, 0755))
+	runGit(t, local, "init", "-q")
+	runGit(t, local, "commit", "-q", "--allow-empty", "-m", "initial")
+	require.Nil(t, env.cfg.SetProjectPath("test", "local", local))
+
+	broken := filepath.Join(env.root, "projects", "broken")
+	require.Nil(t, os.MkdirAll(broken, 0755))
+	require.Nil(t, env.cfg.SetProjectPath("test", "broken", broken))
+
+	return sources
+}
+
+// resultsByProject returns the result column of the summary, with failures
+// trimmed to just 'failed' as the message comes from git.
+func resultsByProject(t *testing.T, env testEnvironment) map[string]string {
+	require.Len(t, env.tui.tables, 1)
+
+	results := map[string]string{}
+
+	for _, row := range env.tui.tables[0] {
+		result := row[4]
+
+		if strings.HasPrefix(result, "failed: ") {
+			result = "failed"
+		}
+
+		results[row[0]] = result
+	}
+
+	return results
+}
+
+func Test_StatusAll(t *testing.T) {
+	env := newTestEnvironment(t, gitTestWorkspace)
+	setUpGitProjects(t, env)
+
+	err := env.manager.StatusAll(context.Background(), workspaces.GitAllDTO{
+		WorkspaceName: "test",
+	})
+
+	assert.Equal(t, common.ErrCommandExecutionFailed{Msg: "1 of 5 projects failed"}, err)
+	assert.Equal(t, map[string]string{
+		"api":    "ok",
+		"db":     "ok",
+		"web":    "ok",
+		"local":  "ok",
+		"broken": "failed",
+	}, resultsByProject(t, env))
+
+	for _, row := range env.tui.tables[0] {
+		switch row[0] {
+		case "db":
+			assert.Equal(t, []string{"+0/-0", "yes"}, row[2:4])
+		case "local":
+			assert.Equal(t, []string{"-", "no"}, row[2:4])
+		}
+	}
+}
+
+func Test_StatusAll_NothingToDo(t *testing.T) {
+	env := newTestEnvironment(t, gitTestWorkspace)
+
+	err := env.manager.StatusAll(context.Background(), workspaces.GitAllDTO{
+		WorkspaceName: "missing",
+	})
+
+	assert.Equal(t, common.ErrUnknownWorkspace{Name: "missing"}, err)
+
+	err = env.manager.StatusAll(context.Background(), workspaces.GitAllDTO{
+		WorkspaceName: "test",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"Unknown workspace: missing",
+		"No projects have been cloned for workspace 'test'!",
+	}, env.tui.lines)
+}
+
+func Test_PullAll(t *testing.T) {
+	env := newTestEnvironment(t, gitTestWorkspace)
+	sources := setUpGitProjects(t, env)
+
+	// Something new to pull, for every clone
+	for _, source := range sources {
+		require.Nil(t, os.WriteFile(filepath.Join(source, "file"), []byte("new"), 0644))
+		runGit(t, source, "commit", "-q", "-am", "new")
+	}
+
+	err := env.manager.PullAll(context.Background(), workspaces.GitAllDTO{
+		WorkspaceName: "test",
+	})
+
+	assert.Equal(t, common.ErrCommandExecutionFailed{Msg: "1 of 5 projects failed"}, err)
+	assert.Equal(t, map[string]string{
+		"api":    "pulled",
+		"db":     "skipped, uncommitted changes",
+		"web":    "pulled",
+		"local":  "skipped, no upstream branch",
+		"broken": "failed",
+	}, resultsByProject(t, env))
+
+	for name, pulled := range map[string]bool{"api": true, "db": false, "web": true} {
+		content, err := os.ReadFile(filepath.Join(env.root, "projects", name, "file"))
+		require.Nil(t, err)
+		assert.Equal(t, pulled, string(content) == "new", name)
+	}
+}
+
+func Test_CheckoutAll(t *testing.T) {
+	env := newTestEnvironment(t, gitTestWorkspace)
+	setUpGitProjects(t, env)
+
+	err := env.manager.CheckoutAll(context.Background(), workspaces.GitCheckoutAllDTO{
+		WorkspaceName: "test",
+		Branch:        "feature",
+		Pull:          true,
+	})
+
+	assert.Equal(t, common.ErrCommandExecutionFailed{Msg: "1 of 5 projects failed"}, err)
+	assert.Equal(t, map[string]string{
+		"api":    "checked out and pulled",
+		"db":     "skipped, uncommitted changes",
+		"web":    "skipped, branch not found",
+		"local":  "skipped, branch not found",
+		"broken": "failed",
+	}, resultsByProject(t, env))
+
+	assert.Equal(t, "feature", runGit(t, filepath.Join(env.root, "projects", "api"), "rev-parse", "--abbrev-ref", "HEAD"))
+	assert.NotEqual(t, "feature", runGit(t, filepath.Join(env.root, "projects", "db"), "rev-parse", "--abbrev-ref", "HEAD"))
+}
+
+func Test_CheckoutAll_NoBranch(t *testing.T) {
+	env := newTestEnvironment(t, gitTestWorkspace)
+
+	err := env.manager.CheckoutAll(context.Background(), workspaces.GitCheckoutAllDTO{
+		WorkspaceName: "test",
+	})
+
+	assert.IsType(t, common.ErrInvalidInput{}, err)
+	assert.Empty(t, env.tui.tables)
+}
@@ -1,40 +1,172 @@
 package workspaces
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
 
 	"github.com/panoptescloud/orca/internal/common"
 	"github.com/spf13/afero"
 )
 
+// maxWorkspaceConfigSearchDepth limits how far into a cloned repository we'll
+// look for the workspace config, if it's not at the root.
+const maxWorkspaceConfigSearchDepth = 3
+
 type InitialiseDTO struct {
-	// Not yet implemented, but will allow you to supply a git url; then clone it
+	// SourceGitUrl is cloned into Into, and used as the source directory
 	SourceGitUrl      string
 	SourceDirectory   string
 	WorkspaceFileName string
 	Into              string
+
+	// CloneProjects clones all projects in the workspace into Into once it has
+	// been registered.
+	CloneProjects bool
+
+	// Switch makes the new workspace the current one.
+	Switch bool
 }
 
-func (m *Manager) Initialise(dto InitialiseDTO) error {
-	workspaceConfigPath := fmt.Sprintf("%s/%s", dto.SourceDirectory, dto.WorkspaceFileName)
-	exists, err := afero.Exists(m.fs, workspaceConfigPath)
+// repoNameFromUrl works out the directory git would clone the repository
+// into, e.g. 'git@github.com:org/workspace.git' becomes 'workspace'.
+func repoNameFromUrl(url string) string {
+	name := strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+func (m *Manager) cloneWorkspaceRepo(ctx context.Context, dto InitialiseDTO) (string, error) {
+	into, err := filepath.Abs(dto.Into)
 
 	if err != nil {
-		return m.tui.RecordIfError("Failed to find config, this is likely a bug!", err)
+		return "", err
+	}
+
+	dir := filepath.Join(into, repoNameFromUrl(dto.SourceGitUrl))
+
+	exists, err := afero.Exists(m.fs, dir)
+
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		return "", common.ErrDirectoryAlreadyExists{
+			Path: dir,
+		}
+	}
+
+	if err := m.fs.MkdirAll(into, 0755); err != nil {
+		return "", err
+	}
+
+	m.tui.Info(fmt.Sprintf("Cloning '%s' into %s...", dto.SourceGitUrl, dir))
+
+	if err := m.git.Clone(ctx, dto.SourceGitUrl, dir, common.CloneOptions{}); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// findWorkspaceConfig looks for the workspace config in the root of dir first,
+// and then within its sub directories if searchSubDirs is set. That's only
+// done for a repository that was just cloned, a local directory must be the
+// one containing the config, so that e.g. a vendored copy isn't picked up.
+func (m *Manager) findWorkspaceConfig(dir string, fileName string, searchSubDirs bool) (string, error) {
+	path := filepath.Join(dir, fileName)
+
+	exists, err := afero.Exists(m.fs, path)
+
+	if err != nil || exists {
+		return path, err
+	}
+
+	if !searchSubDirs {
+		return "", common.ErrFileNotFound{
+			Path: path,
+		}
+	}
+
+	found := ""
+
+	err = afero.Walk(m.fs, dir, func(p string, info fs.FileInfo, err error) error {
+		if err != nil || found != "" {
+			return err
+		}
+
+		rel, _ := filepath.Rel(dir, p)
+		depth := len(strings.Split(rel, string(filepath.Separator)))
+
+		if info.IsDir() {
+			if info.Name() == ".git" || depth > maxWorkspaceConfigSearchDepth {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if info.Name() == fileName {
+			found = p
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return "", err
 	}
 
-	if !exists {
-		return m.tui.RecordIfError(
-			fmt.Sprintf("Could not find workspace config: %s", workspaceConfigPath),
-			common.ErrFileNotFound{
-				Path: workspaceConfigPath,
-			},
-		)
+	if found == "" {
+		return "", common.ErrFileNotFound{
+			Path: path,
+		}
+	}
+
+	return found, nil
+}
+
+func (m *Manager) Initialise(ctx context.Context, dto InitialiseDTO) error {
+	sourceDir := dto.SourceDirectory
+
+	if dto.SourceGitUrl != "" {
+		dir, err := m.cloneWorkspaceRepo(ctx, dto)
+
+		if err != nil {
+			if _, ok := err.(common.ErrDirectoryAlreadyExists); ok {
+				return m.tui.RecordIfError("Cannot clone the workspace, the directory already exists!", err)
+			}
+
+			return m.tui.RecordIfError("Failed to clone the workspace!", err)
+		}
+
+		sourceDir = dir
+	}
+
+	workspaceConfigPath, err := m.findWorkspaceConfig(sourceDir, dto.WorkspaceFileName, dto.SourceGitUrl != "")
+
+	if err != nil {
+		if _, ok := err.(common.ErrFileNotFound); ok {
+			return m.tui.RecordIfError(
+				fmt.Sprintf("Could not find workspace config: %s", dto.WorkspaceFileName),
+				err,
+			)
+		}
+
+		return m.tui.RecordIfError("Failed to find config, this is likely a bug!", err)
 	}
 
 	cfg, err := m.workspaceRepo.LoadUnconfiguredWorkspace(workspaceConfigPath)
 
 	if err != nil {
+		m.reportConfigProblem(err)
 		return m.tui.RecordIfError("Failed to open config file!", err)
 	}
 
@@ -50,5 +182,23 @@ func (m *Manager) Initialise(dto InitialiseDTO) error {
 	}
 
 	m.tui.Success("Workspace initialised!")
+
+	if dto.CloneProjects {
+		err := m.Clone(ctx, CloneDTO{
+			WorkspaceName: cfg.Name,
+			To:            dto.Into,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if dto.Switch {
+		return m.Switch(ctx, SwitchDTO{
+			WorkspaceName: cfg.Name,
+		})
+	}
+
 	return nil
 }
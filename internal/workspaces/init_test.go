@@ -0,0 +1,185 @@
+package workspaces_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/config"
+	"github.com/panoptescloud/orca/internal/git"
+	"github.com/panoptescloud/orca/internal/hostsys"
+	"github.com/panoptescloud/orca/internal/repository"
+	"github.com/panoptescloud/orca/internal/workspaces"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createWorkspaceRepo creates a bare repository containing the workspace
+// config at configPath, and returns a url that can be cloned from.
+func createWorkspaceRepo(t *testing.T, root string, configPath string, wsConfig string) string {
+	dir := filepath.Join(root, "sources", "workspace")
+	path := filepath.Join(dir, configPath)
+
+	require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+	runGit(t, root, "init", "-q", dir)
+	require.Nil(t, os.WriteFile(path, []byte(strings.ReplaceAll(wsConfig, "{root}", root)), 0644))
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "workspace")
+
+	bare := filepath.Join(root, "sources", "workspace.git")
+	runGit(t, root, "clone", "-q", "--bare", dir, bare)
+
+	return "file://" + bare
+}
+
+func Test_Initialise_FromGitUrl(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	tests := []struct {
+		name       string
+		configPath string
+	}{
+		{
+			name:       "config at root",
+			configPath: common.DefaultWorkspaceFileName,
+		},
+		{
+			name:       "config in sub directory",
+			configPath: filepath.Join("orca", common.DefaultWorkspaceFileName),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			root := tt.TempDir()
+			fs := afero.NewOsFs()
+
+			url := createWorkspaceRepo(tt, root, test.configPath, `name: test
+projects:
+  - name: api
+    repository:
+      ssh: file://{root}/sources/api
+`)
+			createSourceRepo(tt, filepath.Join(root, "sources", "api"))
+
+			cfg := config.NewDefaultConfig(fs, filepath.Join(root, "orca.yaml"))
+			require.Nil(tt, cfg.LoadOrCreate())
+
+			tui := &recordingTui{}
+			manager := workspaces.NewManager(
+				fs,
+				tui,
+				cfg,
+				git.NewGit(hostsys.NewExecutor(), tui),
+				repository.NewWorkspaceRepository(fs, cfg),
+				nil,
+				&recordingHooks{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			into := filepath.Join(root, "projects")
+
+			err := manager.Initialise(context.Background(), workspaces.InitialiseDTO{
+				SourceGitUrl:      url,
+				WorkspaceFileName: common.DefaultWorkspaceFileName,
+				Into:              into,
+				CloneProjects:     true,
+				Switch:            true,
+			})
+			require.Nil(tt, err)
+
+			ws, err := cfg.GetWorkspaceMeta("test")
+			require.Nil(tt, err)
+			assert.Equal(tt, filepath.Join(into, "workspace", test.configPath), ws.Path)
+
+			// Switching only updates the config on disk
+			reloaded := config.NewDefaultConfig(fs, filepath.Join(root, "orca.yaml"))
+			require.Nil(tt, reloaded.LoadOrCreate())
+			assert.Equal(tt, "test", reloaded.GetCurrentWorkspace())
+
+			meta, err := cfg.GetProjectMeta("test", "api")
+			require.Nil(tt, err)
+			assert.Equal(tt, filepath.Join(into, "api"), meta.Path)
+
+			// The workspace repo has already been cloned, so it can't be again
+			err = manager.Initialise(context.Background(), workspaces.InitialiseDTO{
+				SourceGitUrl:      url,
+				WorkspaceFileName: common.DefaultWorkspaceFileName,
+				Into:              into,
+			})
+			assert.Equal(tt, common.ErrDirectoryAlreadyExists{Path: filepath.Join(into, "workspace")}, err)
+		})
+	}
+}
+
+func Test_Initialise_FromDirectory(t *testing.T) {
+	tests := []struct {
+		name       string
+		configPath string
+		expectErr  error
+	}{
+		{
+			name:       "config at root",
+			configPath: "/src/workspace/" + common.DefaultWorkspaceFileName,
+		},
+		{
+			// Only searched for when the workspace has just been cloned
+			name:       "config in sub directory",
+			configPath: "/src/workspace/vendor/other/" + common.DefaultWorkspaceFileName,
+			expectErr: common.ErrFileNotFound{
+				Path: "/src/workspace/" + common.DefaultWorkspaceFileName,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.Nil(tt, afero.WriteFile(fs, test.configPath, []byte(`name: test
+projects:
+  - name: api
+`), 0644))
+
+			cfg := config.NewDefaultConfig(fs, "/home/orca.yaml")
+			require.Nil(tt, cfg.LoadOrCreate())
+
+			manager := workspaces.NewManager(
+				fs,
+				&recordingTui{},
+				cfg,
+				nil,
+				repository.NewWorkspaceRepository(fs, cfg),
+				nil,
+				&recordingHooks{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			err := manager.Initialise(context.Background(), workspaces.InitialiseDTO{
+				SourceDirectory:   "/src/workspace",
+				WorkspaceFileName: common.DefaultWorkspaceFileName,
+			})
+			assert.Equal(tt, test.expectErr, err)
+
+			if test.expectErr != nil {
+				return
+			}
+
+			ws, err := cfg.GetWorkspaceMeta("test")
+			require.Nil(tt, err)
+			assert.Equal(tt, test.configPath, ws.Path)
+		})
+	}
+}
@@ -1,11 +1,56 @@
 package workspaces
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/spf13/afero"
+)
 
 type LsDTO struct {
+	// Status also shows how many of the projects are running, which requires
+	// docker.
+	Status bool
+}
+
+// countRegistered counts the projects with a known path, for when the
+// workspace config can't be loaded.
+func (m *Manager) countRegistered(wsName string) int {
+	count := 0
+
+	for _, p := range m.configManager.GetAllProjectMeta() {
+		if p.WorkspaceName == wsName {
+			count++
+		}
+	}
+
+	return count
 }
 
-func (m *Manager) Ls(dto LsDTO) error {
+func (m *Manager) countRunning(ctx context.Context, ws *common.Workspace) (int, error) {
+	running := 0
+
+	for _, p := range ws.Projects {
+		if !p.IsRegistered {
+			continue
+		}
+
+		isRunning, err := m.compose.IsRunning(ctx, ws, &p)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if isRunning {
+			running++
+		}
+	}
+
+	return running, nil
+}
+
+func (m *Manager) Ls(ctx context.Context, dto LsDTO) error {
 	locs := m.configManager.GetAllWorkspaceMeta()
 
 	if len(locs) == 0 {
@@ -14,9 +59,66 @@ func (m *Manager) Ls(dto LsDTO) error {
 		return nil
 	}
 
+	current := m.configManager.GetCurrentWorkspace()
+	headers := []string{"", "NAME", "PROJECTS", "PATH"}
+
+	if dto.Status {
+		headers = []string{"", "NAME", "PROJECTS", "RUNNING", "PATH"}
+	}
+
+	// Once docker can't be reached, there's no point trying again for the
+	// other workspaces.
+	statusAvailable := true
+	rows := [][]string{}
+
 	for _, loc := range locs {
-		m.tui.Info(fmt.Sprintf("%s -> %s", loc.Name, loc.Path))
+		marker := ""
+		if loc.Name == current {
+			marker = "*"
+		}
+
+		path := loc.Path
+		projects := fmt.Sprintf("%d/?", m.countRegistered(loc.Name))
+		running := "?"
+
+		exists, err := afero.Exists(m.fs, loc.Path)
+
+		if err != nil || !exists {
+			path = fmt.Sprintf("%s (missing)", loc.Path)
+		} else if ws, err := m.workspaceRepo.Load(loc.Name); err != nil {
+			path = fmt.Sprintf("%s (invalid config)", loc.Path)
+		} else {
+			registered := 0
+
+			for _, p := range ws.Projects {
+				if p.IsRegistered {
+					registered++
+				}
+			}
+
+			projects = fmt.Sprintf("%d/%d", registered, len(ws.Projects))
+
+			if dto.Status && statusAvailable {
+				count, err := m.countRunning(ctx, ws)
+
+				if err != nil {
+					statusAvailable = false
+				} else {
+					running = fmt.Sprint(count)
+				}
+			}
+		}
+
+		row := []string{marker, loc.Name, projects, path}
+
+		if dto.Status {
+			row = []string{marker, loc.Name, projects, running, path}
+		}
+
+		rows = append(rows, row)
 	}
 
+	m.tui.Table(headers, rows)
+
 	return nil
 }
@@ -0,0 +1,51 @@
+package workspaces_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/workspaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Ls(t *testing.T) {
+	env := newTestEnvironment(t, `name: test
+projects:
+  - name: api
+    repository:
+      ssh: file://{root}/sources/api
+  - name: db
+    repository:
+      ssh: file://{root}/sources/db
+`)
+
+	createSourceRepo(t, filepath.Join(env.root, "sources", "api"))
+
+	err := env.manager.Clone(context.Background(), workspaces.CloneDTO{
+		WorkspaceName: "test",
+		Project:       "api",
+		To:            filepath.Join(env.root, "projects"),
+	})
+	require.Nil(t, err)
+
+	missingPath := filepath.Join(env.root, "missing", common.DefaultWorkspaceFileName)
+	require.Nil(t, env.cfg.AddWorkspace(missingPath, "gone"))
+	require.Nil(t, env.cfg.SetProjectPath("gone", "web", filepath.Join(env.root, "web")))
+	require.Nil(t, env.cfg.SwitchWorkspace("test"))
+
+	// The current workspace is only read from the config when it's loaded
+	require.Nil(t, env.cfg.LoadOrCreate())
+
+	env.tui.tables = nil
+	require.Nil(t, env.manager.Ls(context.Background(), workspaces.LsDTO{}))
+
+	assert.Equal(t, [][][]string{
+		{
+			{"*", "test", "1/2", filepath.Join(env.root, "ws", common.DefaultWorkspaceFileName)},
+			{"", "gone", "1/?", missingPath + " (missing)"},
+		},
+	}, env.tui.tables)
+}
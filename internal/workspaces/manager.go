@@ -1,7 +1,12 @@
 package workspaces
 
 import (
+	"context"
+	"sync"
+
 	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/controller"
+	"github.com/panoptescloud/orca/internal/tls"
 	"github.com/spf13/afero"
 )
 
@@ -11,7 +16,10 @@ type tui interface {
 	Info(msg ...string)
 	Error(msg ...string)
 	Success(msg ...string)
+	NewLine()
 	RecordIfError(msg string, err error) error
+	Table(headers []string, rows [][]string)
+	PresentChoices(opts []string, title string) (string, error)
 }
 
 type config interface {
@@ -20,16 +28,52 @@ type config interface {
 	GetWorkspaceMeta(name string) (common.WorkspaceMeta, error)
 	SetProjectPath(wsName string, name string, into string) error
 	ProjectExists(wsName string, name string) (bool, error)
+	GetCurrentWorkspace() string
+	GetAllProjectMeta() []common.ProjectMeta
+	SwitchWorkspace(name string) error
+	RemoveWorkspace(name string) error
+	RenameWorkspace(from string, to string) error
+	SetWorkspacePath(name string, path string) error
 }
 
 type git interface {
-	GetRepositoryRootFromPath(path string) (string, error)
-	Clone(repoUrl string, target string) error
+	GetRepositoryRootFromPath(ctx context.Context, path string) (string, error)
+	Clone(ctx context.Context, repoUrl string, target string, opts common.CloneOptions) error
+	StatusIn(ctx context.Context, dir string) (common.RepoStatus, error)
+	PullIn(ctx context.Context, dir string) error
+	CheckoutIn(ctx context.Context, dir string, branch string) (bool, error)
 }
 
 type workspaceRepo interface {
 	Load(name string) (*common.Workspace, error)
 	LoadUnconfiguredWorkspace(path string) (*common.UnconfiguredWorkspace, error)
+	Validate(name string) ([]error, error)
+}
+
+type compose interface {
+	IsRunning(ctx context.Context, ws *common.Workspace, p *common.Project) (bool, error)
+}
+
+// lifecycle stops the projects in a workspace.
+type lifecycle interface {
+	Down(ctx context.Context, dto controller.DownDTO) error
+}
+
+type certificates interface {
+	RemoveUnused(dto tls.RemoveUnusedDTO) error
+}
+
+type overlays interface {
+	RemoveOverlays(wsName string) error
+}
+
+type volumes interface {
+	Created(ctx context.Context, ws *common.Workspace, p *common.Project) ([]string, error)
+}
+
+// hooks runs the workspace and project hooks for an event.
+type hooks interface {
+	RunHooks(ctx context.Context, event common.HookEvent, wsName string, projects []string) error
 }
 
 type Manager struct {
@@ -38,6 +82,16 @@ type Manager struct {
 	configManager config
 	git           git
 	workspaceRepo workspaceRepo
+	compose       compose
+	hooks         hooks
+	lifecycle     lifecycle
+	certificates  certificates
+	overlays      overlays
+	volumes       volumes
+
+	// configMu guards changes to the config, which may happen concurrently
+	// while cloning.
+	configMu sync.Mutex
 }
 
 // func (self *Manager) loadConfigFromPath(path string) (*common.WorkspaceMeta, error) {
@@ -60,12 +114,30 @@ type Manager struct {
 // 	return self.loadConfigFromPath(loc.Path)
 // }
 
-func NewManager(fs afero.Fs, tui tui, configManager config, git git, workspaceRepo workspaceRepo) *Manager {
+func NewManager(
+	fs afero.Fs,
+	tui tui,
+	configManager config,
+	git git,
+	workspaceRepo workspaceRepo,
+	compose compose,
+	hooks hooks,
+	lifecycle lifecycle,
+	certificates certificates,
+	overlays overlays,
+	volumes volumes,
+) *Manager {
 	return &Manager{
 		fs:            fs,
 		tui:           tui,
 		configManager: configManager,
 		git:           git,
 		workspaceRepo: workspaceRepo,
+		compose:       compose,
+		hooks:         hooks,
+		lifecycle:     lifecycle,
+		certificates:  certificates,
+		overlays:      overlays,
+		volumes:       volumes,
 	}
 }
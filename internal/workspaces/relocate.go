@@ -0,0 +1,145 @@
+package workspaces
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/spf13/afero"
+)
+
+type RelocateDTO struct {
+	WorkspaceName string
+
+	// Path is either the workspace config, or the directory containing it.
+	Path string
+}
+
+func (m *Manager) resolveWorkspaceConfigPath(path string) (string, error) {
+	path, err := filepath.Abs(path)
+
+	if err != nil {
+		return "", err
+	}
+
+	isDir, err := afero.IsDir(m.fs, path)
+
+	if err == nil && isDir {
+		path = filepath.Join(path, DefaultWorkspaceFileName)
+	}
+
+	exists, err := afero.Exists(m.fs, path)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !exists {
+		return "", common.ErrWorkspaceConfigNotFound{
+			Path: path,
+		}
+	}
+
+	return path, nil
+}
+
+// Relocate updates where the config for the workspace is found, e.g. after
+// the repository containing it has been moved.
+func (m *Manager) Relocate(dto RelocateDTO) error {
+	name := dto.WorkspaceName
+
+	if name == "" {
+		name = m.configManager.GetCurrentWorkspace()
+	}
+
+	if _, err := m.configManager.GetWorkspaceMeta(name); err != nil {
+		return m.tui.RecordIfError(fmt.Sprintf("Unknown workspace: %s", name), err)
+	}
+
+	path, err := m.resolveWorkspaceConfigPath(dto.Path)
+
+	if err != nil {
+		if _, ok := err.(common.ErrWorkspaceConfigNotFound); ok {
+			return m.tui.RecordIfError(err.Error(), err)
+		}
+
+		return m.tui.RecordIfError("Failed to find config, this is likely a bug!", err)
+	}
+
+	if _, err := m.workspaceRepo.LoadUnconfiguredWorkspace(path); err != nil {
+		m.reportConfigProblem(err)
+		return m.tui.RecordIfError("Failed to open config file!", err)
+	}
+
+	if err := m.configManager.SetWorkspacePath(name, path); err != nil {
+		return m.tui.RecordIfError("Failed to update workspace, this is likely a bug!", err)
+	}
+
+	m.tui.Success(fmt.Sprintf("Workspace '%s' now uses %s", name, path))
+
+	return nil
+}
+
+type SetProjectPathDTO struct {
+	WorkspaceName string
+	Project       string
+	Path          string
+}
+
+// SetProjectPath updates where a project in the workspace is found, e.g. after
+// it has been moved, or when it was cloned without orca.
+func (m *Manager) SetProjectPath(dto SetProjectPathDTO) error {
+	name := dto.WorkspaceName
+
+	if name == "" {
+		name = m.configManager.GetCurrentWorkspace()
+	}
+
+	meta, err := m.configManager.GetWorkspaceMeta(name)
+
+	if err != nil {
+		return m.tui.RecordIfError(fmt.Sprintf("Unknown workspace: %s", name), err)
+	}
+
+	// Only the workspace config is loaded, as the project's config won't be
+	// found if it's been moved, which is most likely why its path is being set
+	ws, err := m.workspaceRepo.LoadUnconfiguredWorkspace(meta.Path)
+
+	if err != nil {
+		m.reportConfigProblem(err)
+		return m.tui.RecordIfError("Failed to load workspace!", err)
+	}
+
+	if _, err := ws.GetProject(dto.Project); err != nil {
+		return m.tui.RecordIfError(fmt.Sprintf("Project '%s' is not part of workspace '%s'", dto.Project, name), err)
+	}
+
+	path, err := filepath.Abs(dto.Path)
+
+	if err != nil {
+		return m.tui.RecordIfError("Failed to resolve path!", err)
+	}
+
+	projectConfigPath := filepath.Join(path, common.DefaultProjectFileName)
+	exists, err := afero.Exists(m.fs, projectConfigPath)
+
+	if err != nil {
+		return m.tui.RecordIfError("Failed to find config, this is likely a bug!", err)
+	}
+
+	if !exists {
+		err := common.ErrProjectConfigNotFound{
+			Path: projectConfigPath,
+		}
+
+		return m.tui.RecordIfError(err.Error(), err)
+	}
+
+	if err := m.setProjectPath(name, dto.Project, path); err != nil {
+		return m.tui.RecordIfError("Failed to update project, this is likely a bug!", err)
+	}
+
+	m.tui.Success(fmt.Sprintf("Project '%s' now uses %s", dto.Project, path))
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package workspaces_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/workspaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const relocateTestWorkspace = `name: test
+projects:
+  - name: api
+    repository:
+      ssh: git@example.com:org/api.git
+`
+
+func Test_Relocate(t *testing.T) {
+	env := newTestEnvironment(t, relocateTestWorkspace)
+
+	moved := filepath.Join(env.root, "moved")
+	require.Nil(t, os.Rename(filepath.Join(env.root, "ws"), moved))
+
+	err := env.manager.Relocate(workspaces.RelocateDTO{
+		WorkspaceName: "test",
+		Path:          filepath.Join(env.root, "missing"),
+	})
+	assert.Equal(t, common.ErrWorkspaceConfigNotFound{
+		Path: filepath.Join(env.root, "missing"),
+	}, err)
+
+	err = env.manager.Relocate(workspaces.RelocateDTO{
+		WorkspaceName: "test",
+		Path:          moved,
+	})
+	require.Nil(t, err)
+
+	meta, err := env.cfg.GetWorkspaceMeta("test")
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(moved, common.DefaultWorkspaceFileName), meta.Path)
+}
+
+func Test_SetProjectPath(t *testing.T) {
+	env := newTestEnvironment(t, relocateTestWorkspace)
+
+	projectDir := filepath.Join(env.root, "api")
+	require.Nil(t, os.MkdirAll(projectDir, 0755))
+
+	// The directory doesn't contain the project config yet
+	err := env.manager.SetProjectPath(workspaces.SetProjectPathDTO{
+		WorkspaceName: "test",
+		Project:       "api",
+		Path:          projectDir,
+	})
+	assert.Equal(t, common.ErrProjectConfigNotFound{
+		Path: filepath.Join(projectDir, common.DefaultProjectFileName),
+	}, err)
+
+	projectConfig := []byte("composeFiles:\n  primary: docker-compose.yaml\n")
+	require.Nil(t, os.WriteFile(filepath.Join(projectDir, common.DefaultProjectFileName), projectConfig, 0644))
+
+	err = env.manager.SetProjectPath(workspaces.SetProjectPathDTO{
+		WorkspaceName: "test",
+		Project:       "unknown",
+		Path:          projectDir,
+	})
+	assert.Equal(t, common.ErrUnknownProject{
+		Name: "unknown",
+	}, err)
+
+	err = env.manager.SetProjectPath(workspaces.SetProjectPathDTO{
+		WorkspaceName: "test",
+		Project:       "api",
+		Path:          projectDir,
+	})
+	require.Nil(t, err)
+
+	meta, err := env.cfg.GetProjectMeta("test", "api")
+	require.Nil(t, err)
+	assert.Equal(t, projectDir, meta.Path)
+}
+
+func Test_SetProjectPath_Moved(t *testing.T) {
+	env := newTestEnvironment(t, relocateTestWorkspace)
+
+	projectConfig := []byte("composeFiles:\n  primary: docker-compose.yaml\n")
+	oldDir := filepath.Join(env.root, "api")
+	require.Nil(t, os.MkdirAll(oldDir, 0755))
+	require.Nil(t, os.WriteFile(filepath.Join(oldDir, common.DefaultProjectFileName), projectConfig, 0644))
+	require.Nil(t, env.cfg.SetProjectPath("test", "api", oldDir))
+
+	// The checkout is moved, so the workspace can no longer be loaded
+	newDir := filepath.Join(env.root, "src", "api")
+	require.Nil(t, os.MkdirAll(filepath.Dir(newDir), 0755))
+	require.Nil(t, os.Rename(oldDir, newDir))
+
+	err := env.manager.SetProjectPath(workspaces.SetProjectPathDTO{
+		WorkspaceName: "test",
+		Project:       "api",
+		Path:          newDir,
+	})
+	require.Nil(t, err)
+
+	meta, err := env.cfg.GetProjectMeta("test", "api")
+	require.Nil(t, err)
+	assert.Equal(t, newDir, meta.Path)
+}
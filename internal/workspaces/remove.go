@@ -0,0 +1,79 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/panoptescloud/orca/internal/controller"
+	"github.com/panoptescloud/orca/internal/tls"
+)
+
+type RemoveDTO struct {
+	WorkspaceName string
+
+	// Down stops every project in the workspace before it's removed.
+	Down bool
+
+	// Clean removes the overlays, and any certificates that aren't used by
+	// another workspace.
+	Clean bool
+}
+
+// clean removes what orca generated for the workspace. The certificates of
+// other workspaces are checked, so that any they share are kept.
+func (m *Manager) clean(wsName string) error {
+	others := []string{}
+
+	for _, ws := range m.configManager.GetAllWorkspaceMeta() {
+		if ws.Name != wsName {
+			others = append(others, ws.Name)
+		}
+	}
+
+	err := m.certificates.RemoveUnused(tls.RemoveUnusedDTO{
+		WorkspaceName:   wsName,
+		OtherWorkspaces: others,
+	})
+
+	if err != nil {
+		return m.tui.RecordIfError("Failed to remove certificates, run without --clean to only remove the workspace.", err)
+	}
+
+	if err := m.overlays.RemoveOverlays(wsName); err != nil {
+		return m.tui.RecordIfError("Failed to remove overlays!", err)
+	}
+
+	return nil
+}
+
+// Remove forgets about the workspace, the projects themselves are left on
+// disk.
+func (m *Manager) Remove(ctx context.Context, dto RemoveDTO) error {
+	if _, err := m.configManager.GetWorkspaceMeta(dto.WorkspaceName); err != nil {
+		return m.tui.RecordIfError(fmt.Sprintf("Unknown workspace: %s", dto.WorkspaceName), err)
+	}
+
+	if dto.Down {
+		err := m.lifecycle.Down(ctx, controller.DownDTO{
+			Workspace: dto.WorkspaceName,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if dto.Clean {
+		if err := m.clean(dto.WorkspaceName); err != nil {
+			return err
+		}
+	}
+
+	if err := m.configManager.RemoveWorkspace(dto.WorkspaceName); err != nil {
+		return m.tui.RecordIfError("Failed to remove workspace, this is likely a bug!", err)
+	}
+
+	m.tui.Success(fmt.Sprintf("Removed workspace '%s', the projects have been left on disk.", dto.WorkspaceName))
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package workspaces_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/workspaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Remove(t *testing.T) {
+	tests := []struct {
+		name        string
+		dto         workspaces.RemoveDTO
+		expectCalls []string
+	}{
+		{
+			name: "only forgets the workspace",
+			dto: workspaces.RemoveDTO{
+				WorkspaceName: "test",
+			},
+		},
+		{
+			name: "down and clean",
+			dto: workspaces.RemoveDTO{
+				WorkspaceName: "test",
+				Down:          true,
+				Clean:         true,
+			},
+			expectCalls: []string{
+				"down test",
+				"remove certificates test, keeping other",
+				"remove overlays test",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			env := newTestEnvironment(tt, "name: test\n")
+			require.Nil(tt, env.cfg.AddWorkspace(filepath.Join(env.root, "other.yaml"), "other"))
+
+			require.Nil(tt, env.manager.Remove(context.Background(), test.dto))
+
+			assert.Equal(tt, test.expectCalls, env.docker.calls)
+
+			_, err := env.cfg.GetWorkspaceMeta("test")
+			assert.Equal(tt, common.ErrUnknownWorkspace{Name: "test"}, err)
+		})
+	}
+}
+
+func Test_Remove_Unknown(t *testing.T) {
+	env := newTestEnvironment(t, "name: test\n")
+
+	err := env.manager.Remove(context.Background(), workspaces.RemoveDTO{
+		WorkspaceName: "missing",
+		Clean:         true,
+	})
+
+	assert.Equal(t, common.ErrUnknownWorkspace{Name: "missing"}, err)
+	assert.Contains(t, env.tui.lines, "Unknown workspace: missing")
+	// Nothing is cleaned up for a workspace that doesn't exist
+	assert.Empty(t, env.docker.calls)
+}
@@ -0,0 +1,115 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+type RenameDTO struct {
+	From string
+	To   string
+}
+
+// checkCanRename makes sure none of the workspace's projects are running, and
+// returns the volumes compose has created for them. Both are named after the
+// workspace, so wouldn't be found once it's renamed. If docker isn't available
+// then nothing can be running, so the rename goes ahead anyway.
+func (m *Manager) checkCanRename(ctx context.Context, wsName string) ([]string, error) {
+	ws, err := m.workspaceRepo.Load(wsName)
+
+	if err != nil {
+		if _, ok := err.(common.ErrUnknownWorkspace); ok {
+			return nil, m.tui.RecordIfError(fmt.Sprintf("Unknown workspace: %s", wsName), err)
+		}
+
+		m.tui.Info(fmt.Sprintf("Could not load workspace '%s' to check if it's running, continuing anyway.", wsName))
+
+		return nil, nil
+	}
+
+	running := []string{}
+	volumes := []string{}
+
+	for _, p := range ws.Projects {
+		if !p.IsRegistered {
+			continue
+		}
+
+		isRunning, err := m.compose.IsRunning(ctx, ws, &p)
+
+		if err != nil {
+			m.tui.Info(fmt.Sprintf("Could not check if workspace '%s' is running, continuing anyway.", wsName))
+
+			return nil, nil
+		}
+
+		if isRunning {
+			running = append(running, p.Name)
+			continue
+		}
+
+		created, err := m.volumes.Created(ctx, ws, &p)
+
+		if err != nil {
+			m.tui.Info(fmt.Sprintf("Could not check the volumes of '%s', continuing anyway.", p.Name))
+			continue
+		}
+
+		volumes = append(volumes, created...)
+	}
+
+	if len(running) > 0 {
+		return nil, m.tui.RecordIfError(
+			fmt.Sprintf("Workspace '%s' has running projects (%s), stop them with 'orca down -w %s' first.", wsName, strings.Join(running, ", "), wsName),
+			common.ErrInvalidExecutionContext{
+				Msg: "cannot rename a running workspace",
+			},
+		)
+	}
+
+	return volumes, nil
+}
+
+// Rename changes the name the workspace is registered under. It must be
+// stopped first, as the compose projects are named after it.
+func (m *Manager) Rename(ctx context.Context, dto RenameDTO) error {
+	if dto.To == "" {
+		return m.tui.RecordIfError("Must supply a new name!", common.ErrArgumentRequired{
+			Name:  "new name",
+			Index: 1,
+		})
+	}
+
+	volumes, err := m.checkCanRename(ctx, dto.From)
+
+	if err != nil {
+		return err
+	}
+
+	err = m.configManager.RenameWorkspace(dto.From, dto.To)
+
+	if err != nil {
+		switch err.(type) {
+		case common.ErrUnknownWorkspace:
+			return m.tui.RecordIfError(fmt.Sprintf("Unknown workspace: %s", dto.From), err)
+		case common.ErrWorkspaceAlreadyExists:
+			return m.tui.RecordIfError(fmt.Sprintf("Workspace '%s' already exists!", dto.To), err)
+		}
+
+		return m.tui.RecordIfError("Failed to rename workspace, this is likely a bug!", err)
+	}
+
+	m.tui.Success(fmt.Sprintf("Renamed workspace '%s' to '%s'", dto.From, dto.To))
+
+	if len(volumes) > 0 {
+		m.tui.Info(
+			fmt.Sprintf("These volumes are named after '%s', so won't be used by '%s': %s", dto.From, dto.To, strings.Join(volumes, ", ")),
+			fmt.Sprintf("Rename it back to '%s' to use them again, or remove them if they're no longer needed.", dto.From),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package workspaces_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/workspaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Rename(t *testing.T) {
+	tests := []struct {
+		name        string
+		running     []string
+		volumes     map[string][]string
+		unavailable bool
+		expectErr   error
+		expectName  string
+		expectLines []string
+	}{
+		{
+			name:       "stopped",
+			expectName: "renamed",
+			expectLines: []string{
+				"Renamed workspace 'test' to 'renamed'",
+			},
+		},
+		{
+			name:      "running",
+			running:   []string{"api"},
+			expectErr: common.ErrInvalidExecutionContext{Msg: "cannot rename a running workspace"},
+			expectLines: []string{
+				"Workspace 'test' has running projects (api), stop them with 'orca down -w test' first.",
+			},
+			expectName: "test",
+		},
+		{
+			name: "volumes are left behind",
+			volumes: map[string][]string{
+				"api": {"orca-test-api_pgdata"},
+			},
+			expectName: "renamed",
+			expectLines: []string{
+				"Renamed workspace 'test' to 'renamed'",
+				"These volumes are named after 'test', so won't be used by 'renamed': orca-test-api_pgdata",
+				"Rename it back to 'test' to use them again, or remove them if they're no longer needed.",
+			},
+		},
+		{
+			name:        "docker is unavailable",
+			unavailable: true,
+			expectName:  "renamed",
+			expectLines: []string{
+				"Could not check if workspace 'test' is running, continuing anyway.",
+				"Renamed workspace 'test' to 'renamed'",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			env := newTestEnvironment(tt, `name: test
+projects:
+  - name: api
+  - name: web
+`)
+			apiDir := filepath.Join(env.root, "api")
+			require.Nil(tt, os.MkdirAll(apiDir, 0755))
+			require.Nil(tt, os.WriteFile(filepath.Join(apiDir, common.DefaultProjectFileName), []byte("composeFiles:\n  primary: docker-compose.yaml\n"), 0644))
+			require.Nil(tt, env.cfg.SetProjectPath("test", "api", apiDir))
+
+			env.docker.running = test.running
+			env.docker.volumes = test.volumes
+			env.docker.unavailable = test.unavailable
+
+			err := env.manager.Rename(context.Background(), workspaces.RenameDTO{
+				From: "test",
+				To:   "renamed",
+			})
+
+			assert.Equal(tt, test.expectErr, err)
+			assert.Equal(tt, test.expectLines, env.tui.lines)
+
+			meta, err := env.cfg.GetWorkspaceMeta(test.expectName)
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expectName, meta.Name)
+		})
+	}
+}
+
+func Test_Rename_Unknown(t *testing.T) {
+	env := newTestEnvironment(t, "name: test\n")
+
+	err := env.manager.Rename(context.Background(), workspaces.RenameDTO{
+		From: "missing",
+		To:   "renamed",
+	})
+
+	assert.Equal(t, common.ErrUnknownWorkspace{Name: "missing"}, err)
+	assert.Equal(t, []string{"Unknown workspace: missing"}, env.tui.lines)
+}
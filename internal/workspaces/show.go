@@ -0,0 +1,106 @@
+package workspaces
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+type ShowDTO struct {
+	WorkspaceName string
+}
+
+func orNone(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func describeNetworkOverlay(cfg common.NetworkOverlayConfig) string {
+	if !cfg.Enabled {
+		return "disabled"
+	}
+
+	desc := fmt.Sprintf("enabled, created in '%s'", cfg.CreateIn)
+
+	if cfg.DisableAliases {
+		return desc + ", aliases disabled"
+	}
+
+	if cfg.AliasPattern != "" {
+		return fmt.Sprintf("%s, alias pattern '%s'", desc, cfg.AliasPattern)
+	}
+
+	return desc
+}
+
+// Show prints the details of a single workspace, and each of its projects.
+func (m *Manager) Show(dto ShowDTO) error {
+	name := dto.WorkspaceName
+
+	if name == "" {
+		name = m.configManager.GetCurrentWorkspace()
+	}
+
+	ws, err := m.workspaceRepo.Load(name)
+
+	if err != nil {
+		if _, ok := err.(common.ErrUnknownWorkspace); ok {
+			return m.tui.RecordIfError(fmt.Sprintf("Unknown workspace: %s", name), err)
+		}
+
+		m.reportConfigProblem(err)
+		return m.tui.RecordIfError("Failed to load workspace!", err)
+	}
+
+	m.tui.Table([]string{"WORKSPACE", name}, [][]string{
+		{"Config", ws.ConfigPath},
+		{"Network overlay", describeNetworkOverlay(ws.OverlayConfig.Network)},
+	})
+
+	if len(ws.Profiles) > 0 {
+		profiles := make([]string, 0, len(ws.Profiles))
+		for p := range ws.Profiles {
+			profiles = append(profiles, p)
+		}
+		slices.Sort(profiles)
+
+		rows := make([][]string, len(profiles))
+
+		for i, p := range profiles {
+			rows[i] = []string{p, orNone(ws.Profiles[p])}
+		}
+
+		m.tui.NewLine()
+		m.tui.Table([]string{"PROFILE", "PROJECTS"}, rows)
+	}
+
+	rows := make([][]string, len(ws.Projects))
+
+	for i, p := range ws.Projects {
+		path := p.ProjectDir
+		if !p.IsRegistered {
+			path = "(not cloned)"
+		}
+
+		repo := p.RepositoryConfig.SSH
+		if p.RepositoryConfig.Self {
+			repo = "(workspace repository)"
+		}
+
+		if p.RepositoryConfig.Branch != "" {
+			repo = fmt.Sprintf("%s [%s]", repo, p.RepositoryConfig.Branch)
+		}
+
+		rows[i] = []string{p.Name, path, repo, orNone(p.Requires)}
+	}
+
+	m.tui.NewLine()
+	m.tui.Table([]string{"PROJECT", "PATH", "REPOSITORY", "REQUIRES"}, rows)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+type SwitchDTO struct {
+	// WorkspaceName to switch to, if empty the user is asked to choose one.
+	WorkspaceName string
+}
+
+func (m *Manager) chooseWorkspace() (string, error) {
+	locs := m.configManager.GetAllWorkspaceMeta()
+
+	if len(locs) == 0 {
+		m.tui.Error("No workspaces exist!")
+
+		return "", common.ErrUnknownWorkspace{
+			Name: "",
+		}
+	}
+
+	names := make([]string, len(locs))
+
+	for i, loc := range locs {
+		names[i] = loc.Name
+	}
+
+	chosen, err := m.tui.PresentChoices(names, "Which workspace?")
+
+	if err != nil {
+		if _, ok := err.(common.ErrUserAbortedExecution); ok {
+			return "", err
+		}
+
+		return "", m.tui.RecordIfError("Something went wrong, this is most likely a bug!", err)
+	}
+
+	return chosen, nil
+}
+
+func (m *Manager) Switch(ctx context.Context, dto SwitchDTO) error {
+	name := dto.WorkspaceName
+
+	if name == "" {
+		chosen, err := m.chooseWorkspace()
+
+		if err != nil {
+			return err
+		}
+
+		name = chosen
+	}
+
+	if err := m.configManager.SwitchWorkspace(name); err != nil {
+		return m.tui.RecordIfError("Failed to switch workspace!", err)
+	}
+
+	m.tui.Success(fmt.Sprintf("Switched to %s", name))
+
+	return m.hooks.RunHooks(ctx, common.HookPostSwitch, name, nil)
+}
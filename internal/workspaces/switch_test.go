@@ -0,0 +1,73 @@
+package workspaces_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/panoptescloud/orca/internal/common"
+	"github.com/panoptescloud/orca/internal/config"
+	"github.com/panoptescloud/orca/internal/workspaces"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Switch(t *testing.T) {
+	tests := []struct {
+		name          string
+		workspaceName string
+		choice        string
+		expect        string
+		expectErr     error
+		expectHooks   []hookRun
+	}{
+		{
+			name:          "given a workspace",
+			workspaceName: "other",
+			expect:        "other",
+			expectHooks: []hookRun{
+				{event: common.HookPostSwitch, workspace: "other"},
+			},
+		},
+		{
+			name:   "chosen by the user",
+			choice: "other",
+			expect: "other",
+			expectHooks: []hookRun{
+				{event: common.HookPostSwitch, workspace: "other"},
+			},
+		},
+		{
+			name:      "user aborts",
+			expect:    "",
+			expectErr: common.ErrUserAbortedExecution{},
+		},
+		{
+			name:          "unknown workspace",
+			workspaceName: "missing",
+			expect:        "",
+			expectErr: common.ErrUnknownWorkspace{
+				Name: "missing",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			env := newTestEnvironment(tt, "name: test\n")
+			require.Nil(tt, env.cfg.AddWorkspace(filepath.Join(env.root, "other.yaml"), "other"))
+			env.tui.choice = test.choice
+
+			err := env.manager.Switch(context.Background(), workspaces.SwitchDTO{
+				WorkspaceName: test.workspaceName,
+			})
+			assert.Equal(tt, test.expectErr, err)
+			assert.Equal(tt, test.expectHooks, env.hooks.runs)
+
+			reloaded := config.NewDefaultConfig(afero.NewOsFs(), filepath.Join(env.root, "orca.yaml"))
+			require.Nil(tt, reloaded.LoadOrCreate())
+			assert.Equal(tt, test.expect, reloaded.GetCurrentWorkspace())
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package workspaces
+
+import (
+	"fmt"
+
+	"github.com/panoptescloud/orca/internal/common"
+)
+
+type ValidateDTO struct {
+	WorkspaceName string
+}
+
+func (m *Manager) reportConfigProblem(err error) {
+	if invalid, ok := err.(common.ErrInvalidConfig); ok {
+		for _, line := range invalid.Lines() {
+			m.tui.Error(line)
+		}
+
+		return
+	}
+
+	m.tui.Error(err.Error())
+}
+
+func (m *Manager) Validate(dto ValidateDTO) error {
+	name := dto.WorkspaceName
+
+	if name == "" {
+		name = m.configManager.GetCurrentWorkspace()
+	}
+
+	problems, err := m.workspaceRepo.Validate(name)
+
+	if err != nil {
+		if _, ok := err.(common.ErrUnknownWorkspace); ok {
+			return m.tui.RecordIfError(fmt.Sprintf("Unknown workspace: %s", name), err)
+		}
+
+		return m.tui.RecordIfError("Failed to validate workspace, this is likely a bug!", err)
+	}
+
+	if len(problems) == 0 {
+		m.tui.Success(fmt.Sprintf("Workspace '%s' is valid!", name))
+		return nil
+	}
+
+	for _, p := range problems {
+		m.reportConfigProblem(p)
+	}
+
+	return problems[0]
+}
@@ -24,6 +24,22 @@ func (err ErrVertexNotFoundForEdge) Error() string {
 	return fmt.Sprintf("cannot add %s '%s' to '%s'", err.Type, err.Missing, err.Source)
 }
 
+type ErrVertexNotFound struct {
+	Key string
+}
+
+func (err ErrVertexNotFound) Error() string {
+	return fmt.Sprintf("vertex with key '%s' not found in graph", err.Key)
+}
+
+type ErrCycleDetected struct {
+	Keys []string
+}
+
+func (err ErrCycleDetected) Error() string {
+	return fmt.Sprintf("cycle detected between: %s", strings.Join(err.Keys, ", "))
+}
+
 type Graphable interface {
 	GetKey() string
 	GetChildren() []string
@@ -125,7 +141,7 @@ func (g *Graph) RemoveVertex(key string) {
 // and 'to' is the child.
 // It fills in both sides, so we'll add a child to the parent 'from' pointing
 // at 'to'. And we'll add a parent to the child 'to' pointing at 'from'.
-// TODO: Check for cycles here to ensure its acylic.
+// Cycles are not checked for here, they are reported when sorting the graph.
 func (g *Graph) AddEdge(from string, to string) error {
 	source := g.GetVertex(from)
 	destination := g.GetVertex(to)
@@ -187,6 +203,100 @@ func (g *Graph) Roots() []*Vertex {
 	return roots
 }
 
+// collectKeys walks from each of the given vertices using next, and returns
+// the keys of every vertex that is reached, sorted.
+func (g *Graph) collectKeys(keys []string, next func(v *Vertex) Vertices) ([]string, error) {
+	seen := map[string]bool{}
+	queue := []*Vertex{}
+
+	for _, k := range keys {
+		v := g.GetVertex(k)
+
+		if v == nil {
+			return nil, ErrVertexNotFound{
+				Key: k,
+			}
+		}
+
+		queue = append(queue, v)
+	}
+
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+
+		for k, other := range next(v) {
+			if seen[k] {
+				continue
+			}
+
+			seen[k] = true
+			queue = append(queue, other)
+		}
+	}
+
+	found := make([]string, 0, len(seen))
+
+	for k := range seen {
+		found = append(found, k)
+	}
+
+	slices.Sort(found)
+
+	return found, nil
+}
+
+// Ancestors returns the keys of all parents of the given vertices, and their
+// parents and so on.
+func (g *Graph) Ancestors(keys ...string) ([]string, error) {
+	return g.collectKeys(keys, func(v *Vertex) Vertices {
+		return v.Parents
+	})
+}
+
+// Descendants returns the keys of all children of the given vertices, and
+// their children and so on.
+func (g *Graph) Descendants(keys ...string) ([]string, error) {
+	return g.collectKeys(keys, func(v *Vertex) Vertices {
+		return v.Children
+	})
+}
+
+// Subgraph creates a new graph containing only the given vertices, along with
+// any edges between them.
+func (g *Graph) Subgraph(keys []string) (*Graph, error) {
+	new := &Graph{
+		vertices: Vertices{},
+	}
+
+	for _, k := range keys {
+		if g.GetVertex(k) == nil {
+			return nil, ErrVertexNotFound{
+				Key: k,
+			}
+		}
+
+		if new.GetVertex(k) != nil {
+			continue
+		}
+
+		if err := new.AddVertex(k); err != nil {
+			return nil, err
+		}
+	}
+
+	for k, v := range new.vertices {
+		for childKey := range g.GetVertex(k).Children {
+			if child := new.GetVertex(childKey); child != nil {
+				v.AddChild(childKey, child)
+				child.AddParent(k, v)
+			}
+		}
+	}
+
+	return new, nil
+}
+
 func (g *Graph) clone() (*Graph, error) {
 	new := &Graph{
 		vertices: Vertices{},
@@ -235,6 +345,27 @@ func (g *Graph) pruneRoots() []*Vertex {
 	return roots
 }
 
+// remainingKeysAsCycleError is used once the graph can no longer be reduced
+// any further. If any vertices remain they must be part of, or depend upon, a
+// cycle.
+func (g *Graph) remainingKeysAsCycleError() error {
+	if len(g.vertices) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(g.vertices))
+
+	for k := range g.vertices {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return ErrCycleDetected{
+		Keys: keys,
+	}
+}
+
 func (g *Graph) TopologicalKeysFromLeaves() ([]string, error) {
 	new, err := g.clone()
 	if err != nil {
@@ -253,6 +384,10 @@ func (g *Graph) TopologicalKeysFromLeaves() ([]string, error) {
 		sorted = append(sorted, leaves...)
 	}
 
+	if err := new.remainingKeysAsCycleError(); err != nil {
+		return nil, err
+	}
+
 	keys := make([]string, len(sorted))
 
 	for i, v := range sorted {
@@ -280,6 +415,10 @@ func (g *Graph) TopologicalKeysFromRoots() ([]string, error) {
 		sorted = append(sorted, roots...)
 	}
 
+	if err := new.remainingKeysAsCycleError(); err != nil {
+		return nil, err
+	}
+
 	keys := make([]string, len(sorted))
 
 	for i, v := range sorted {
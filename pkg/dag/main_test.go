@@ -47,6 +47,18 @@ func Test_ErrorMessages(t *testing.T) {
 	}
 
 	assert.Equal(t, "cannot add child 'meh' to 'blah'", errVertexNotFoundForEdge.Error())
+
+	errCycleDetected := ErrCycleDetected{
+		Keys: []string{"a", "b"},
+	}
+
+	assert.Equal(t, "cycle detected between: a, b", errCycleDetected.Error())
+
+	errVertexNotFound := ErrVertexNotFound{
+		Key: "blah",
+	}
+
+	assert.Equal(t, "vertex with key 'blah' not found in graph", errVertexNotFound.Error())
 }
 
 func Test_NewGraph(t *testing.T) {
@@ -538,3 +550,161 @@ func Test_TopologicalKeysFromRoots(t *testing.T) {
 	assert.Equal(t, roots, g.Roots())
 	assert.Equal(t, leaves, g.Leaves())
 }
+
+func getCyclicGraph() []graphable {
+	return []graphable{
+		{
+			key: "v1",
+		},
+		{
+			key:     "v2",
+			parents: []string{"v1", "v3"},
+		},
+		{
+			key:     "v3",
+			parents: []string{"v2"},
+		},
+		{
+			key:     "v4",
+			parents: []string{"v3"},
+		},
+	}
+}
+
+func Test_TopologicalKeys_DetectsCycles(t *testing.T) {
+	g, err := NewGraph(getCyclicGraph())
+
+	require.Nil(t, err)
+
+	_, err = g.TopologicalKeysFromRoots()
+
+	assert.Equal(t, ErrCycleDetected{
+		Keys: []string{"v2", "v3", "v4"},
+	}, err)
+
+	_, err = g.TopologicalKeysFromLeaves()
+
+	assert.Equal(t, ErrCycleDetected{
+		Keys: []string{"v1", "v2", "v3"},
+	}, err)
+}
+
+func Test_Graph_Ancestors(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      []string
+		expect    []string
+		expectErr error
+	}{
+		{
+			name:   "root has no ancestors",
+			keys:   []string{"v1"},
+			expect: []string{},
+		},
+		{
+			name:   "transitive parents",
+			keys:   []string{"v6"},
+			expect: []string{"v1", "v2", "v3", "v4", "v5"},
+		},
+		{
+			name:   "multiple keys",
+			keys:   []string{"v4", "v5"},
+			expect: []string{"v1", "v2", "v3"},
+		},
+		{
+			name:      "unknown key",
+			keys:      []string{"v1", "nope"},
+			expectErr: ErrVertexNotFound{Key: "nope"},
+		},
+	}
+
+	g, err := NewGraph(getComplexGraph())
+
+	require.Nil(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			keys, err := g.Ancestors(test.keys...)
+
+			if test.expectErr != nil {
+				assert.Equal(tt, test.expectErr, err)
+				return
+			}
+
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expect, keys)
+		})
+	}
+}
+
+func Test_Graph_Descendants(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      []string
+		expect    []string
+		expectErr error
+	}{
+		{
+			name:   "leaf has no descendants",
+			keys:   []string{"v6"},
+			expect: []string{},
+		},
+		{
+			name:   "transitive children",
+			keys:   []string{"v1"},
+			expect: []string{"v3", "v4", "v6"},
+		},
+		{
+			name:   "multiple keys",
+			keys:   []string{"v2", "v4"},
+			expect: []string{"v5", "v6"},
+		},
+		{
+			name:      "unknown key",
+			keys:      []string{"nope"},
+			expectErr: ErrVertexNotFound{Key: "nope"},
+		},
+	}
+
+	g, err := NewGraph(getComplexGraph())
+
+	require.Nil(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			keys, err := g.Descendants(test.keys...)
+
+			if test.expectErr != nil {
+				assert.Equal(tt, test.expectErr, err)
+				return
+			}
+
+			require.Nil(tt, err)
+			assert.Equal(tt, test.expect, keys)
+		})
+	}
+}
+
+func Test_Graph_Subgraph(t *testing.T) {
+	g, err := NewGraph(getComplexGraph())
+
+	require.Nil(t, err)
+
+	sub, err := g.Subgraph([]string{"v1", "v3", "v4", "v6"})
+
+	require.Nil(t, err)
+
+	keys, err := sub.TopologicalKeysFromRoots()
+
+	require.Nil(t, err)
+
+	assert.Equal(t, []string{"v1", "v3", "v4", "v6"}, keys)
+
+	// Edges to vertices outside the subgraph are dropped
+	assert.Len(t, sub.GetVertex("v6").Parents, 1)
+	assert.Len(t, g.GetVertex("v6").Parents, 2)
+
+	_, err = g.Subgraph([]string{"nope"})
+
+	assert.Equal(t, ErrVertexNotFound{Key: "nope"}, err)
+}
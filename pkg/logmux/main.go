@@ -0,0 +1,162 @@
+// Package logmux combines the output of several concurrent sources of logs
+// into one stream, prefixing each line with where it came from.
+package logmux
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Line is a single line of output, and the prefix it is shown with.
+type Line struct {
+	Prefix string `json:"prefix"`
+	Text   string `json:"text"`
+
+	// Time is when the line was read, it's only set once the line has been
+	// recorded.
+	Time time.Time `json:"time,omitzero"`
+}
+
+// Filter decides whether a line is shown.
+type Filter func(Line) bool
+
+// Sink receives every line that passes the filter. Lines are never written to
+// it concurrently.
+type Sink interface {
+	WriteLine(Line) error
+}
+
+// reserver is implemented by sinks that align their output, so they can make
+// room for every source up front.
+type reserver interface {
+	reserve(prefix string)
+}
+
+// Source produces logs. Start should write to w until there are no more logs
+// (or it fails), and only return once it's done.
+type Source struct {
+	Name  string
+	Start func(w io.Writer) error
+
+	// Parse turns the raw output into a line, e.g. if the source includes its
+	// own prefix. When nil, lines are prefixed with the name of the source.
+	Parse func(raw string) Line
+}
+
+func (s Source) parse(raw string) Line {
+	if s.Parse == nil {
+		return Line{
+			Prefix: s.Name,
+			Text:   raw,
+		}
+	}
+
+	return s.Parse(raw)
+}
+
+// lockedSink serialises the writes from each source.
+type lockedSink struct {
+	mu   sync.Mutex
+	sink Sink
+}
+
+func (s *lockedSink) WriteLine(line Line) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sink.WriteLine(line)
+}
+
+func read(source Source, r io.Reader, filter Filter, sink Sink) error {
+	scanner := bufio.NewScanner(r)
+	// Some services log very long lines, e.g. stack traces as json
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := source.parse(scanner.Text())
+
+		if filter != nil && !filter(line) {
+			continue
+		}
+
+		if err := sink.WriteLine(line); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Stream starts every source, and writes their lines to the sink until they
+// have all finished. Any that fail don't stop the others, their errors are
+// returned together at the end.
+func Stream(sources []Source, filter Filter, sink Sink) error {
+	errs := make([]error, len(sources))
+	locked := &lockedSink{sink: sink}
+	wg := sync.WaitGroup{}
+
+	if r, ok := sink.(reserver); ok {
+		for _, source := range sources {
+			r.reserve(source.Name)
+		}
+	}
+
+	for i, source := range sources {
+		r, w := io.Pipe()
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			err := source.Start(w)
+			w.CloseWithError(err)
+
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", source.Name, err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			// Keep draining the pipe even if writing fails, so the source isn't
+			// left blocked.
+			if err := read(source, r, filter, locked); err != nil {
+				_, _ = io.Copy(io.Discard, r)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Replay writes lines that have already been read, e.g. ones that were
+// recorded earlier, to the sink.
+func Replay(lines []Line, filter Filter, sink Sink) error {
+	if r, ok := sink.(reserver); ok {
+		for _, line := range lines {
+			if filter == nil || filter(line) {
+				r.reserve(line.Prefix)
+			}
+		}
+	}
+
+	for _, line := range lines {
+		if filter != nil && !filter(line) {
+			continue
+		}
+
+		if err := sink.WriteLine(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package logmux_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/panoptescloud/orca/pkg/logmux"
+	"github.com/stretchr/testify/assert"
+)
+
+func writesLines(lines ...string) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		for _, l := range lines {
+			if _, err := fmt.Fprintln(w, l); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func sortedLines(out string) []string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	sort.Strings(lines)
+
+	return lines
+}
+
+func Test_Stream_PrefixesEachSource(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	err := logmux.Stream([]logmux.Source{
+		{Name: "api", Start: writesLines("one", "two")},
+		{Name: "db", Start: writesLines("three")},
+	}, nil, logmux.NewTerminal(out))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"api | one",
+		"api | two",
+		"db  | three",
+	}, sortedLines(out.String()))
+}
+
+func Test_Stream_PadsToTheLongestPrefix(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	err := logmux.Stream([]logmux.Source{
+		{Name: "api", Start: writesLines("api/php | one", "api/nginx | two", "api/php | three")},
+	}, nil, logmux.NewTerminal(out))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "api | api/php | one\napi | api/nginx | two\napi | api/php | three\n", out.String())
+
+	out.Reset()
+
+	err = logmux.Stream([]logmux.Source{
+		{
+			Name:  "api",
+			Start: writesLines("php | one", "nginx | two", "php | three"),
+			Parse: func(raw string) logmux.Line {
+				prefix, text, _ := strings.Cut(raw, " | ")
+
+				return logmux.Line{Prefix: "api/" + prefix, Text: text}
+			},
+		},
+	}, nil, logmux.NewTerminal(out))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "api/php | one\napi/nginx | two\napi/php   | three\n", out.String())
+}
+
+func Test_Stream_Filters(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	err := logmux.Stream([]logmux.Source{
+		{Name: "api", Start: writesLines("GET /health", "POST /users", "GET /users")},
+		{Name: "db", Start: writesLines("GET is not sql")},
+	}, func(l logmux.Line) bool {
+		return l.Prefix == "api" && strings.HasPrefix(l.Text, "GET")
+	}, logmux.NewTerminal(out))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "api | GET /health\napi | GET /users\n", out.String())
+}
+
+func Test_Stream_CollectsErrors(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	err := logmux.Stream([]logmux.Source{
+		{
+			Name: "api",
+			Start: func(w io.Writer) error {
+				fmt.Fprintln(w, "starting")
+
+				return errors.New("exit status 1")
+			},
+		},
+		{Name: "db", Start: writesLines("ready")},
+	}, nil, logmux.NewTerminal(out))
+
+	assert.EqualError(t, err, "api: exit status 1")
+	assert.Equal(t, []string{"api | starting", "db  | ready"}, sortedLines(out.String()))
+}
+
+func Test_Replay(t *testing.T) {
+	out := &bytes.Buffer{}
+	recordedAt := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	err := logmux.Replay([]logmux.Line{
+		{Prefix: "api/php", Text: "one", Time: recordedAt},
+		{Prefix: "api/nginx", Text: "two", Time: recordedAt},
+		{Prefix: "api/php", Text: "three"},
+	}, func(l logmux.Line) bool {
+		return l.Text != "two"
+	}, logmux.NewTerminal(out))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "api/php | 15:04:05.000 one\napi/php | three\n", out.String())
+}
@@ -0,0 +1,77 @@
+package logmux
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// palette is cycled through to give each prefix a distinct colour. Red is
+// left out, as it tends to be read as an error.
+var palette = []lipgloss.Color{
+	lipgloss.Color("6"),
+	lipgloss.Color("3"),
+	lipgloss.Color("2"),
+	lipgloss.Color("5"),
+	lipgloss.Color("4"),
+	lipgloss.Color("14"),
+	lipgloss.Color("11"),
+	lipgloss.Color("10"),
+	lipgloss.Color("13"),
+	lipgloss.Color("12"),
+}
+
+// Terminal writes lines for a person to read, with the prefixes aligned and
+// each in a different colour.
+type Terminal struct {
+	out      io.Writer
+	renderer *lipgloss.Renderer
+
+	width  int
+	styles map[string]lipgloss.Style
+}
+
+// reserve makes room for the prefix, so the output lines up from the start.
+func (t *Terminal) reserve(prefix string) {
+	t.width = max(t.width, len(prefix))
+}
+
+func (t *Terminal) styleFor(prefix string) lipgloss.Style {
+	style, ok := t.styles[prefix]
+
+	if !ok {
+		style = t.renderer.NewStyle().Foreground(palette[len(t.styles)%len(palette)])
+		t.styles[prefix] = style
+	}
+
+	return style
+}
+
+func (t *Terminal) WriteLine(line Line) error {
+	// Prefixes are padded to the longest seen so far, so the output lines up
+	// once every source has written something.
+	t.reserve(line.Prefix)
+	prefix := t.styleFor(line.Prefix).Render(line.Prefix + strings.Repeat(" ", t.width-len(line.Prefix)))
+
+	text := line.Text
+
+	if !line.Time.IsZero() {
+		text = fmt.Sprintf("%s %s", line.Time.Format("15:04:05.000"), text)
+	}
+
+	_, err := fmt.Fprintf(t.out, "%s | %s\n", prefix, text)
+
+	return err
+}
+
+// NewTerminal creates a sink writing to out. Colours are only used when out
+// supports them, e.g. not when piped to a file.
+func NewTerminal(out io.Writer) *Terminal {
+	return &Terminal{
+		out:      out,
+		renderer: lipgloss.NewRenderer(out),
+		styles:   map[string]lipgloss.Style{},
+	}
+}
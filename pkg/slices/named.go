@@ -44,3 +44,13 @@ func UpsertNamedElement[T namedElement](items []T, item T) []T {
 
 	return slices.Concat(left, []T{item}, right)
 }
+
+func RemoveNamedElement[T namedElement](items []T, name string) []T {
+	idx := GetNamedElementIndex(items, name)
+
+	if idx == -1 {
+		return items
+	}
+
+	return slices.Concat(items[0:idx], items[(idx+1):])
+}
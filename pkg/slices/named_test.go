@@ -303,3 +303,66 @@ func Test_UpsertNamedElement(t *testing.T) {
 		})
 	}
 }
+
+func Test_RemoveNamedElement(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial []Element
+		remove  string
+		expect  []Element
+	}{
+		{
+			name:    "empty list",
+			initial: []Element{},
+			remove:  "one",
+			expect:  []Element{},
+		},
+
+		{
+			name: "element does not exist",
+			initial: []Element{
+				{
+					name: "one",
+				},
+			},
+			remove: "two",
+			expect: []Element{
+				{
+					name: "one",
+				},
+			},
+		},
+
+		{
+			name: "removes the element",
+			initial: []Element{
+				{
+					name: "one",
+				},
+				{
+					name: "two",
+				},
+				{
+					name: "three",
+				},
+			},
+			remove: "two",
+			expect: []Element{
+				{
+					name: "one",
+				},
+				{
+					name: "three",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			result := slices.RemoveNamedElement(test.initial, test.remove)
+
+			assert.Equal(tt, test.expect, result)
+		})
+	}
+}